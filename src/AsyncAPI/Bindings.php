@@ -0,0 +1,426 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Bindings renders protocol-specific configuration of channels, operations and messages.
+ *
+ * AMQP, Kafka, MQTT and HTTP bindings are resolved into typed values, so that transport adapters
+ * do not need to parse binding objects, bindings of other protocols are skipped.
+ */
+class Bindings extends GoTemplate
+{
+    const CHANNEL = 'Channel';
+    const OPERATION = 'Operation';
+    const MESSAGE = 'Message';
+
+    /**
+     * Go fields of binding properties by kind of binding and protocol.
+     *
+     * Field is defined with name and kind: string, int, bool, strings, value (string constant of schema),
+     * type (reflect.Type of schema) or a pair of structure name and its fields.
+     *
+     * @var array[]
+     */
+    public static $fields = array(
+        self::CHANNEL => array(
+            'amqp' => array(
+                'is' => array('Is', 'string'),
+                'exchange' => array('Exchange', array('AMQPExchange', array(
+                    'name' => array('Name', 'string'),
+                    'type' => array('Type', 'string'),
+                    'durable' => array('Durable', 'bool'),
+                    'autoDelete' => array('AutoDelete', 'bool'),
+                    'vhost' => array('VHost', 'string'),
+                ))),
+                'queue' => array('Queue', array('AMQPQueue', array(
+                    'name' => array('Name', 'string'),
+                    'durable' => array('Durable', 'bool'),
+                    'exclusive' => array('Exclusive', 'bool'),
+                    'autoDelete' => array('AutoDelete', 'bool'),
+                    'vhost' => array('VHost', 'string'),
+                ))),
+            ),
+            'kafka' => array(
+                'topic' => array('Topic', 'string'),
+                'partitions' => array('Partitions', 'int'),
+                'replicas' => array('Replicas', 'int'),
+            ),
+        ),
+        self::OPERATION => array(
+            'amqp' => array(
+                'expiration' => array('Expiration', 'int'),
+                'userId' => array('UserID', 'string'),
+                'cc' => array('CC', 'strings'),
+                'priority' => array('Priority', 'int'),
+                'deliveryMode' => array('DeliveryMode', 'int'),
+                'mandatory' => array('Mandatory', 'bool'),
+                'bcc' => array('BCC', 'strings'),
+                'replyTo' => array('ReplyTo', 'string'),
+                'timestamp' => array('Timestamp', 'bool'),
+                'ack' => array('Ack', 'bool'),
+            ),
+            'kafka' => array(
+                'groupId' => array('GroupID', 'value'),
+                'clientId' => array('ClientID', 'value'),
+            ),
+            'mqtt' => array(
+                'qos' => array('QoS', 'int'),
+                'retain' => array('Retain', 'bool'),
+            ),
+            'http' => array(
+                'type' => array('Type', 'string'),
+                'method' => array('Method', 'string'),
+                'query' => array('Query', 'type'),
+            ),
+        ),
+        self::MESSAGE => array(
+            'amqp' => array(
+                'contentEncoding' => array('ContentEncoding', 'string'),
+                'messageType' => array('MessageType', 'string'),
+            ),
+            'kafka' => array(
+                'key' => array('Key', 'type'),
+            ),
+            'http' => array(
+                'headers' => array('Headers', 'type'),
+            ),
+        ),
+    );
+
+    /** @var Builder */
+    private $builder;
+
+    /**
+     * Bindings constructor.
+     * @param Builder $builder
+     */
+    public function __construct(Builder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    protected function toString()
+    {
+        $code = new Code();
+        $code->imports()->addByName('reflect');
+
+        $code->addSnippet(<<<GO
+// ChannelBindings is a protocol-specific configuration of channel, nil for protocols without binding.
+type ChannelBindings struct {
+	AMQP  *AMQPChannelBinding
+	Kafka *KafkaChannelBinding
+}
+
+// OperationBindings is a protocol-specific configuration of operation, nil for protocols without binding.
+type OperationBindings struct {
+	AMQP  *AMQPOperationBinding
+	Kafka *KafkaOperationBinding
+	MQTT  *MQTTOperationBinding
+	HTTP  *HTTPOperationBinding
+}
+
+// MessageBindings is a protocol-specific configuration of message, nil for protocols without binding.
+type MessageBindings struct {
+	AMQP  *AMQPMessageBinding
+	Kafka *KafkaMessageBinding
+	HTTP  *HTTPMessageBinding
+}
+
+// AMQPChannelBinding describes AMQP 0-9-1 exchange or queue of channel.
+type AMQPChannelBinding struct {
+	// Is is a kind of channel, "routingKey" (default) or "queue".
+	Is string
+
+	// Exchange is declared when channel is a routing key.
+	Exchange *AMQPExchange
+
+	// Queue is declared when channel is a queue.
+	Queue *AMQPQueue
+}
+
+// AMQPExchange describes AMQP exchange declaration.
+type AMQPExchange struct {
+	Name string
+
+	// Type is one of "topic", "direct", "fanout", "default" or "headers".
+	Type string
+
+	Durable    bool
+	AutoDelete bool
+	VHost      string
+}
+
+// AMQPQueue describes AMQP queue declaration.
+type AMQPQueue struct {
+	Name       string
+	Durable    bool
+	Exclusive  bool
+	AutoDelete bool
+	VHost      string
+}
+
+// AMQPOperationBinding describes AMQP properties of operation messages.
+type AMQPOperationBinding struct {
+	// Expiration is a TTL of message in milliseconds.
+	Expiration int64
+
+	UserID string
+	CC     []string
+
+	Priority int64
+
+	// DeliveryMode is 1 for transient and 2 for persistent messages.
+	DeliveryMode int64
+
+	Mandatory bool
+	BCC       []string
+	ReplyTo   string
+	Timestamp bool
+	Ack       bool
+}
+
+// AMQPMessageBinding describes AMQP properties of message.
+type AMQPMessageBinding struct {
+	ContentEncoding string
+	MessageType     string
+}
+
+// KafkaChannelBinding describes Kafka topic of channel.
+type KafkaChannelBinding struct {
+	Topic      string
+	Partitions int64
+	Replicas   int64
+}
+
+// KafkaOperationBinding describes Kafka consumer of operation.
+type KafkaOperationBinding struct {
+	GroupID  string
+	ClientID string
+}
+
+// KafkaMessageBinding describes Kafka message.
+type KafkaMessageBinding struct {
+	// Key is a type of message key.
+	Key reflect.Type
+}
+
+// MQTTOperationBinding describes MQTT delivery of operation messages.
+type MQTTOperationBinding struct {
+	// QoS is a quality of service level: 0, 1 or 2.
+	QoS int64
+
+	Retain bool
+}
+
+// HTTPOperationBinding describes HTTP request of operation.
+type HTTPOperationBinding struct {
+	// Type is "request" or "response".
+	Type string
+
+	Method string
+
+	// Query is a type of query parameters.
+	Query reflect.Type
+}
+
+// HTTPMessageBinding describes HTTP message.
+type HTTPMessageBinding struct {
+	// Headers is a type of HTTP headers.
+	Headers reflect.Type
+}
+
+
+GO
+        );
+
+        foreach ($this->builder->getChannels() as $channel) {
+            $this->renderBindings($channel->name . 'ChannelBindings', self::CHANNEL,
+                'of ' . Literal::quote($channel->address) . ' channel', $channel->bindings, $channel->bindingTypes, $code);
+
+            foreach ($channel->operations as $operation) {
+                $this->renderBindings($operation->name . 'OperationBindings', self::OPERATION,
+                    'of ' . $operation->id . ' operation', $operation->bindings, $operation->bindingTypes, $code);
+            }
+        }
+
+        foreach ($this->builder->getMessages() as $message) {
+            $this->renderBindings($message->name . 'MessageBindings', self::MESSAGE,
+                'of ' . ($message->id !== null ? $message->id : $message->name) . ' message',
+                $message->bindings, $message->bindingTypes, $code);
+        }
+
+        return $code;
+    }
+
+    /**
+     * Adds variable with bindings of supported protocols.
+     *
+     * @param string $name
+     * @param string $kind Channel, Operation or Message
+     * @param string $subject
+     * @param \stdClass[] $bindings binding objects by protocol
+     * @param AnyType[] $types types of binding schemas by protocol and property
+     * @param Code $code
+     */
+    private function renderBindings($name, $kind, $subject, array $bindings, array $types, Code $code)
+    {
+        $elements = array();
+        foreach (self::$fields[$kind] as $protocol => $fields) {
+            if (!isset($bindings[$protocol])) {
+                continue;
+            }
+
+            $protocolName = Builder::$bindingProtocols[$protocol];
+            $elements[$protocolName] = '&' . $this->literal($protocolName . $kind . 'Binding', $fields,
+                    $bindings[$protocol], $types, $protocol . '.', "\t");
+        }
+
+        if (empty($elements)) {
+            return;
+        }
+
+        $code->addSnippet('// ' . $name . ' is a protocol configuration ' . $subject . ".\n"
+            . 'var ' . $name . ' = ' . $kind . 'Bindings{' . "\n" . $this->elements($elements, "\t") . "}\n\n");
+    }
+
+    /**
+     * Returns composite literal of binding object.
+     *
+     * @param string $type
+     * @param array[] $fields
+     * @param \stdClass $data
+     * @param AnyType[] $types
+     * @param string $typePrefix prefix of keys of schema types
+     * @param string $indent
+     * @return string
+     */
+    private function literal($type, array $fields, $data, array $types, $typePrefix, $indent)
+    {
+        $elements = array();
+        foreach ($fields as $property => $field) {
+            list($fieldName, $fieldKind) = $field;
+            if (!isset($data->$property)) {
+                continue;
+            }
+
+            $value = null;
+            if (is_array($fieldKind)) {
+                if ($data->$property instanceof \stdClass) {
+                    $value = '&' . $this->literal($fieldKind[0], $fieldKind[1], $data->$property, array(),
+                            $typePrefix . $property . '.', $indent . "\t");
+                }
+            } else {
+                $value = $this->value($data->$property, $fieldKind, $typePrefix . $property, $types);
+            }
+
+            if ($value !== null) {
+                $elements[$fieldName] = $value;
+            }
+        }
+
+        if (empty($elements)) {
+            return $type . '{}';
+        }
+
+        return $type . "{\n" . $this->elements($elements, $indent . "\t") . $indent . '}';
+    }
+
+    /**
+     * Returns Go expression of binding property value, null if value is not applicable.
+     *
+     * @param mixed $value
+     * @param string $kind
+     * @param string $typeKey
+     * @param AnyType[] $types
+     * @return string|null
+     */
+    private function value($value, $kind, $typeKey, array $types)
+    {
+        switch ($kind) {
+            case 'string':
+                return is_string($value) ? Literal::quote($value) : null;
+            case 'int':
+                return is_int($value) || is_float($value) ? (string)(int)$value : null;
+            case 'bool':
+                return is_bool($value) ? ($value ? 'true' : 'false') : null;
+            case 'strings':
+                if (!is_array($value)) {
+                    return null;
+                }
+                $items = array();
+                foreach ($value as $item) {
+                    $items[] = Literal::quote((string)$item);
+                }
+                return '[]string{' . implode(', ', $items) . '}';
+            case 'value':
+                // Schema defines a single string value with const, default or enum.
+                if (isset($value->const) && is_string($value->const)) {
+                    return Literal::quote($value->const);
+                }
+                if (isset($value->default) && is_string($value->default)) {
+                    return Literal::quote($value->default);
+                }
+                if (isset($value->enum) && count($value->enum) === 1 && is_string($value->enum[0])) {
+                    return Literal::quote($value->enum[0]);
+                }
+                return null;
+            case 'type':
+                if (!isset($types[$typeKey])) {
+                    return null;
+                }
+                return 'reflect.TypeOf((*' . $types[$typeKey]->render() . ')(nil)).Elem()';
+        }
+
+        return null;
+    }
+
+    /**
+     * Renders keyed elements of composite literal, single-line elements are aligned like gofmt does.
+     *
+     * @param string[] $elements Go expressions by keys
+     * @param string $indent
+     * @return string
+     */
+    private function elements(array $elements, $indent)
+    {
+        $result = '';
+        $section = array();
+        foreach ($elements as $key => $value) {
+            if (false === strpos($value, "\n")) {
+                $section[$key] = $value;
+                continue;
+            }
+
+            $result .= $this->section($section, $indent) . $indent . $key . ': ' . $value . ",\n";
+            $section = array();
+        }
+
+        return $result . $this->section($section, $indent);
+    }
+
+    /**
+     * @param string[] $elements
+     * @param string $indent
+     * @return string
+     */
+    private function section(array $elements, $indent)
+    {
+        $width = 0;
+        foreach ($elements as $key => $value) {
+            $width = max($width, strlen($key) + 1);
+        }
+
+        $result = '';
+        foreach ($elements as $key => $value) {
+            $result .= $indent . str_pad($key . ':', $width) . ' ' . $value . ",\n";
+        }
+
+        return $result;
+    }
+}
\ No newline at end of file
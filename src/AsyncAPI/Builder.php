@@ -0,0 +1,530 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\Registry;
+use Swaggest\GoCodeBuilder\OpenAPI3\Builder as OpenAPI3Builder;
+use Swaggest\GoCodeBuilder\OpenAPI3\ParamCodec;
+use Swaggest\GoCodeBuilder\OpenAPI3\PathToNameHook;
+use Swaggest\GoCodeBuilder\Style\Comment;
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\JsonSchema\Context;
+use Swaggest\JsonSchema\RemoteRef\Preloaded;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder walks channels of AsyncAPI 2 document and builds channel names and message structures.
+ *
+ * Schemas of parameters, headers and payloads are processed with GoBuilder.
+ */
+class Builder
+{
+    /** Document URL for schema references. */
+    const DOCUMENT_URL = 'asyncapi.json';
+
+    /** @var string[] operations of channel item in order of specification */
+    public static $actions = array(Operation::PUBLISH, Operation::SUBSCRIBE);
+
+    /** @var string[] names of arguments used by generated methods */
+    public static $reservedArgs = array('c', 'ctx', 'err', 'handle', 'm', 'msg');
+
+    /** @var string[] Go names of protocols with supported bindings */
+    public static $bindingProtocols = array('amqp' => 'AMQP', 'kafka' => 'Kafka', 'mqtt' => 'MQTT', 'http' => 'HTTP');
+
+    /** @var string[][] binding properties that contain schemas by protocol */
+    public static $bindingSchemas = array('kafka' => array('key'), 'http' => array('query', 'headers'));
+
+    /** @var GoBuilder */
+    public $goBuilder;
+
+    /** @var PathToNameHook */
+    public $pathToNameHook;
+
+    /** @var \stdClass */
+    private $document;
+
+    /** @var Context */
+    private $context;
+
+    /** @var Channel[] */
+    private $channels = array();
+
+    /** @var Operation[] */
+    private $operations = array();
+
+    /** @var Message[] by JSON pointer */
+    private $messages = array();
+
+    /** @var bool[] */
+    private $names = array();
+
+    /**
+     * Builder constructor.
+     * @param \stdClass $document decoded AsyncAPI 2 document
+     * @param GoBuilder|null $goBuilder
+     */
+    public function __construct($document, GoBuilder $goBuilder = null)
+    {
+        if ($goBuilder === null) {
+            $goBuilder = new GoBuilder();
+            $goBuilder->options->enableXNullable = true;
+        }
+
+        $this->document = $document;
+        $this->goBuilder = $goBuilder;
+
+        $preloaded = new Preloaded();
+        $preloaded->setSchemaData(static::DOCUMENT_URL, $document);
+        $this->context = new Context($preloaded);
+
+        $this->pathToNameHook = new PathToNameHook($goBuilder->pathToNameHook);
+        $goBuilder->pathToNameHook = $this->pathToNameHook;
+    }
+
+    /**
+     * @return \stdClass
+     */
+    public function getDocument()
+    {
+        return $this->document;
+    }
+
+    /**
+     * @return Channel[]
+     */
+    public function getChannels()
+    {
+        return $this->channels;
+    }
+
+    /**
+     * @return Operation[]
+     */
+    public function getOperations()
+    {
+        return $this->operations;
+    }
+
+    /**
+     * @return Message[]
+     */
+    public function getMessages()
+    {
+        return array_values($this->messages);
+    }
+
+    /**
+     * Adds payload types of messages to registry by message name or component key.
+     *
+     * @param Registry $registry
+     * @return $this
+     */
+    public function register(Registry $registry)
+    {
+        foreach ($this->messages as $message) {
+            if ($message->payloadType !== null) {
+                $registry->add($message->identifier(), $message->payloadType);
+            }
+        }
+
+        return $this;
+    }
+
+    /**
+     * Returns API title from document info.
+     *
+     * @return string|null
+     */
+    public function getTitle()
+    {
+        if (isset($this->document->info->title)) {
+            return $this->document->info->title;
+        }
+        return null;
+    }
+
+    /**
+     * Returns code with channel names and message structures.
+     *
+     * @return Code
+     */
+    public function getCode()
+    {
+        $code = new Code();
+        foreach ($this->channels as $channel) {
+            $this->renderChannel($channel, $code);
+        }
+        foreach ($this->messages as $message) {
+            $code->addSnippet($message->structDef);
+        }
+
+        return $code;
+    }
+
+    /**
+     * Builds all channels.
+     *
+     * @return $this
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function build()
+    {
+        if (!isset($this->document->channels)) {
+            return $this;
+        }
+
+        foreach ($this->document->channels as $address => $channelItem) {
+            $pointer = '#/channels/' . OpenAPI3Builder::escapePointer($address);
+            list($channelItem, $pointer) = $this->resolve($channelItem, $pointer);
+
+            $channel = $this->buildChannel($address, $channelItem, $pointer);
+            $this->channels[] = $channel;
+
+            foreach (static::$actions as $action) {
+                if (!isset($channelItem->$action)) {
+                    continue;
+                }
+
+                $operation = $this->buildOperation($channel, $action, $channelItem->$action, $pointer . '/' . $action);
+                $channel->operations[] = $operation;
+                $this->operations[] = $operation;
+            }
+        }
+
+        return $this;
+    }
+
+    /**
+     * @param string $address
+     * @param \stdClass $data
+     * @param string $pointer
+     * @return Channel
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildChannel($address, $data, $pointer)
+    {
+        $channel = new Channel();
+        $channel->address = $address;
+        if (isset($data->description)) {
+            $channel->description = $data->description;
+        }
+
+        $name = isset($data->{'x-go-name'}) ? $data->{'x-go-name'}
+            : $this->goBuilder->codeBuilder->exportableName(preg_replace('/\{[^}]+\}/', '', $address));
+        $channel->name = $this->uniqueName($name, 'Channel');
+
+        preg_match_all('/\{([^}]+)\}/', $address, $matches);
+        foreach ($matches[1] as $paramName) {
+            if (!isset($data->parameters->$paramName)) {
+                throw new Exception('Missing parameter "' . $paramName . '" of channel ' . $address);
+            }
+
+            list($paramData, $paramPointer) = $this->resolve($data->parameters->$paramName,
+                $pointer . '/parameters/' . OpenAPI3Builder::escapePointer($paramName));
+
+            $parameter = new Parameter();
+            $parameter->name = $paramName;
+            if (isset($paramData->description)) {
+                $parameter->description = $paramData->description;
+            }
+
+            $parameter->argName = $this->goBuilder->codeBuilder->privateName($paramName);
+            if (in_array($parameter->argName, static::$reservedArgs, true)) {
+                $parameter->argName .= 'Param';
+            }
+
+            if (isset($paramData->schema)) {
+                $schemaPointer = $paramPointer . '/schema';
+                $this->pathToNameHook->names[$schemaPointer] = $channel->name
+                    . $this->goBuilder->codeBuilder->exportableName($paramName);
+                $parameter->type = $this->getType($schemaPointer);
+            } else {
+                $parameter->type = new Type('string');
+            }
+
+            $channel->parameters[] = $parameter;
+        }
+
+        $channel->bindings = $this->buildBindings($data, $pointer, $channel->name, $channel->bindingTypes);
+
+        return $channel;
+    }
+
+    /**
+     * @param Channel $channel
+     * @param string $action
+     * @param \stdClass $data
+     * @param string $pointer
+     * @return Operation
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildOperation(Channel $channel, $action, $data, $pointer)
+    {
+        $operation = new Operation();
+        $operation->action = $action;
+        $operation->channel = $channel;
+        $operation->id = isset($data->operationId) ? $data->operationId : $action . ' ' . $channel->address;
+        $operation->name = $this->uniqueName(isset($data->operationId)
+            ? $this->goBuilder->codeBuilder->exportableName($data->operationId)
+            : $this->goBuilder->codeBuilder->exportableName($action) . $channel->name, 'Operation');
+        if (isset($data->summary)) {
+            $operation->summary = $data->summary;
+        }
+        if (isset($data->description)) {
+            $operation->description = $data->description;
+        }
+
+        $operation->bindings = $this->buildBindings($data, $pointer, $operation->name, $operation->bindingTypes);
+
+        if (isset($data->message)) {
+            list($messageData, $messagePointer) = $this->resolve($data->message, $pointer . '/message');
+            if (isset($messageData->oneOf)) {
+                foreach ($messageData->oneOf as $i => $item) {
+                    $operation->messages[] = $this->getMessage($item, $messagePointer . '/oneOf/' . $i,
+                        $operation->name . ($i + 1));
+                }
+            } else {
+                $operation->messages[] = $this->getMessage($messageData, $messagePointer, $operation->name);
+            }
+        }
+
+        return $operation;
+    }
+
+    /**
+     * Returns message with structure, messages referenced by several operations are built once.
+     *
+     * @param \stdClass $data
+     * @param string $pointer
+     * @param string $defaultName name of inline message without name
+     * @return Message
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function getMessage($data, $pointer, $defaultName)
+    {
+        list($data, $pointer) = $this->resolve($data, $pointer);
+        if (isset($this->messages[$pointer])) {
+            return $this->messages[$pointer];
+        }
+
+        $message = new Message();
+        $message->pointer = $pointer;
+        if (isset($data->name)) {
+            $message->id = $data->name;
+        } elseif (0 === strpos($pointer, '#/components/messages/')) {
+            $message->id = substr($pointer, strlen('#/components/messages/'));
+        }
+        $message->name = $this->uniqueName($message->id !== null
+            ? $this->goBuilder->codeBuilder->exportableName($message->id) : $defaultName, 'Message');
+
+        foreach (array('title', 'summary', 'description', 'contentType') as $property) {
+            if (isset($data->$property)) {
+                $message->$property = $data->$property;
+            }
+        }
+        if ($message->contentType === null) {
+            $message->contentType = isset($this->document->defaultContentType)
+                ? $this->document->defaultContentType : 'application/json';
+        }
+
+        $this->messages[$pointer] = $message;
+
+        if (isset($data->headers)) {
+            $this->pathToNameHook->names[$pointer . '/headers'] = $message->name . 'Headers';
+            $message->headersType = $this->getType($pointer . '/headers');
+        }
+        if (isset($data->payload)) {
+            $this->pathToNameHook->names[$pointer . '/payload'] = $message->name . 'Payload';
+            $message->payloadType = $this->getType($pointer . '/payload');
+        }
+
+        $message->bindings = $this->buildBindings($data, $pointer, $message->name, $message->bindingTypes);
+
+        $title = $message->title ? $message->title : $message->identifier();
+        $comment = $message->name . 'Message is a "' . $title . '" message.';
+        if ($message->summary) {
+            $comment .= "\n\n" . Comment::sentence($message->summary);
+        }
+        if ($message->description && false === strpos(trim($message->description), "\n")) {
+            $comment .= "\n\n" . Comment::sentence($message->description);
+        }
+        $message->structDef = new StructDef($message->name . 'Message', $comment);
+        if ($message->headersType !== null) {
+            $message->structDef->addProperty(new StructProperty('Headers', $message->headersType));
+        }
+        if ($message->payloadType !== null) {
+            $message->structDef->addProperty(new StructProperty('Payload', $message->payloadType));
+        }
+
+        return $message;
+    }
+
+    /**
+     * Resolves binding objects of supported protocols and builds types of their schemas.
+     *
+     * @param \stdClass $data channel, operation or message
+     * @param string $pointer
+     * @param string $name Go name of channel, operation or message to prefix names of binding types
+     * @param AnyType[] $types receives types of binding schemas by protocol and property
+     * @return \stdClass[] binding objects by protocol
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildBindings($data, $pointer, $name, array &$types)
+    {
+        $bindings = array();
+        if (!isset($data->bindings)) {
+            return $bindings;
+        }
+
+        list($bindingsData, $pointer) = $this->resolve($data->bindings, $pointer . '/bindings');
+        foreach (static::$bindingProtocols as $protocol => $protocolName) {
+            if (!isset($bindingsData->$protocol)) {
+                continue;
+            }
+
+            list($binding, $bindingPointer) = $this->resolve($bindingsData->$protocol, $pointer . '/' . $protocol);
+            $bindings[$protocol] = $binding;
+
+            if (!isset(static::$bindingSchemas[$protocol])) {
+                continue;
+            }
+
+            foreach (static::$bindingSchemas[$protocol] as $property) {
+                if (!isset($binding->$property)) {
+                    continue;
+                }
+
+                $schemaPointer = $bindingPointer . '/' . $property;
+                $this->pathToNameHook->names[$schemaPointer] = $name . $protocolName
+                    . $this->goBuilder->codeBuilder->exportableName($property);
+                $types[$protocol . '.' . $property] = $this->getType($schemaPointer);
+            }
+        }
+
+        return $bindings;
+    }
+
+    /**
+     * Adds channel name constant and function that substitutes parameters.
+     *
+     * @param Channel $channel
+     * @param Code $code
+     */
+    private function renderChannel(Channel $channel, Code $code)
+    {
+        $address = Literal::quote($channel->address);
+        $comment = $channel->constName() . ' is a name ' . (empty($channel->parameters) ? '' : 'template ')
+            . 'of ' . $address . ' channel.';
+        if ($channel->description && false === strpos(trim($channel->description), "\n")) {
+            $comment .= "\n\n" . Comment::sentence($channel->description);
+        }
+        $lines = array();
+        foreach (explode("\n", $comment) as $line) {
+            $lines[] = rtrim('// ' . $line);
+        }
+        $code->addSnippet(implode("\n", $lines) . "\n" . 'const ' . $channel->constName() . ' = ' . $address . "\n\n");
+
+        if (empty($channel->parameters)) {
+            return;
+        }
+
+        $body = new Code();
+        $arguments = new Arguments();
+        $parameters = array();
+        foreach ($channel->parameters as $parameter) {
+            $arguments->add($parameter->argName, $parameter->type);
+            $parameters[$parameter->name] = $parameter;
+        }
+
+        $parts = array();
+        foreach (preg_split('/(\{[^}]+\})/', $channel->address, -1, PREG_SPLIT_DELIM_CAPTURE | PREG_SPLIT_NO_EMPTY) as $part) {
+            $name = substr($part, 1, -1);
+            if ($part[0] === '{' && isset($parameters[$name])) {
+                $parts[] = ParamCodec::format($parameters[$name]->argName, $parameters[$name]->type, $body);
+            } else {
+                $parts[] = Literal::quote($part);
+            }
+        }
+        $body->addSnippet('return ' . implode(' + ', $parts));
+
+        $func = new FuncDef($channel->funcName(),
+            $channel->funcName() . ' returns name of ' . $address . ' channel with parameters.');
+        $func->setArguments($arguments);
+        $func->setResult((new Result())->add(null, new Type('string')));
+        $func->setBody($body);
+        $code->addSnippet($func);
+    }
+
+    /**
+     * Returns Go type of schema located in document.
+     *
+     * @param string $pointer JSON pointer of schema, e.g. #/components/schemas/User
+     * @return AnyType
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function getType($pointer)
+    {
+        $schema = Schema::import((object)array(Schema::PROP_REF => static::DOCUMENT_URL . $pointer), $this->context);
+
+        return $this->goBuilder->getType($schema, $pointer);
+    }
+
+    /**
+     * Follows local references of document.
+     *
+     * @param mixed $value
+     * @param string $pointer
+     * @return array pair of resolved value and its pointer
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     */
+    public function resolve($value, $pointer)
+    {
+        return OpenAPI3Builder::resolveRef($this->document, $value, $pointer);
+    }
+
+    /**
+     * @param string $name
+     * @param string $suffix suffix of generated Go symbol to check for conflicts
+     * @return string
+     */
+    private function uniqueName($name, $suffix = '')
+    {
+        $preferredName = $name;
+        $i = 2;
+        while (isset($this->names[$name . $suffix])) {
+            $name = $preferredName . $i;
+            $i++;
+        }
+        $this->names[$name . $suffix] = true;
+
+        return $name;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Channel describes channel with its name template, parameters and operations.
+ */
+class Channel
+{
+    /** @var string channel name template, e.g. user/{userId}/signup */
+    public $address;
+
+    /** @var string exported Go name of channel */
+    public $name;
+
+    /** @var string */
+    public $description;
+
+    /** @var Parameter[] parameters in order of name template */
+    public $parameters = array();
+
+    /** @var Operation[] */
+    public $operations = array();
+
+    /** @var \stdClass[] binding objects by protocol */
+    public $bindings = array();
+
+    /** @var AnyType[] types of binding schemas by protocol and property, e.g. kafka.key */
+    public $bindingTypes = array();
+
+    /**
+     * Returns name of constant with channel name template.
+     *
+     * @return string
+     */
+    public function constName()
+    {
+        return $this->name . 'Channel';
+    }
+
+    /**
+     * Returns name of function that substitutes parameters into channel name.
+     *
+     * @return string
+     */
+    public function funcName()
+    {
+        return $this->name . 'ChannelName';
+    }
+
+    /**
+     * Returns Go expression of channel name with arguments of parameters.
+     *
+     * @return string
+     */
+    public function nameExpr()
+    {
+        if (empty($this->parameters)) {
+            return $this->constName();
+        }
+
+        $args = array();
+        foreach ($this->parameters as $parameter) {
+            $args[] = $parameter->argName;
+        }
+
+        return $this->funcName() . '(' . implode(', ', $args) . ')';
+    }
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+
+class Exception extends \Exception
+{
+
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Message describes channel message and its structure with headers and payload.
+ */
+class Message
+{
+    /** @var string JSON pointer of message in document */
+    public $pointer;
+
+    /** @var string message name or component key */
+    public $id;
+
+    /** @var string exported Go name of message */
+    public $name;
+
+    /** @var string */
+    public $title;
+
+    /** @var string */
+    public $summary;
+
+    /** @var string */
+    public $description;
+
+    /** @var string */
+    public $contentType;
+
+    /** @var AnyType|null */
+    public $headersType;
+
+    /** @var AnyType|null */
+    public $payloadType;
+
+    /** @var StructDef */
+    public $structDef;
+
+    /** @var \stdClass[] binding objects by protocol */
+    public $bindings = array();
+
+    /** @var AnyType[] types of binding schemas by protocol and property, e.g. kafka.key */
+    public $bindingTypes = array();
+
+    /**
+     * Returns message identifier for envelope and registry, message name or component key or Go name.
+     *
+     * @return string
+     */
+    public function identifier()
+    {
+        return $this->id !== null ? $this->id : $this->name;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,530 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\JsonSchema\Registry;
+use Swaggest\GoCodeBuilder\Style\Comment;
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Argument;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceDef;
+use Swaggest\GoCodeBuilder\Templates\Type\FuncType;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+
+/**
+ * Messaging renders transport-agnostic message envelope, message codecs, publisher and subscriber interfaces
+ * of operations and their implementation with a broker.
+ *
+ * In-memory broker is rendered for tests. With registry, operations with several messages get typed dispatchers
+ * of envelopes.
+ */
+class Messaging extends GoTemplate
+{
+    /** @var Builder */
+    private $builder;
+
+    /** @var string */
+    private $clientName = 'BrokerClient';
+
+    /** @var Registry|null */
+    private $registry;
+
+    /**
+     * Messaging constructor.
+     * @param Builder $builder
+     */
+    public function __construct(Builder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    /**
+     * @param string $clientName
+     * @return Messaging
+     */
+    public function setClientName($clientName)
+    {
+        $this->clientName = $clientName;
+        return $this;
+    }
+
+    /**
+     * Enables typed dispatchers (e.g. AuditHandlers) of operations with several messages.
+     *
+     * Payloads are decoded with registry, messages should be added to it with Builder::register.
+     *
+     * @param Registry $registry
+     * @return Messaging
+     */
+    public function setRegistry(Registry $registry)
+    {
+        $this->registry = $registry;
+        return $this;
+    }
+
+    /**
+     * Returns publisher and subscriber interfaces of operations, they can also be used to render mocks.
+     *
+     * @return IfaceDef[]
+     */
+    public function getIfaces()
+    {
+        $ifaces = array();
+        foreach ($this->builder->getOperations() as $operation) {
+            if (empty($operation->messages)) {
+                continue;
+            }
+
+            $publisher = new IfaceDef($operation->name . 'Publisher',
+                $operation->name . 'Publisher publishes messages of ' . $operation->id . ' operation.');
+            foreach (self::publishFuncs($operation) as $func) {
+                $publisher->addFunc($func);
+            }
+            $ifaces[] = $publisher;
+
+            $subscriber = new IfaceDef($operation->name . 'Subscriber',
+                $operation->name . 'Subscriber receives messages of ' . $operation->id . ' operation.');
+            $subscriber->addFunc(self::subscribeFunc($operation, $this->dispatcherName($operation)));
+            $ifaces[] = $subscriber;
+        }
+
+        return $ifaces;
+    }
+
+    /**
+     * Returns methods that publish messages of operation, operation with several messages has a method per message.
+     *
+     * @param Operation $operation
+     * @return FuncDef[] by message name
+     */
+    public static function publishFuncs(Operation $operation)
+    {
+        $funcs = array();
+        foreach ($operation->messages as $message) {
+            $name = 'Publish' . $operation->name;
+            if (count($operation->messages) > 1) {
+                $name .= $message->name;
+            }
+
+            $func = new FuncDef($name, $name . ' publishes ' . $message->name . 'Message to '
+                . Literal::quote($operation->channel->address) . ' channel.' . self::summary($operation));
+            $func->setArguments(self::channelArguments($operation->channel)
+                ->add('msg', new Type($message->name . 'Message')));
+            $func->setResult((new Result())->add(null, new Type('error')));
+            $funcs[$message->name] = $func;
+        }
+
+        return $funcs;
+    }
+
+    /**
+     * Returns method that subscribes to messages of operation.
+     *
+     * Operation with several messages provides envelopes to handler.
+     *
+     * @param Operation $operation
+     * @param string|null $dispatcherName name of typed dispatcher of envelopes to mention in comment
+     * @return FuncDef
+     */
+    public static function subscribeFunc(Operation $operation, $dispatcherName = null)
+    {
+        $name = 'Subscribe' . $operation->name;
+        $comment = $name . ' subscribes to messages of ' . Literal::quote($operation->channel->address)
+            . ' channel.' . self::summary($operation);
+
+        if (count($operation->messages) === 1) {
+            $handle = new FuncDef('handle');
+            $handle->setArguments((new Arguments())
+                ->add(null, TypeUtil::fromString('context.Context'))
+                ->add(null, new Type($operation->messages[0]->name . 'Message')));
+            $handle->setResult((new Result())->add(null, new Type('error')));
+            $handleType = new FuncType($handle);
+        } else {
+            $names = array();
+            foreach ($operation->messages as $message) {
+                $names[] = $message->name . 'Message';
+            }
+            $comment .= "\n\nHandler receives envelopes of " . implode(', ', $names) . '.';
+            if ($dispatcherName !== null) {
+                $comment .= ' Use ' . $dispatcherName . '.Handle to receive typed messages.';
+            }
+            $handleType = new Type('MessageHandler');
+        }
+
+        $func = new FuncDef($name, $comment);
+        $func->setArguments(self::channelArguments($operation->channel)->add('handle', $handleType));
+        $func->setResult((new Result())->add(null, new Type('error')));
+
+        return $func;
+    }
+
+    /**
+     * @param Channel $channel
+     * @return Arguments
+     */
+    private static function channelArguments(Channel $channel)
+    {
+        $arguments = (new Arguments())->add('ctx', TypeUtil::fromString('context.Context'));
+        foreach ($channel->parameters as $parameter) {
+            $arguments->add($parameter->argName, $parameter->type);
+        }
+
+        return $arguments;
+    }
+
+    /**
+     * Returns name of typed dispatcher of operation envelopes or null if it is not rendered.
+     *
+     * @param Operation $operation
+     * @return string|null
+     */
+    private function dispatcherName(Operation $operation)
+    {
+        if ($this->registry === null || count($operation->messages) < 2) {
+            return null;
+        }
+
+        return $operation->name . 'Handlers';
+    }
+
+    /**
+     * @param Operation $operation
+     * @return string
+     */
+    private static function summary(Operation $operation)
+    {
+        if ($operation->summary) {
+            return "\n\n" . Comment::sentence($operation->summary);
+        }
+        return '';
+    }
+
+    protected function toString()
+    {
+        $code = new Code();
+        $code->imports()->addByName('context');
+        $code->imports()->addByName('sync');
+
+        $code->addSnippet(<<<GO
+// Message is a transport-agnostic envelope of channel message.
+type Message struct {
+	// Channel is a name of channel with parameters.
+	Channel string
+
+	// Name identifies message, e.g. in Registry.
+	Name string
+
+	// ContentType is a media type of payload.
+	ContentType string
+
+	// Headers is a JSON object of message headers.
+	Headers []byte
+
+	// Payload is an encoded message payload.
+	Payload []byte
+}
+
+// MessageHandler handles received message.
+type MessageHandler func(ctx context.Context, msg Message) error
+
+// Broker delivers messages to channel subscribers.
+type Broker interface {
+	// Publish sends message to its channel.
+	Publish(ctx context.Context, msg Message) error
+
+	// Subscribe registers handler of channel messages.
+	Subscribe(ctx context.Context, channel string, handle MessageHandler) error
+}
+
+// MemoryBroker is an in-memory implementation of Broker for tests, messages are delivered synchronously.
+type MemoryBroker struct {
+	mu       sync.Mutex
+	handlers map[string][]MessageHandler
+}
+
+// Publish calls handlers of message channel.
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	handlers := b.handlers[msg.Channel]
+	b.mu.Unlock()
+
+	for _, handle := range handlers {
+		if err := handle(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribe adds handler of channel messages.
+func (b *MemoryBroker) Subscribe(_ context.Context, channel string, handle MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.handlers == nil {
+		b.handlers = make(map[string][]MessageHandler)
+	}
+
+	b.handlers[channel] = append(b.handlers[channel], handle)
+
+	return nil
+}
+
+
+GO
+        );
+
+        foreach ($this->builder->getMessages() as $message) {
+            $this->renderCodec($message, $code);
+        }
+
+        foreach ($this->getIfaces() as $iface) {
+            $code->addSnippet($iface);
+        }
+
+        $code->addSnippet(<<<GO
+// {$this->clientName} implements publishers and subscribers of operations with Broker.
+type {$this->clientName} struct {
+	Broker Broker
+}
+
+
+GO
+        );
+
+        foreach ($this->builder->getOperations() as $operation) {
+            if (empty($operation->messages)) {
+                continue;
+            }
+
+            foreach (self::publishFuncs($operation) as $func) {
+                $code->addSnippet($this->publishMethod($operation, $func));
+            }
+            $code->addSnippet($this->subscribeMethod($operation));
+        }
+
+        foreach ($this->builder->getOperations() as $operation) {
+            if (null !== $dispatcherName = $this->dispatcherName($operation)) {
+                $this->renderDispatcher($operation, $dispatcherName, $code);
+            }
+        }
+
+        return $code;
+    }
+
+    /**
+     * Renders methods that convert message structure to and from envelope.
+     *
+     * @param Message $message
+     * @param Code $code
+     */
+    private function renderCodec(Message $message, Code $code)
+    {
+        $code->imports()->addByName('encoding/json');
+        $type = $message->name . 'Message';
+        $contentType = Literal::quote($message->contentType);
+        $id = Literal::quote($message->identifier());
+
+        $encode = '';
+        $decode = '';
+        foreach (array('Payload' => $message->payloadType, 'Headers' => $message->headersType) as $field => $fieldType) {
+            if ($fieldType === null) {
+                continue;
+            }
+
+            $var = strtolower($field);
+            $encode .= <<<GO
+	{$var}, err := json.Marshal(m.{$field})
+	if err != nil {
+		return msg, err
+	}
+
+	msg.{$field} = {$var}
+
+
+GO;
+            $decode .= <<<GO
+	if len(msg.{$field}) > 0 {
+		if err := json.Unmarshal(msg.{$field}, &m.{$field}); err != nil {
+			return err
+		}
+	}
+
+
+GO;
+        }
+
+        $code->addSnippet(<<<GO
+// ToMessage encodes {$type} into envelope of channel.
+func (m {$type}) ToMessage(channel string) (Message, error) {
+	msg := Message{Channel: channel, Name: {$id}, ContentType: {$contentType}}
+
+{$encode}	return msg, nil
+}
+
+// FromMessage decodes {$type} from envelope.
+func (m *{$type}) FromMessage(msg Message) error {
+{$decode}	return nil
+}
+
+
+GO
+        );
+    }
+
+    /**
+     * Renders structure with handler per message of operation that dispatches envelopes by message name.
+     *
+     * @param Operation $operation
+     * @param string $name
+     * @param Code $code
+     */
+    private function renderDispatcher(Operation $operation, $name, Code $code)
+    {
+        $code->imports()->addByName('fmt');
+        $decode = $this->registry->getDecodeName();
+
+        $width = 0;
+        foreach ($operation->messages as $message) {
+            $width = max($width, strlen($message->name));
+        }
+
+        $fields = '';
+        $cases = '';
+        foreach ($operation->messages as $message) {
+            $type = $message->name . 'Message';
+            $id = Literal::quote($message->identifier());
+            $field = str_pad($message->name, $width);
+            $fields .= <<<GO
+	{$field} func(ctx context.Context, msg {$type}) error
+
+GO;
+
+            if ($message->payloadType === null) {
+                $decodeMessage = <<<GO
+		var m {$type}
+		if err := m.FromMessage(msg); err != nil {
+			return err
+		}
+
+GO;
+            } else {
+                $decodeMessage = <<<GO
+		payload, err := {$decode}(msg.Name, msg.Payload)
+		if err != nil {
+			return err
+		}
+
+		p, ok := payload.(*{$message->payloadType->render()})
+		if !ok {
+			return fmt.Errorf("unexpected payload %T for %s", payload, msg.Name)
+		}
+
+		m := {$type}{Payload: *p}
+
+GO;
+                if ($message->headersType !== null) {
+                    $code->imports()->addByName('encoding/json');
+                    $decodeMessage .= <<<GO
+
+		if len(msg.Headers) > 0 {
+			if err := json.Unmarshal(msg.Headers, &m.Headers); err != nil {
+				return err
+			}
+		}
+
+GO;
+                }
+            }
+
+            $cases .= <<<GO
+	case {$id}:
+		if h.{$message->name} == nil {
+			return nil
+		}
+
+{$decodeMessage}
+		return h.{$message->name}(ctx, m)
+
+GO;
+        }
+
+        $channel = Literal::quote($operation->channel->address);
+        $code->addSnippet(<<<GO
+// {$name} dispatches envelopes of {$channel} channel to handlers of typed messages by message name.
+//
+// Messages without handler are skipped, payloads are decoded with {$decode}.
+type {$name} struct {
+{$fields}}
+
+// Handle implements MessageHandler.
+func (h {$name}) Handle(ctx context.Context, msg Message) error {
+	switch msg.Name {
+{$cases}	}
+
+	return fmt.Errorf("unexpected message %q on %s channel", msg.Name, msg.Channel)
+}
+
+
+GO
+        );
+    }
+
+    /**
+     * @param Operation $operation
+     * @param FuncDef $func
+     * @return FuncDef
+     */
+    private function publishMethod(Operation $operation, FuncDef $func)
+    {
+        $func->setSelf(new Argument('c', new Type($this->clientName)));
+        $func->setBody(new Code(<<<GO
+m, err := msg.ToMessage({$operation->channel->nameExpr()})
+if err != nil {
+	return err
+}
+
+return c.Broker.Publish(ctx, m)
+GO
+        ));
+
+        return $func;
+    }
+
+    /**
+     * @param Operation $operation
+     * @return FuncDef
+     */
+    private function subscribeMethod(Operation $operation)
+    {
+        $func = self::subscribeFunc($operation, $this->dispatcherName($operation));
+        $func->setSelf(new Argument('c', new Type($this->clientName)));
+
+        $channel = $operation->channel->nameExpr();
+        if (count($operation->messages) > 1) {
+            $func->setBody(new Code("return c.Broker.Subscribe(ctx, {$channel}, handle)"));
+            return $func;
+        }
+
+        $type = $operation->messages[0]->name . 'Message';
+        $func->setBody(new Code(<<<GO
+return c.Broker.Subscribe(ctx, {$channel}, func(ctx context.Context, m Message) error {
+	var msg {$type}
+	if err := msg.FromMessage(m); err != nil {
+		return err
+	}
+
+	return handle(ctx, msg)
+})
+GO
+        ));
+
+        return $func;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Operation describes publish or subscribe operation of channel.
+ */
+class Operation
+{
+    const PUBLISH = 'publish';
+    const SUBSCRIBE = 'subscribe';
+
+    /** @var string publish or subscribe */
+    public $action;
+
+    /** @var string operationId or name derived from channel and action */
+    public $id;
+
+    /** @var string exported Go name of operation */
+    public $name;
+
+    /** @var string */
+    public $summary;
+
+    /** @var string */
+    public $description;
+
+    /** @var Channel */
+    public $channel;
+
+    /** @var Message[] */
+    public $messages = array();
+
+    /** @var \stdClass[] binding objects by protocol */
+    public $bindings = array();
+
+    /** @var AnyType[] types of binding schemas by protocol and property, e.g. kafka.key */
+    public $bindingTypes = array();
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\AsyncAPI;
+
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Parameter describes channel name parameter and its argument.
+ */
+class Parameter
+{
+    /** @var string name of parameter in channel name template */
+    public $name;
+
+    /** @var string */
+    public $description;
+
+    /** @var string name of Go argument */
+    public $argName;
+
+    /** @var AnyType */
+    public $type;
+}
\ No newline at end of file
@@ -0,0 +1,435 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Avro;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\TypeBuilder;
+use Swaggest\GoCodeBuilder\OpenAPI3\PathToNameHook;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\JsonSchema\Context;
+use Swaggest\JsonSchema\RemoteRef\Preloaded;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder translates Avro schema into JSON Schema and builds types of named schemas with GoBuilder.
+ *
+ * Named schemas (records, enums, fixed) are added to definitions by full name and get Go names of their short names,
+ * unions with "null" are nullable. Renames of aliases in Options::renames apply to named schemas.
+ */
+class Builder
+{
+    /** Document URL for schema references. */
+    const DOCUMENT_URL = 'avro.json';
+
+    const DEFINITIONS = 'definitions';
+
+    /** @var array[] JSON Schema of primitive types */
+    public static $primitives = array(
+        'null' => array('type' => Schema::NULL),
+        'boolean' => array('type' => Schema::BOOLEAN),
+        'int' => array(
+            'type' => Schema::INTEGER,
+            'minimum' => -2147483648,
+            'maximum' => 2147483647,
+            TypeBuilder::X_GO_TYPE => 'int32',
+        ),
+        'long' => array('type' => Schema::INTEGER),
+        'float' => array('type' => Schema::NUMBER, TypeBuilder::X_GO_TYPE => 'float32'),
+        'double' => array('type' => Schema::NUMBER),
+        'bytes' => array('type' => Schema::STRING),
+        'string' => array('type' => Schema::STRING),
+    );
+
+    /**
+     * JSON Schema formats of logical types by underlying types.
+     *
+     * Other logical types are encoded in JSON as their underlying types,
+     * e.g. "timestamp-millis" is a long number and "decimal" is a string of bytes.
+     *
+     * @var string[][]
+     */
+    public static $logicalTypes = array(
+        'uuid' => array('string' => 'uuid'),
+    );
+
+    /** @var GoBuilder */
+    public $goBuilder;
+
+    /** @var PathToNameHook */
+    public $pathToNameHook;
+
+    /** @var mixed */
+    private $document;
+
+    /** @var \stdClass translated JSON Schema document */
+    private $schemaData;
+
+    /** @var Context */
+    private $context;
+
+    /** @var string[] full names of named schemas by full names and aliases */
+    private $names = array();
+
+    /**
+     * Builder constructor.
+     * @param mixed $document decoded Avro schema, a list of schemas is a union
+     * @param GoBuilder|null $goBuilder
+     * @throws Exception
+     */
+    public function __construct($document, GoBuilder $goBuilder = null)
+    {
+        if ($goBuilder === null) {
+            $goBuilder = new GoBuilder();
+            $goBuilder->options->enableXNullable = true;
+        }
+
+        $this->document = $document;
+        $this->goBuilder = $goBuilder;
+        $this->pathToNameHook = new PathToNameHook($goBuilder->pathToNameHook);
+        $this->pathToNameHook->prefixes = array();
+        $goBuilder->pathToNameHook = $this->pathToNameHook;
+
+        $this->schemaData = new \stdClass();
+        $this->schemaData->{self::DEFINITIONS} = new \stdClass();
+        if (is_array($document)) {
+            foreach ($document as $i => $item) {
+                $this->convert($item, '', '#/' . $i);
+            }
+        } else {
+            $this->convert($document, '', '#');
+        }
+
+        $preloaded = new Preloaded();
+        $preloaded->setSchemaData(static::DOCUMENT_URL, $this->schemaData);
+        $this->context = new Context($preloaded);
+    }
+
+    /**
+     * @return mixed
+     */
+    public function getDocument()
+    {
+        return $this->document;
+    }
+
+    /**
+     * Returns JSON Schema document with named schemas in definitions.
+     *
+     * @return \stdClass
+     */
+    public function getSchemaData()
+    {
+        return $this->schemaData;
+    }
+
+    /**
+     * Builds types of all named schemas.
+     *
+     * @return $this
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function build()
+    {
+        foreach ($this->schemaData->{self::DEFINITIONS} as $fullName => $definition) {
+            $this->getType($fullName);
+        }
+
+        return $this;
+    }
+
+    /**
+     * Returns type of named schema by full name or alias.
+     *
+     * @param string $name
+     * @return AnyType
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function getType($name)
+    {
+        if (!isset($this->names[$name])) {
+            throw new Exception('Unknown named schema ' . $name);
+        }
+
+        $pointer = self::pointer($this->names[$name]);
+        $schema = Schema::import((object)array(Schema::PROP_REF => static::DOCUMENT_URL . $pointer), $this->context);
+
+        return $this->goBuilder->getType($schema, $pointer);
+    }
+
+    /**
+     * Converts Avro schema into JSON Schema.
+     *
+     * @param mixed $avro
+     * @param string $namespace enclosing namespace
+     * @param string $pointer
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convert($avro, $namespace, $pointer)
+    {
+        if (is_string($avro)) {
+            if (isset(static::$primitives[$avro])) {
+                return (object)static::$primitives[$avro];
+            }
+
+            $fullName = self::fullName($avro, $namespace);
+            if (!isset($this->names[$fullName])) {
+                throw new Exception('Unknown type ' . $avro . ' at ' . $pointer);
+            }
+
+            return (object)array(Schema::PROP_REF => self::pointer($this->names[$fullName]));
+        }
+
+        if (is_array($avro)) {
+            return $this->convertUnion($avro, $namespace, $pointer);
+        }
+
+        if (!$avro instanceof \stdClass || !isset($avro->type)) {
+            throw new Exception('Schema with type expected at ' . $pointer);
+        }
+
+        $type = $avro->type;
+        if (!is_string($type)) {
+            return $this->convert($type, $namespace, $pointer . '/type');
+        }
+
+        switch ($type) {
+            case 'record':
+            case 'error':
+                return $this->convertRecord($avro, $namespace, $pointer);
+
+            case 'enum':
+                $schema = (object)array('type' => Schema::STRING, 'enum' => $avro->symbols);
+                return $this->define($avro, $schema, $namespace, $pointer);
+
+            case 'fixed':
+                $schema = (object)array('type' => Schema::STRING);
+                if (isset($avro->logicalType, static::$logicalTypes[$avro->logicalType][$type])) {
+                    $schema->format = static::$logicalTypes[$avro->logicalType][$type];
+                }
+                return $this->define($avro, $schema, $namespace, $pointer);
+
+            case 'array':
+                return (object)array(
+                    'type' => Schema::_ARRAY,
+                    'items' => $this->convert($avro->items, $namespace, $pointer . '/items'),
+                );
+
+            case 'map':
+                return (object)array(
+                    'type' => Schema::OBJECT,
+                    'additionalProperties' => $this->convert($avro->values, $namespace, $pointer . '/values'),
+                );
+        }
+
+        // Logical type is ignored if it does not match underlying type.
+        if (isset($avro->logicalType, static::$logicalTypes[$avro->logicalType][$type], static::$primitives[$type])) {
+            $schema = (object)static::$primitives[$type];
+            $schema->format = static::$logicalTypes[$avro->logicalType][$type];
+            return $schema;
+        }
+
+        return $this->convert($type, $namespace, $pointer . '/type');
+    }
+
+    /**
+     * @param array $avro
+     * @param string $namespace
+     * @param string $pointer
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convertUnion(array $avro, $namespace, $pointer)
+    {
+        $nullable = false;
+        $schemas = array();
+        foreach ($avro as $i => $item) {
+            if ($item === 'null') {
+                $nullable = true;
+                continue;
+            }
+
+            $schemas[] = $this->convert($item, $namespace, $pointer . '/' . $i);
+        }
+
+        if (empty($schemas)) {
+            return (object)array('type' => Schema::NULL);
+        }
+
+        if (count($schemas) === 1) {
+            $schema = $schemas[0];
+            // Sibling keywords of reference are ignored, so nullable reference is wrapped.
+            if ($nullable && isset($schema->{Schema::PROP_REF})) {
+                $schema = (object)array('anyOf' => array($schema));
+            }
+        } else {
+            $schema = (object)array('oneOf' => $schemas);
+        }
+
+        if ($nullable) {
+            // Go type is explicit, so nullable needs a pointer here.
+            if (isset($schema->{TypeBuilder::X_GO_TYPE})) {
+                $schema->{TypeBuilder::X_GO_TYPE} = '*' . $schema->{TypeBuilder::X_GO_TYPE};
+            }
+            $schema->{TypeBuilder::X_NULLABLE} = true;
+            // Null is a value of union, so it is encoded explicitly whatever the Go type is.
+            $schema->{TypeBuilder::X_OMIT_EMPTY} = false;
+        }
+
+        return $schema;
+    }
+
+    /**
+     * @param \stdClass $avro
+     * @param string $namespace
+     * @param string $pointer
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convertRecord($avro, $namespace, $pointer)
+    {
+        if (!isset($avro->fields) || !is_array($avro->fields)) {
+            throw new Exception('Fields expected at ' . $pointer);
+        }
+
+        $schema = (object)array('type' => Schema::OBJECT, 'properties' => new \stdClass());
+        // Record is defined before fields, so that fields can refer to it.
+        $ref = $this->define($avro, $schema, $namespace, $pointer);
+        $namespace = self::namespaceOf(self::fullName($avro->name, $namespace, $avro));
+
+        $required = array();
+        foreach ($avro->fields as $i => $field) {
+            $fieldPointer = $pointer . '/fields/' . $i;
+            if (!isset($field->name, $field->type)) {
+                throw new Exception('Field name and type expected at ' . $fieldPointer);
+            }
+
+            $property = $this->convert($field->type, $namespace, $fieldPointer . '/type');
+            if (isset($field->doc)) {
+                if (isset($property->{Schema::PROP_REF})) {
+                    $property = (object)array('anyOf' => array($property));
+                }
+                $property->description = $field->doc;
+            }
+
+            $name = $field->name;
+            $schema->properties->$name = $property;
+
+            // Fields with default value can be omitted.
+            if (!property_exists($field, 'default')) {
+                $required[] = $name;
+            }
+        }
+
+        if (!empty($required)) {
+            $schema->required = $required;
+        }
+
+        return $ref;
+    }
+
+    /**
+     * Adds named schema to definitions.
+     *
+     * @param \stdClass $avro
+     * @param \stdClass $schema
+     * @param string $namespace
+     * @param string $pointer
+     * @return \stdClass reference to definition
+     * @throws Exception
+     */
+    private function define($avro, $schema, $namespace, $pointer)
+    {
+        if (!isset($avro->name)) {
+            throw new Exception('Name expected at ' . $pointer);
+        }
+
+        $fullName = self::fullName($avro->name, $namespace, $avro);
+        if (isset($this->names[$fullName])) {
+            throw new Exception('Duplicate name ' . $fullName . ' at ' . $pointer);
+        }
+        $this->names[$fullName] = $fullName;
+
+        if (isset($avro->doc)) {
+            $schema->description = $avro->doc;
+        }
+
+        $this->schemaData->{self::DEFINITIONS}->$fullName = $schema;
+
+        $codeBuilder = $this->goBuilder->codeBuilder;
+        $goName = $codeBuilder->exportableName(self::shortName($fullName));
+        $this->pathToNameHook->names[self::pointer($fullName)] = $goName;
+
+        if (isset($avro->aliases)) {
+            $renames = &$this->goBuilder->options->renames;
+            foreach ($avro->aliases as $alias) {
+                $alias = self::fullName($alias, self::namespaceOf($fullName));
+                $this->names[$alias] = $fullName;
+
+                // Renames configured for previous name of schema are kept.
+                $aliasGoName = $codeBuilder->exportableName(self::shortName($alias));
+                if (!isset($renames[$goName]) && isset($renames[$aliasGoName])) {
+                    $renames[$goName] = $renames[$aliasGoName];
+                }
+            }
+        }
+
+        return (object)array(Schema::PROP_REF => self::pointer($fullName));
+    }
+
+    /**
+     * @param string $name
+     * @param string $namespace enclosing namespace
+     * @param \stdClass|null $avro named schema with optional namespace
+     * @return string
+     */
+    private static function fullName($name, $namespace, $avro = null)
+    {
+        if (false !== strpos($name, '.')) {
+            return $name;
+        }
+
+        if ($avro !== null && isset($avro->namespace)) {
+            $namespace = $avro->namespace;
+        }
+
+        return $namespace === '' ? $name : $namespace . '.' . $name;
+    }
+
+    /**
+     * @param string $fullName
+     * @return string
+     */
+    private static function namespaceOf($fullName)
+    {
+        $pos = strrpos($fullName, '.');
+
+        return $pos === false ? '' : substr($fullName, 0, $pos);
+    }
+
+    /**
+     * @param string $fullName
+     * @return string
+     */
+    private static function shortName($fullName)
+    {
+        $pos = strrpos($fullName, '.');
+
+        return $pos === false ? $fullName : substr($fullName, $pos + 1);
+    }
+
+    /**
+     * @param string $fullName
+     * @return string
+     */
+    private static function pointer($fullName)
+    {
+        return '#/' . self::DEFINITIONS . '/' . strtr($fullName, array('~' => '~0', '/' => '~1'));
+    }
+}
\ No newline at end of file
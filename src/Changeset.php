@@ -0,0 +1,76 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder;
+
+/**
+ * Changeset describes files that storing to disk would add, modify or remove.
+ */
+class Changeset
+{
+    /**
+     * Unified diffs of new files by relative path.
+     * @var string[]
+     */
+    public $added = array();
+
+    /**
+     * Unified diffs of changed files by relative path.
+     * @var string[]
+     */
+    public $modified = array();
+
+    /**
+     * Unified diffs of stale files by relative path.
+     * @var string[]
+     */
+    public $removed = array();
+
+    /**
+     * Relative paths of generated files that were edited by hand.
+     * @var string[]
+     */
+    public $tampered = array();
+
+    /**
+     * @return bool
+     */
+    public function isEmpty()
+    {
+        return empty($this->added) && empty($this->modified) && empty($this->removed);
+    }
+
+    /**
+     * Returns combined unified diff of all changes ordered by file path.
+     *
+     * @return string
+     */
+    public function diff()
+    {
+        $diffs = $this->added + $this->modified + $this->removed;
+        ksort($diffs);
+
+        return implode('', $diffs);
+    }
+
+    /**
+     * Returns short summary of changes, one file per line.
+     *
+     * @return string
+     */
+    public function summary()
+    {
+        $lines = array();
+        foreach ($this->added as $path => $diff) {
+            $lines[$path] = 'A ' . $path;
+        }
+        foreach ($this->modified as $path => $diff) {
+            $lines[$path] = 'M ' . $path;
+        }
+        foreach ($this->removed as $path => $diff) {
+            $lines[$path] = 'D ' . $path;
+        }
+        ksort($lines);
+
+        return implode("\n", $lines);
+    }
+}
\ No newline at end of file
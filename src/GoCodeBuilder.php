@@ -2,8 +2,12 @@
 
 namespace Swaggest\GoCodeBuilder;
 
+use PhpLang\ScopeExit;
 use Swaggest\CodeBuilder\CodeBuilder;
 use Swaggest\GoCodeBuilder\Style\Initialisms;
+use Swaggest\GoCodeBuilder\Templates\Checksum;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\GoCodeBuilder\Templates\ProtectedRegion;
 
 /**
  * GoCodeBuilder provides file manager and names processor.
@@ -27,11 +31,130 @@ GO;
 
     public $versionComment;
 
+    /**
+     * Keep contents of protected regions of existing files when storing to disk.
+     * @var bool
+     * @see \Swaggest\GoCodeBuilder\Templates\ProtectedRegion
+     */
+    public $preserveRegions = false;
+
+    /**
+     * Overwrite generated files that were edited by hand.
+     * @var bool
+     * @see \Swaggest\GoCodeBuilder\Templates\Checksum
+     */
+    public $force = false;
+
+    /**
+     * Relative paths of generated files with mismatching checksum found during last store to disk.
+     * @var string[]
+     */
+    public $tamperedFiles = array();
+
     /**
      * @var Initialisms
      */
     public $initialisms;
 
+    /**
+     * Build constraint expression for generated files, e.g. "linux && amd64".
+     * @var string|null
+     */
+    public $buildConstraint;
+
+    /**
+     * Commands for //go:generate directives of generated files, e.g. to re-run generator with json-cli.
+     * @var string[]
+     */
+    public $generateDirectives = array();
+
+    /**
+     * Linters to disable in generated files.
+     * @var string[]
+     */
+    public $nolint = array();
+
+    /**
+     * Path to file with license header for generated files.
+     * @var string|null
+     */
+    public $licenseHeaderFile;
+
+    /**
+     * Module of generated code, found with readGoMod.
+     * @var GoMod|null
+     */
+    public $goMod;
+
+    /**
+     * Finds nearest go.mod above output directory to compute import paths of generated packages.
+     *
+     * @param string $outputPath
+     * @return GoMod|null
+     * @throws Exception
+     */
+    public function readGoMod($outputPath)
+    {
+        $this->goMod = GoMod::find($outputPath);
+        return $this->goMod;
+    }
+
+    /**
+     * Creates Go file for package in directory, import path is computed from go.mod.
+     *
+     * @param string $dir package directory
+     * @param string|null $package package name, defaults to directory name
+     * @return GoFile
+     * @throws Exception
+     */
+    public function createGoFile($dir, $package = null)
+    {
+        if ($this->goMod === null) {
+            $this->readGoMod($dir);
+        }
+
+        $importPath = null;
+        if ($this->goMod !== null) {
+            $importPath = $this->goMod->importPath($dir);
+        }
+
+        if ($package === null) {
+            $segments = explode('/', $importPath !== null ? $importPath : str_replace('\\', '/', rtrim($dir, '/\\')));
+            $base = array_pop($segments);
+            // Major version suffix is not a package name, e.g. github.com/acme/service/v2.
+            if (preg_match('/^v\d+$/', $base) && !empty($segments)) {
+                $base = array_pop($segments);
+            }
+            $package = preg_replace('/[^a-z0-9_]/', '', strtolower($base));
+        }
+
+        return $this->setUpGoFile(new GoFile($package, $importPath));
+    }
+
+    /**
+     * Applies builder level file header settings to Go file.
+     *
+     * @param GoFile $goFile
+     * @return GoFile
+     * @throws Exception
+     */
+    public function setUpGoFile(GoFile $goFile)
+    {
+        if ($this->buildConstraint) {
+            $goFile->buildConstraint = $this->buildConstraint;
+        }
+        foreach ($this->generateDirectives as $command) {
+            $goFile->generateDirectives[] = $command;
+        }
+        foreach ($this->nolint as $linter) {
+            $goFile->nolint[] = $linter;
+        }
+        if ($this->licenseHeaderFile) {
+            $goFile->setLicenseHeaderFromFile($this->licenseHeaderFile);
+        }
+        return $goFile;
+    }
+
 
     public function storeToDisk($srcPath)
     {
@@ -40,11 +163,252 @@ GO;
         }
 
         $srcPath = $this->realSrcPath($srcPath);
+        $this->tamperedFiles = $this->findTamperedFiles($srcPath);
+        if ($this->tamperedFiles && !$this->force) {
+            throw new Exception("Generated files were edited by hand, use force to overwrite:\n"
+                . implode("\n", $this->tamperedFiles));
+        }
+
         $this->originalFiles = $this->recursiveFileList($srcPath, array('go' => true, 'yaml' => true, 'lock' => true),
             array('vendor', '.git', '*_test.go'));
+
+        $previous = array();
+        if ($this->preserveRegions) {
+            $previous = $this->readProtectedFiles($srcPath);
+        }
+
+        $this->writeFiles($srcPath);
+
+        $this->restoreRegions($srcPath, $previous);
+    }
+
+    /**
+     * Writes generated files to src path and removes original files that were not generated again.
+     *
+     * @param string $srcPath
+     */
+    protected function writeFiles($srcPath)
+    {
         parent::storeToDisk($srcPath);
     }
 
+    /**
+     * Returns changes that storing to disk would make without touching files in src path.
+     *
+     * Files are rendered to a temporary directory that is removed afterwards.
+     *
+     * @param string $srcPath
+     * @return Changeset
+     * @throws Exception
+     */
+    public function dryRun($srcPath)
+    {
+        if (!$srcPath) {
+            throw new Exception('No src path');
+        }
+
+        $tmpPath = tempnam(sys_get_temp_dir(), 'go-code-builder');
+        unlink($tmpPath);
+        mkdir($tmpPath);
+
+        $originalFiles = $this->originalFiles;
+        /** @noinspection PhpUnusedLocalVariableInspection */
+        $_ = new ScopeExit(function () use ($tmpPath, $originalFiles) {
+            $this->originalFiles = $originalFiles;
+            $this->removeDir($tmpPath);
+        });
+
+        // Nothing to remove in a fresh directory.
+        $this->originalFiles = array();
+        $this->writeFiles($tmpPath);
+
+        $srcPath = rtrim($srcPath, '/\\');
+        if ($this->preserveRegions) {
+            $this->restoreRegions($tmpPath, $this->readProtectedFiles($srcPath));
+        }
+
+        $changeset = new Changeset();
+        $changeset->tampered = $this->findTamperedFiles($srcPath);
+        $diff = new UnifiedDiff();
+        $existing = $this->managedFiles($srcPath);
+        $generated = $this->listFiles($tmpPath);
+        foreach ($generated as $relativePath) {
+            $code = file_get_contents($tmpPath . DIRECTORY_SEPARATOR . $relativePath);
+            $filePath = $srcPath . DIRECTORY_SEPARATOR . $relativePath;
+            if (!file_exists($filePath)) {
+                $changeset->added[$relativePath] = $diff->diff('', $code, '/dev/null', 'b/' . $relativePath);
+                continue;
+            }
+            $previousCode = file_get_contents($filePath);
+            if ($previousCode !== $code) {
+                $changeset->modified[$relativePath] = $diff->diff($previousCode, $code,
+                    'a/' . $relativePath, 'b/' . $relativePath);
+            }
+        }
+
+        foreach (array_diff($existing, $generated) as $relativePath) {
+            $previousCode = file_get_contents($srcPath . DIRECTORY_SEPARATOR . $relativePath);
+            $changeset->removed[$relativePath] = $diff->diff($previousCode, '', 'a/' . $relativePath, '/dev/null');
+        }
+
+        return $changeset;
+    }
+
+    /**
+     * Fails if generated code in src path differs from what would be stored.
+     *
+     * @param string $srcPath
+     * @return Changeset empty changeset
+     * @throws Exception
+     */
+    public function check($srcPath)
+    {
+        $changeset = $this->dryRun($srcPath);
+        if (!$changeset->isEmpty()) {
+            throw new Exception("Generated code is out of date:\n" . $changeset->summary() . "\n\n" . $changeset->diff());
+        }
+
+        return $changeset;
+    }
+
+    /**
+     * @param string $srcPath
+     * @param string[] $previous contents by relative path
+     */
+    private function restoreRegions($srcPath, array $previous)
+    {
+        foreach ($previous as $relativePath => $previousCode) {
+            $filePath = $srcPath . DIRECTORY_SEPARATOR . $relativePath;
+            if (!file_exists($filePath)) {
+                continue;
+            }
+            $code = file_get_contents($filePath);
+            $restored = ProtectedRegion::restore($code, $previousCode);
+            if ($restored !== $code) {
+                file_put_contents($filePath, $restored);
+            }
+        }
+    }
+
+    /**
+     * Returns contents of Go files that have protected regions.
+     *
+     * @param string $srcPath
+     * @return string[] contents by relative path
+     */
+    private function readProtectedFiles($srcPath)
+    {
+        $result = array();
+        foreach ($this->managedFiles($srcPath) as $relativePath) {
+            if (substr($relativePath, -3) !== '.go') {
+                continue;
+            }
+            $code = file_get_contents($srcPath . DIRECTORY_SEPARATOR . $relativePath);
+            if (ProtectedRegion::parse($code)) {
+                $result[$relativePath] = $code;
+            }
+        }
+
+        return $result;
+    }
+
+    /**
+     * Returns relative paths of signed Go files with mismatching checksum.
+     *
+     * @param string $srcPath
+     * @return string[]
+     */
+    private function findTamperedFiles($srcPath)
+    {
+        $result = array();
+        foreach ($this->managedFiles($srcPath) as $relativePath) {
+            if (substr($relativePath, -3) !== '.go') {
+                continue;
+            }
+            $code = file_get_contents($srcPath . DIRECTORY_SEPARATOR . $relativePath);
+            if (Checksum::verify($code) === false) {
+                $result[] = $relativePath;
+            }
+        }
+
+        return $result;
+    }
+
+    /**
+     * Returns relative paths of files that are replaced or removed when storing to disk.
+     *
+     * @param string $srcPath
+     * @return string[]
+     */
+    private function managedFiles($srcPath)
+    {
+        $result = array();
+        foreach ($this->listFiles($srcPath) as $relativePath) {
+            $segments = explode(DIRECTORY_SEPARATOR, $relativePath);
+            if (in_array('vendor', $segments, true) || in_array('.git', $segments, true)) {
+                continue;
+            }
+            if (substr($relativePath, -8) === '_test.go') {
+                continue;
+            }
+            $extension = pathinfo($relativePath, PATHINFO_EXTENSION);
+            if ($extension === 'go' || $extension === 'yaml' || $extension === 'lock') {
+                $result[] = $relativePath;
+            }
+        }
+
+        return $result;
+    }
+
+    /**
+     * Returns sorted relative paths of all files in directory.
+     *
+     * @param string $path
+     * @return string[]
+     */
+    private function listFiles($path)
+    {
+        $result = array();
+        if (!is_dir($path)) {
+            return $result;
+        }
+
+        $iterator = new \RecursiveIteratorIterator(
+            new \RecursiveDirectoryIterator($path, \FilesystemIterator::SKIP_DOTS)
+        );
+        /** @var \SplFileInfo $file */
+        foreach ($iterator as $file) {
+            $result[] = substr($file->getPathname(), strlen($path) + 1);
+        }
+        sort($result);
+
+        return $result;
+    }
+
+    /**
+     * @param string $path
+     */
+    private function removeDir($path)
+    {
+        if (!is_dir($path)) {
+            return;
+        }
+
+        $iterator = new \RecursiveIteratorIterator(
+            new \RecursiveDirectoryIterator($path, \FilesystemIterator::SKIP_DOTS),
+            \RecursiveIteratorIterator::CHILD_FIRST
+        );
+        /** @var \SplFileInfo $file */
+        foreach ($iterator as $file) {
+            if ($file->isDir()) {
+                rmdir($file->getPathname());
+            } else {
+                unlink($file->getPathname());
+            }
+        }
+        rmdir($path);
+    }
+
 
     /**
      * @param string $string
@@ -0,0 +1,182 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder;
+
+/**
+ * GoMod describes Go module from go.mod file.
+ */
+class GoMod
+{
+    /** @var string module path */
+    public $modulePath;
+
+    /** @var string|null minimum Go version from go directive */
+    public $goVersion;
+
+    /** @var string directory of go.mod */
+    public $dir;
+
+    /**
+     * Finds nearest go.mod in the directory or its parents.
+     *
+     * @param string $path directory, may not exist yet
+     * @return GoMod|null
+     * @throws Exception
+     */
+    public static function find($path)
+    {
+        $dir = self::absolutePath($path);
+        while (true) {
+            $goModPath = $dir . DIRECTORY_SEPARATOR . 'go.mod';
+            if (is_file($goModPath)) {
+                return self::parse(file_get_contents($goModPath), $dir);
+            }
+
+            $parent = dirname($dir);
+            if ($parent === $dir) {
+                return null;
+            }
+            $dir = $parent;
+        }
+    }
+
+    /**
+     * @param string $content
+     * @param string $dir
+     * @return GoMod
+     * @throws Exception
+     */
+    public static function parse($content, $dir)
+    {
+        // Comments are not a part of directives.
+        $content = preg_replace('/\/\/[^\n]*/', '', $content);
+
+        if (!preg_match('/^\s*module\s+("([^"]+)"|\S+)/m', $content, $match)) {
+            throw new Exception('Module directive not found in go.mod of ' . $dir);
+        }
+
+        $goMod = new self();
+        $goMod->modulePath = isset($match[2]) && $match[2] !== '' ? $match[2] : $match[1];
+        $goMod->dir = rtrim($dir, '/\\');
+
+        if (preg_match('/^\s*go\s+(\d+(?:\.\d+)*)/m', $content, $match)) {
+            $goMod->goVersion = $match[1];
+        }
+
+        return $goMod;
+    }
+
+    /**
+     * Returns import path of package in directory.
+     *
+     * @param string $path package directory, may not exist yet
+     * @return string
+     * @throws Exception
+     */
+    public function importPath($path)
+    {
+        $path = self::absolutePath($path);
+        if ($path === $this->dir) {
+            return $this->modulePath;
+        }
+
+        $prefix = $this->dir . DIRECTORY_SEPARATOR;
+        if (strpos($path, $prefix) !== 0) {
+            throw new Exception('Directory ' . $path . ' is outside of module ' . $this->modulePath);
+        }
+
+        return $this->modulePath . '/' . str_replace(DIRECTORY_SEPARATOR, '/', substr($path, strlen($prefix)));
+    }
+
+    /**
+     * Returns import path that points to existing package of this module under a different path.
+     *
+     * For example, "github.com/acme/old-name/orders" resolves to "github.com/acme/service/orders"
+     * if module "github.com/acme/service" has "orders" directory. Only import paths with the same host and owner
+     * as module path (e.g. "github.com/acme") are checked, packages of third-party modules are not mismatching.
+     *
+     * @param string $importPath
+     * @return string|null null if import path is not mismatching
+     */
+    public function mismatchingImportPath($importPath)
+    {
+        if ($importPath === $this->modulePath || strpos($importPath, $this->modulePath . '/') === 0) {
+            return null;
+        }
+
+        $owner = $this->ownerPath();
+        if ($owner === null || strpos($importPath, $owner . '/') !== 0) {
+            return null;
+        }
+
+        $segments = explode('/', $importPath);
+        // Domain alone is not a package of this module.
+        for ($i = 1, $count = count($segments); $i < $count; ++$i) {
+            $relative = implode('/', array_slice($segments, $i));
+            if (is_dir($this->dir . DIRECTORY_SEPARATOR . str_replace('/', DIRECTORY_SEPARATOR, $relative))) {
+                return $this->modulePath . '/' . $relative;
+            }
+        }
+
+        return null;
+    }
+
+    /**
+     * Returns host and owner of module path, e.g. "github.com/acme" for "github.com/acme/service/v2".
+     *
+     * @return string|null null if module path has no owner
+     */
+    private function ownerPath()
+    {
+        $segments = explode('/', $this->modulePath);
+        if (count($segments) > 1 && preg_match('/^v\d+$/', end($segments))) {
+            array_pop($segments);
+        }
+        array_pop($segments);
+        if (empty($segments)) {
+            return null;
+        }
+
+        return implode('/', array_slice($segments, 0, 2));
+    }
+
+    /**
+     * Makes absolute normalized path, missing trailing directories are kept as is.
+     *
+     * @param string $path
+     * @return string
+     */
+    private static function absolutePath($path)
+    {
+        if ($real = realpath($path)) {
+            return $real;
+        }
+
+        $path = str_replace('\\', '/', $path);
+        if ($path === '' || ($path[0] !== '/' && !preg_match('/^[A-Za-z]:\//', $path))) {
+            $path = str_replace('\\', '/', getcwd()) . '/' . $path;
+        }
+
+        $segments = array();
+        foreach (explode('/', $path) as $i => $segment) {
+            if ($segment === '..') {
+                if (count($segments) > 1) {
+                    array_pop($segments);
+                }
+            } elseif ($segment !== '.' && ($segment !== '' || $i === 0)) {
+                $segments[] = $segment;
+            }
+        }
+
+        // Existing part of path is resolved to match paths of found go.mod.
+        $missing = array();
+        while (count($segments) > 1) {
+            $missing[] = array_pop($segments);
+            if ($real = realpath(implode('/', $segments) . '/')) {
+                return rtrim($real, '/\\') . DIRECTORY_SEPARATOR . implode(DIRECTORY_SEPARATOR, array_reverse($missing));
+            }
+        }
+
+        return str_replace('/', DIRECTORY_SEPARATOR, implode('/', array_merge($segments, array_reverse($missing))));
+    }
+}
\ No newline at end of file
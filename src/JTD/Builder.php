@@ -0,0 +1,374 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JTD;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\TypeBuilder;
+use Swaggest\GoCodeBuilder\OpenAPI3\PathToNameHook;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\JsonSchema\Context;
+use Swaggest\JsonSchema\RemoteRef\Preloaded;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder translates JSON Type Definition (RFC 8927) schema into JSON Schema and builds its types with GoBuilder.
+ *
+ * Root schema is added to definitions with a name, mapping variants of discriminator become named structures
+ * that are combined with oneOf.
+ */
+class Builder
+{
+    /** Document URL for schema references. */
+    const DOCUMENT_URL = 'jtd.json';
+
+    const DEFINITIONS = 'definitions';
+    const VARIANTS = 'variants';
+
+    /** @var int[][] ranges of integer types */
+    public static $integers = array(
+        'int8' => array(-128, 127),
+        'uint8' => array(0, 255),
+        'int16' => array(-32768, 32767),
+        'uint16' => array(0, 65535),
+        'int32' => array(-2147483648, 2147483647),
+        'uint32' => array(0, 4294967295),
+    );
+
+    /** @var GoBuilder */
+    public $goBuilder;
+
+    /** @var PathToNameHook */
+    public $pathToNameHook;
+
+    /** @var \stdClass */
+    private $document;
+
+    /** @var string */
+    private $name;
+
+    /** @var \stdClass translated JSON Schema document */
+    private $schemaData;
+
+    /** @var Context */
+    private $context;
+
+    /**
+     * Builder constructor.
+     * @param \stdClass $document decoded JTD schema
+     * @param string $name definition name of root schema
+     * @param GoBuilder|null $goBuilder
+     * @throws Exception
+     */
+    public function __construct($document, $name, GoBuilder $goBuilder = null)
+    {
+        if ($goBuilder === null) {
+            $goBuilder = new GoBuilder();
+            $goBuilder->options->enableXNullable = true;
+        }
+
+        $this->document = $document;
+        $this->name = $name;
+        $this->goBuilder = $goBuilder;
+        $this->schemaData = $this->translate();
+
+        $preloaded = new Preloaded();
+        $preloaded->setSchemaData(static::DOCUMENT_URL, $this->schemaData);
+        $this->context = new Context($preloaded);
+
+        $this->pathToNameHook = new PathToNameHook($goBuilder->pathToNameHook);
+        $this->pathToNameHook->prefixes = array('#/' . self::DEFINITIONS, '#/' . self::VARIANTS);
+        $goBuilder->pathToNameHook = $this->pathToNameHook;
+    }
+
+    /**
+     * @return \stdClass
+     */
+    public function getDocument()
+    {
+        return $this->document;
+    }
+
+    /**
+     * Returns JSON Schema document with root schema and definitions of JTD schema in definitions.
+     *
+     * @return \stdClass
+     */
+    public function getSchemaData()
+    {
+        return $this->schemaData;
+    }
+
+    /**
+     * Builds types of root schema and definitions.
+     *
+     * @return AnyType type of root schema
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function build()
+    {
+        $type = $this->getType($this->name);
+        foreach ($this->schemaData->{self::DEFINITIONS} as $name => $definition) {
+            $this->getType($name);
+        }
+
+        return $type;
+    }
+
+    /**
+     * Returns type of root schema or definition by name.
+     *
+     * @param string $name
+     * @return AnyType
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function getType($name)
+    {
+        $pointer = '#/' . self::DEFINITIONS . '/' . self::escapePointer($name);
+        $schema = Schema::import((object)array(Schema::PROP_REF => static::DOCUMENT_URL . $pointer), $this->context);
+
+        return $this->goBuilder->getType($schema, $pointer);
+    }
+
+    /**
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function translate()
+    {
+        if (!$this->document instanceof \stdClass) {
+            throw new Exception('Schema object expected at #');
+        }
+
+        $schemaData = new \stdClass();
+        $schemaData->{self::DEFINITIONS} = new \stdClass();
+        $schemaData->{self::VARIANTS} = new \stdClass();
+
+        $root = clone $this->document;
+        unset($root->definitions);
+        $schemaData->{self::DEFINITIONS}->{$this->name} = $this->convert($root, '#', $this->name, $schemaData);
+
+        if (isset($this->document->definitions)) {
+            foreach ($this->document->definitions as $name => $definition) {
+                if ($name === $this->name) {
+                    throw new Exception('Definition ' . $name . ' conflicts with name of root schema');
+                }
+
+                $pointer = '#/definitions/' . self::escapePointer($name);
+                $schemaData->{self::DEFINITIONS}->$name = $this->convert($definition, $pointer, $name, $schemaData);
+            }
+        }
+
+        return $schemaData;
+    }
+
+    /**
+     * Converts JTD schema form into JSON Schema.
+     *
+     * @param \stdClass $jtd
+     * @param string $pointer
+     * @param string $name base name for mapping variants
+     * @param \stdClass $schemaData translated document
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convert($jtd, $pointer, $name, $schemaData)
+    {
+        if (!$jtd instanceof \stdClass) {
+            throw new Exception('Schema object expected at ' . $pointer);
+        }
+
+        $nullable = !empty($jtd->nullable);
+
+        if (isset($jtd->ref)) {
+            if (!isset($this->document->definitions->{$jtd->ref})) {
+                throw new Exception('Missing definition ' . $jtd->ref . ' at ' . $pointer);
+            }
+
+            $schema = (object)array(Schema::PROP_REF => '#/definitions/' . self::escapePointer($jtd->ref));
+            if (!$nullable) {
+                return $schema;
+            }
+
+            // Sibling keywords of reference are ignored, so nullable reference is wrapped.
+            $schema = (object)array('anyOf' => array($schema));
+        } elseif (isset($jtd->type)) {
+            $schema = $this->convertType($jtd->type, $nullable, $pointer);
+        } elseif (isset($jtd->enum)) {
+            $schema = (object)array('type' => Schema::STRING, 'enum' => $jtd->enum);
+        } elseif (isset($jtd->elements)) {
+            $schema = (object)array(
+                'type' => Schema::_ARRAY,
+                'items' => $this->convert($jtd->elements, $pointer . '/elements', $name, $schemaData),
+            );
+        } elseif (isset($jtd->values)) {
+            $schema = (object)array(
+                'type' => Schema::OBJECT,
+                'additionalProperties' => $this->convert($jtd->values, $pointer . '/values', $name, $schemaData),
+            );
+        } elseif (isset($jtd->discriminator)) {
+            $schema = $this->convertDiscriminator($jtd, $pointer, $name, $schemaData);
+        } elseif (isset($jtd->properties) || isset($jtd->optionalProperties)) {
+            $schema = $this->convertProperties($jtd, $pointer, $name, $schemaData);
+        } else {
+            // Empty form accepts any value, including null.
+            $schema = new \stdClass();
+            $nullable = false;
+        }
+
+        if ($nullable) {
+            $schema->{TypeBuilder::X_NULLABLE} = true;
+        }
+
+        if (isset($jtd->metadata->description)) {
+            $schema->description = $jtd->metadata->description;
+        }
+
+        return $schema;
+    }
+
+    /**
+     * @param string $type
+     * @param bool $nullable
+     * @param string $pointer
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convertType($type, $nullable, $pointer)
+    {
+        switch ($type) {
+            case 'boolean':
+                return (object)array('type' => Schema::BOOLEAN);
+            case 'string':
+                return (object)array('type' => Schema::STRING);
+            case 'timestamp':
+                return (object)array('type' => Schema::STRING, 'format' => 'date-time');
+            case 'float64':
+                return (object)array('type' => Schema::NUMBER);
+            case 'float32':
+                // Go type is explicit, so nullable needs a pointer here.
+                return (object)array(
+                    'type' => Schema::NUMBER,
+                    TypeBuilder::X_GO_TYPE => ($nullable ? '*' : '') . $type,
+                );
+        }
+
+        if (!isset(static::$integers[$type])) {
+            throw new Exception('Unknown type ' . $type . ' at ' . $pointer);
+        }
+
+        return (object)array(
+            'type' => Schema::INTEGER,
+            'minimum' => static::$integers[$type][0],
+            'maximum' => static::$integers[$type][1],
+            TypeBuilder::X_GO_TYPE => ($nullable ? '*' : '') . $type,
+        );
+    }
+
+    /**
+     * @param \stdClass $jtd
+     * @param string $pointer
+     * @param string $name
+     * @param \stdClass $schemaData
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convertProperties($jtd, $pointer, $name, $schemaData)
+    {
+        $schema = (object)array('type' => Schema::OBJECT);
+        $properties = new \stdClass();
+        $required = array();
+
+        foreach (array('properties', 'optionalProperties') as $keyword) {
+            if (!isset($jtd->$keyword)) {
+                continue;
+            }
+
+            foreach ($jtd->$keyword as $propertyName => $property) {
+                if (isset($properties->$propertyName)) {
+                    throw new Exception('Duplicate property ' . $propertyName . ' at ' . $pointer);
+                }
+
+                $propertyPointer = $pointer . '/' . $keyword . '/' . self::escapePointer($propertyName);
+                $properties->$propertyName = $this->convert($property, $propertyPointer, $name . '/' . $propertyName, $schemaData);
+                if ($keyword === 'properties') {
+                    $required[] = (string)$propertyName;
+                }
+            }
+        }
+
+        $schema->properties = $properties;
+        if (!empty($required)) {
+            $schema->required = $required;
+        }
+
+        // Additional properties are rejected by default in JTD, allowed ones are handled according to builder options.
+        if (empty($jtd->additionalProperties)) {
+            $schema->additionalProperties = false;
+        }
+
+        return $schema;
+    }
+
+    /**
+     * @param \stdClass $jtd
+     * @param string $pointer
+     * @param string $name
+     * @param \stdClass $schemaData
+     * @return \stdClass
+     * @throws Exception
+     */
+    private function convertDiscriminator($jtd, $pointer, $name, $schemaData)
+    {
+        if (!isset($jtd->mapping)) {
+            throw new Exception('Missing mapping of discriminator at ' . $pointer);
+        }
+
+        $tagName = $jtd->discriminator;
+        $oneOf = array();
+        foreach ($jtd->mapping as $tag => $variant) {
+            $tag = (string)$tag;
+            $variantPointer = $pointer . '/mapping/' . self::escapePointer($tag);
+            if (!$variant instanceof \stdClass || !empty($variant->nullable)
+                || (!isset($variant->properties) && !isset($variant->optionalProperties))) {
+                throw new Exception('Non-nullable properties form expected at ' . $variantPointer);
+            }
+
+            $variantName = $this->goBuilder->codeBuilder->exportableName($name . '/' . $tag);
+            if (isset($schemaData->{self::VARIANTS}->$variantName)) {
+                throw new Exception('Duplicate variant name ' . $variantName . ' at ' . $variantPointer);
+            }
+
+            $schema = $this->convert($variant, $variantPointer, $name . '/' . $tag, $schemaData);
+            if (isset($schema->properties->$tagName)) {
+                throw new Exception('Discriminator ' . $tagName . ' is redefined at ' . $variantPointer);
+            }
+
+            // Discriminator property with constant value is the first required property of variant.
+            $properties = new \stdClass();
+            $properties->$tagName = (object)array('type' => Schema::STRING, 'enum' => array($tag));
+            foreach ($schema->properties as $propertyName => $property) {
+                $properties->$propertyName = $property;
+            }
+            $schema->properties = $properties;
+            $schema->required = array_merge(array($tagName), isset($schema->required) ? $schema->required : array());
+
+            $schemaData->{self::VARIANTS}->$variantName = $schema;
+            $oneOf[] = (object)array(Schema::PROP_REF => '#/' . self::VARIANTS . '/' . self::escapePointer($variantName));
+        }
+
+        return (object)array('oneOf' => $oneOf);
+    }
+
+    /**
+     * @param string $value
+     * @return string
+     */
+    private static function escapePointer($value)
+    {
+        return strtr($value, array('~' => '~0', '/' => '~1'));
+    }
+}
\ No newline at end of file
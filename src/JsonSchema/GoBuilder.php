@@ -13,6 +13,7 @@ use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
 use Swaggest\GoCodeBuilder\Templates\Type\NoOmitEmpty;
 use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
 use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
 use Swaggest\JsonSchema\Schema;
 use Swaggest\JsonSchema\Wrapper;
 
@@ -42,6 +43,12 @@ class GoBuilder
     /** @var GoBuilderPathToNameHook */
     public $pathToNameHook;
 
+    /** @var GoBuilderKeywordHook|null receives schema keywords that could not be represented in generated code */
+    public $unsupportedKeywordHook;
+
+    /** @var GoBuilderWarningHook|null receives warnings about possibly wrong schema or generation settings */
+    public $warningHook;
+
     /** @var MarshalUnion */
     public $marshalUnion;
 
@@ -72,6 +79,58 @@ class GoBuilder
         return $this->code;
     }
 
+    /**
+     * Checks if feature is available in target Go version.
+     *
+     * @param string $feature minimum version, e.g. GoVersion::ANY
+     * @return bool
+     */
+    public function supports($feature)
+    {
+        $goVersion = $this->options->goVersion;
+        if (empty($goVersion) && $this->codeBuilder->goMod !== null) {
+            $goVersion = $this->codeBuilder->goMod->goVersion;
+        }
+
+        return GoVersion::supports($goVersion, $feature);
+    }
+
+    /**
+     * Warns if `x-go-type` imports a package of generated module under a different path.
+     *
+     * @param AnyType $type
+     * @param string $path
+     * @param Schema $schema
+     */
+    public function checkXGoTypeImports(AnyType $type, $path, $schema)
+    {
+        if ($this->warningHook === null || $this->codeBuilder->goMod === null) {
+            return;
+        }
+
+        foreach (TypeUtil::imports($type) as $import) {
+            $expected = $this->codeBuilder->goMod->mismatchingImportPath($import->name);
+            if ($expected !== null) {
+                $this->warningHook->process(
+                    'x-go-type imports "' . $import->name . '", but package of module '
+                    . $this->codeBuilder->goMod->modulePath . ' is "' . $expected . '"',
+                    $path,
+                    $schema
+                );
+            }
+        }
+    }
+
+    /**
+     * Returns name of empty interface type for target Go version.
+     *
+     * @return string
+     */
+    public function anyType()
+    {
+        return $this->supports(GoVersion::ANY) ? 'any' : 'interface{}';
+    }
+
     /**
      * @param Schema|\stdClass $schema
      * @param string $path
@@ -262,7 +321,10 @@ class GoBuilder
                         || $this->options->ignoreRequired
                         || $this->isNullable($property)
                     ) {
-                        $goPropertyType = new Pointer($goPropertyType);
+                        // Optional structures are omitted by value with `omitzero`, only `null` needs a pointer.
+                        if (!$this->supports(GoVersion::OMIT_ZERO) || $this->isNullable($property)) {
+                            $goPropertyType = new Pointer($goPropertyType);
+                        }
                     }
                 }
                 $goProperty = new StructProperty(
@@ -292,15 +354,34 @@ class GoBuilder
                     $goProperty->getTags()->setTag('json', $name);
                 } else {
                     $isOmitEmpty = true;
-                    $goProperty->getTags()->setTag('json', $name . ',omitempty');
+                    if ($goPropertyType instanceof StructType && $this->supports(GoVersion::OMIT_ZERO)) {
+                        $goProperty->getTags()->setTag('json', $name . ',omitzero');
+                    } else {
+                        $goProperty->getTags()->setTag('json', $name . ',omitempty');
+                    }
                 }
 
+                MirrorTags::apply($goProperty, $this->options->mirrorTags);
+
                 if (!empty($this->options->nameTags)) {
                     foreach ($this->options->nameTags as $nameTag) {
                         $goProperty->getTags()->setTag($nameTag, $name);
                     }
                 }
 
+                if ($this->options->jsonSchemaTags) {
+                    SchemaTags::addToProperty($goProperty, $property, $isRequired);
+                }
+
+                if ($this->options->validateTags) {
+                    $validateTags = new ValidateTags($this);
+                    $validateTags->addToProperty($goProperty, $property, $isRequired, $path . '->' . $name);
+                }
+
+                if (null !== $property->{TypeBuilder::X_GO_TAGS}) {
+                    MirrorTags::applyExtension($goProperty, $property->{TypeBuilder::X_GO_TAGS});
+                }
+
                 $comment = '';
                 if ($property->title) {
                     $comment .= Comment::sentence($property->title) . "\n";
@@ -339,7 +420,7 @@ class GoBuilder
                     }
                 }
 
-                if ($goPropertyType->getTypeString() === 'interface{}') {
+                if (TypeUtil::isAny($goPropertyType)) {
                     if ($this->options->distinctNull && $isOmitEmpty) {
                         $goPropertyType = new Pointer($goPropertyType);
                         $goProperty->setType($goPropertyType);
@@ -441,6 +522,26 @@ class GoBuilder
         return false;
     }
 
+    /**
+     * Checks if unknown keys should be rejected when unmarshaling schema.
+     *
+     * @param Schema $schema
+     * @param string $path
+     * @return bool
+     */
+    public function isStrict(Schema $schema, $path)
+    {
+        if ($this->options->strictUnmarshal) {
+            return true;
+        }
+
+        if (!empty($this->options->strictUnmarshalPaths) && in_array($path, $this->options->strictUnmarshalPaths, true)) {
+            return true;
+        }
+
+        return false;
+    }
+
     /**
      * @param Schema $schema
      * @param string $path
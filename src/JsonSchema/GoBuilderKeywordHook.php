@@ -0,0 +1,16 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\JsonSchema\Schema;
+
+interface GoBuilderKeywordHook
+{
+    /**
+     * @param string $keyword
+     * @param string $path
+     * @param Schema $schema
+     * @return null
+     */
+    public function process($keyword, $path, $schema);
+}
\ No newline at end of file
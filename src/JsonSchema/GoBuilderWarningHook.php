@@ -0,0 +1,16 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\JsonSchema\Schema;
+
+interface GoBuilderWarningHook
+{
+    /**
+     * @param string $message
+     * @param string $path
+     * @param Schema|null $schema null for warnings that are not about a schema
+     * @return null
+     */
+    public function process($message, $path, $schema);
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+/**
+ * GoVersion lists minimum Go versions of language and library features used in generated code.
+ */
+class GoVersion
+{
+    /** Predeclared `any` alias of `interface{}`. */
+    const ANY = '1.18';
+
+    /** Wrapping of multiple errors with `errors.Join`. */
+    const ERRORS_JOIN = '1.20';
+
+    /** Iterator helpers `slices.Sorted` and `maps.Keys`. */
+    const SORTED_KEYS = '1.23';
+
+    /** Json field tag option `omitzero`. */
+    const OMIT_ZERO = '1.24';
+
+    /**
+     * Checks if feature is available in target Go version.
+     *
+     * @param string|null $target target version, e.g. "1.21" or "go1.21", empty target supports no features
+     * @param string $feature minimum version of feature
+     * @return bool
+     */
+    public static function supports($target, $feature)
+    {
+        if (empty($target)) {
+            return false;
+        }
+
+        if (strpos($target, 'go') === 0) {
+            $target = substr($target, 2);
+        }
+
+        return version_compare($target, $feature, '>=');
+    }
+}
\ No newline at end of file
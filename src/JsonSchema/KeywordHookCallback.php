@@ -0,0 +1,26 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+
+class KeywordHookCallback implements GoBuilderKeywordHook
+{
+    /** @var \Closure */
+    private $closure;
+
+    /**
+     * KeywordHookCallback constructor.
+     * @param \Closure $closure
+     */
+    public function __construct(\Closure $closure)
+    {
+        $this->closure = $closure;
+    }
+
+    public function process($keyword, $path, $schema)
+    {
+        $this->closure->__invoke($keyword, $path, $schema);
+    }
+
+
+}
\ No newline at end of file
@@ -35,6 +35,9 @@ class MarshalEnum extends GoTemplate
     /** @var Code */
     private $code;
 
+    /** @var MarshalYaml */
+    private $marshalYaml;
+
     /**
      * MarshalEnum constructor.
      * @param Type $type
@@ -49,6 +52,7 @@ class MarshalEnum extends GoTemplate
         $this->base = $base;
         $this->builder = $builder;
         $this->code = new Code();
+        $this->marshalYaml = new MarshalYaml($type, $builder);
     }
 
 
@@ -114,6 +118,7 @@ GO;
                 ':base' => $this->base,
             ]));
             $code->imports()->addByName('fmt');
+            $code->addSnippet($this->marshalYaml);
             return $code;
         }
         return '';
@@ -172,6 +177,9 @@ GO;
 
     protected function toString()
     {
+        // Code is collected from scratch so that template can be rendered more than once.
+        $this->code = new Code();
+
         if (count($this->enum) === 1) {
             return $this->constToString();
         }
@@ -186,6 +194,7 @@ GO;
                 ':base' => $this->base,
             ]));
             $this->code->imports()->addByName('fmt');
+            $this->code->addSnippet($this->marshalYaml);
             return $this->code;
         }
 
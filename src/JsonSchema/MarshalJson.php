@@ -26,6 +26,12 @@ class MarshalJson extends GoTemplate
     /** @var string[] */
     public $distinctNullNames = [];
 
+    /** @var string[] */
+    private $knownKeys = [];
+
+    /** @var string[][] */
+    private $partKeys = [];
+
     private $additionalPropertiesEnabled = null;
 
     /** @var StructProperty */
@@ -41,6 +47,9 @@ class MarshalJson extends GoTemplate
 
     private $code;
 
+    /** @var MarshalYaml */
+    private $marshalYaml;
+
     /** @var array */
     public $constValues;
 
@@ -49,6 +58,7 @@ class MarshalJson extends GoTemplate
         $this->type = $type;
         $this->builder = $builder;
         $this->code = new Code();
+        $this->marshalYaml = new MarshalYaml($type->getType(), $builder);
     }
 
     public function forbidAdditionalProperties()
@@ -86,12 +96,57 @@ class MarshalJson extends GoTemplate
         return $this;
     }
 
+    /**
+     * Returns names of union properties of a kind, e.g. "oneOf".
+     *
+     * @param string $kind
+     * @return string[]
+     */
+    public function getSomeOf($kind)
+    {
+        if (!isset($this->someOf[$kind])) {
+            return array();
+        }
+        return $this->someOf[$kind];
+    }
+
     public function addNamedProperty($name)
     {
         $this->propertyNames[] = $name;
         return $this;
     }
 
+    /**
+     * Adds a key that is not a property of this structure, but should not be treated as additional property.
+     *
+     * @param string $name
+     * @return $this
+     */
+    public function addKnownKey($name)
+    {
+        $this->knownKeys[$name] = $name;
+        return $this;
+    }
+
+    /**
+     * Limits keys that are decoded into embedded "allOf" part.
+     *
+     * @param string $name
+     * @param string[] $keys
+     * @return $this
+     */
+    public function setPartKeys($name, array $keys)
+    {
+        $this->partKeys[$name] = $keys;
+        return $this;
+    }
+
+    private function hasKnownKeys()
+    {
+        return ($this->propertyNames !== null || !empty($this->knownKeys)) &&
+            ($this->patternProperties || $this->additionalPropertiesEnabled !== null);
+    }
+
     private function receiver()
     {
         return $this->propertyNames === null ? ':receiver' : 'm:receiver';
@@ -99,12 +154,15 @@ class MarshalJson extends GoTemplate
 
     protected function toString()
     {
+        // Code is collected from scratch so that template can be rendered more than once.
+        $this->code = new Code();
+
         if ($this->patternProperties === null
             && $this->additionalPropertiesEnabled === null
             && $this->someOf === null
             && $this->constValues === null
             && (empty($this->required) || $this->builder->options->ignoreRequired || !$this->builder->options->validateRequired)) {
-            return '';
+            return $this->marshalYaml->render();
         }
 
         $result = '';
@@ -137,6 +195,7 @@ GO;
             ':type' => $this->type->getType(),
             ':receiver' => new Code(strtolower($this->type->getType()->getName()[0]))
         ]));
+        $this->code->addSnippet($this->marshalYaml);
 
         return $this->code;
     }
@@ -168,18 +227,20 @@ GO;
     {
         $result = '';
 
-        if (
-            $this->propertyNames !== null &&
-            ($this->patternProperties || $this->additionalPropertiesEnabled !== null)
-        ) {
+        if ($this->hasKnownKeys()) {
             // All known property names (including constant values) has to be removed from
             // pattern and additional properties matching.
-            $knownKeys = $this->propertyNames;
+            $knownKeys = $this->propertyNames === null ? [] : $this->propertyNames;
             if (!empty($this->constValues)) {
                 foreach ($this->constValues as $propertyName => $value) {
                     $knownKeys[] = $propertyName;
                 }
             }
+            foreach ($this->knownKeys as $key) {
+                if (!in_array($key, $knownKeys, true)) {
+                    $knownKeys[] = $key;
+                }
+            }
             $result .= <<<GO
 var knownKeys:type = []string{
 	"{$this->padLines("\t", implode("\",\n\"", $knownKeys))}",
@@ -216,14 +277,7 @@ GO;
 
         $mustUnmarshal = $this->renderMustUnmarshal();
         $mayUnmarshal = $this->renderTypeUnmarshal() . $this->renderAnyOfUnmarshal() . $this->renderOneOfUnmarshal();
-        $withKnownKeys = false; // TODO move to renderer
-
-        if (
-            $this->propertyNames !== null &&
-            ($this->patternProperties || $this->additionalPropertiesEnabled !== null)
-        ) {
-            $withKnownKeys = true;
-        }
+        $withKnownKeys = $this->hasKnownKeys(); // TODO move to renderer
 
         $mapUnmarshal = '';
         if ($this->patternProperties !== null
@@ -283,7 +337,7 @@ GO;
 
 if m:receiver.$goPropertyName == nil {
     if _, ok := rawMap[$name]; ok {
-        var v interface{}
+        var v {$this->builder->anyType()}
         m:receiver.$goPropertyName = &v
     }
 }
@@ -316,7 +370,7 @@ GO;
             foreach ($this->patternProperties as $regex => $patternProperty) {
                 $regexName = $this->builder->unmarshalUnion->patternVarName($regex);
                 $mapType = $patternProperty->getType();
-                $itemType = 'interface{}';
+                $itemType = $this->builder->anyType();
                 if ($mapType instanceof Map) {
                     $itemType = $mapType->getValueType()->render();
                 }
@@ -359,7 +413,7 @@ GO;
 
         if ($this->additionalPropertiesEnabled) {
             $mapType = $this->additionalProperties->getType();
-            $itemType = 'interface{}';
+            $itemType = $this->builder->anyType();
             if ($mapType instanceof Map) {
                 $itemType = $mapType->getValueType()->render();
             }
@@ -388,7 +442,19 @@ GO;
         // Additional properties forbidden.
         if ($this->additionalPropertiesEnabled === false) {
             $this->code->imports()->addByName('fmt');
-            $mapUnmarshal .= <<<'GO'
+            if ($this->builder->supports(GoVersion::SORTED_KEYS)) {
+                $this->code->imports()
+                    ->addByName('maps')
+                    ->addByName('slices');
+                $mapUnmarshal .= <<<'GO'
+
+if len(rawMap) != 0 {
+    return fmt.Errorf("additional properties not allowed in :type: %v", slices.Sorted(maps.Keys(rawMap)))
+}
+
+GO;
+            } else {
+                $mapUnmarshal .= <<<'GO'
 
 if len(rawMap) != 0 {
     offendingKeys := make([]string, 0, len(rawMap))
@@ -401,6 +467,7 @@ if len(rawMap) != 0 {
 }
 
 GO;
+            }
 
         }
 
@@ -525,10 +592,19 @@ GO;
 
         foreach ($this->someOf[$kind] as $propertyName) {
             $this->code->imports()->addByName('encoding/json');
+            $unmarshal = "json.Unmarshal(data, &{$this->receiver()}.{$propertyName})";
+            if (isset($this->partKeys[$propertyName])) {
+                $this->builder->unmarshalUnion->withPartKeys = true;
+                $keys = '';
+                foreach ($this->partKeys[$propertyName] as $key) {
+                    $keys .= ', ' . $this->escapeValue($key);
+                }
+                $unmarshal = "unmarshalPart(data, &{$this->receiver()}.{$propertyName}{$keys})";
+            }
             $result .= <<<GO
 
 
-err = json.Unmarshal(data, &{$this->receiver()}.{$propertyName})
+err = {$unmarshal}
 if err != nil {
     return err
 }
@@ -553,21 +629,24 @@ GO;
             ->addByName('fmt');
 
         $count = count($this->someOf[$kind]);
+        $joinErrors = $this->builder->supports(GoVersion::ERRORS_JOIN);
+        $errorsType = $joinErrors ? "make([]error, 0, $count)" : "make(map[string]error, $count)";
         $result .= <<<GO
 
 
-oneOfErrors := make(map[string]error, $count)
+oneOfErrors := $errorsType
 oneOfValid := 0
 
 GO;
 
         foreach ($this->someOf[$kind] as $i => $propertyName) {
+            $addError = $joinErrors ? 'oneOfErrors = append(oneOfErrors, err)' : "oneOfErrors[\"$i\"] = err";
             $result .= <<<GO
 
 
 err = json.Unmarshal(data, &{$this->receiver()}.{$propertyName})
 if err != nil {
-    oneOfErrors["$i"] = err
+    $addError
     {$this->receiver()}.{$propertyName} = nil
 } else {
     oneOfValid++
@@ -577,7 +656,22 @@ GO;
         }
 
         $this->code->imports()->addByName('fmt');
-        $result .= <<<'GO'
+        if ($joinErrors) {
+            $this->code->imports()->addByName('errors');
+            $result .= <<<'GO'
+
+
+if oneOfValid == 0 {
+    return fmt.Errorf("oneOf constraint failed for :type with 0 valid results: %w", errors.Join(oneOfErrors...))
+}
+
+if oneOfValid > 1 {
+    return fmt.Errorf("oneOf constraint failed for :type with %d valid results", oneOfValid)
+}
+
+GO;
+        } else {
+            $result .= <<<'GO'
 
 
 if oneOfValid != 1 {
@@ -585,6 +679,7 @@ if oneOfValid != 1 {
 }
 
 GO;
+        }
 
         return $result;
     }
@@ -599,21 +694,24 @@ GO;
         }
 
         $count = count($this->someOf[$kind]);
+        $joinErrors = $this->builder->supports(GoVersion::ERRORS_JOIN);
+        $errorsType = $joinErrors ? "make([]error, 0, $count)" : "make(map[string]error, $count)";
         $result .= <<<GO
 
 
-anyOfErrors := make(map[string]error, $count)
+anyOfErrors := $errorsType
 anyOfValid := 0
 
 GO;
 
         $this->code->imports()->addByName('encoding/json');
         foreach ($this->someOf[$kind] as $i => $propertyName) {
+            $addError = $joinErrors ? 'anyOfErrors = append(anyOfErrors, err)' : "anyOfErrors[\"$i\"] = err";
             $result .= <<<GO
 
 err = json.Unmarshal(data, &{$this->receiver()}.{$propertyName})
 if err != nil {
-    anyOfErrors["$i"] = err
+    $addError
     {$this->receiver()}.{$propertyName} = nil
 } else {
     anyOfValid++
@@ -623,7 +721,18 @@ GO;
         }
 
         $this->code->imports()->addByName('fmt');
-        $result .= <<<'GO'
+        if ($joinErrors) {
+            $this->code->imports()->addByName('errors');
+            $result .= <<<'GO'
+
+
+if anyOfValid == 0 {
+    return fmt.Errorf("anyOf constraint for :type failed with %d valid results: %w", anyOfValid, errors.Join(anyOfErrors...))
+}
+
+GO;
+        } else {
+            $result .= <<<'GO'
 
 
 if anyOfValid == 0 {
@@ -631,6 +740,7 @@ if anyOfValid == 0 {
 }
 
 GO;
+        }
 
         return $result;
     }
@@ -0,0 +1,81 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+
+use Swaggest\CodeBuilder\PlaceholderString;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * MarshalYaml renders gopkg.in/yaml.v3 marshaling that delegates to JSON marshaling of the type,
+ * so that const values, unions, enums, pattern and additional properties behave the same way.
+ */
+class MarshalYaml extends GoTemplate
+{
+    /** @var AnyType */
+    private $type;
+
+    /** @var GoBuilder */
+    private $builder;
+
+    /**
+     * MarshalYaml constructor.
+     * @param AnyType $type
+     * @param GoBuilder $builder
+     */
+    public function __construct(AnyType $type, GoBuilder $builder)
+    {
+        $this->type = $type;
+        $this->builder = $builder;
+
+        if ($builder->options->yamlMarshaling && !($builder->options->skipMarshal && $builder->options->skipUnmarshal)) {
+            // Helpers are added early as enum code may be rendered while builder code is being rendered.
+            $builder->getCode()->addSnippet(new YamlHelpers($builder), false, 'yaml_helpers');
+        }
+    }
+
+    protected function toString()
+    {
+        if (!$this->builder->options->yamlMarshaling) {
+            return '';
+        }
+
+        $result = '';
+        if (!$this->builder->options->skipUnmarshal) {
+            $result .= <<<'GO'
+// UnmarshalYAML decodes YAML node with JSON semantics.
+func (:receiver *:type) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, :receiver)
+}
+
+
+GO;
+        }
+
+        if (!$this->builder->options->skipMarshal) {
+            $result .= <<<'GO'
+// MarshalYAML encodes YAML node with JSON semantics.
+func (:receiver :type) MarshalYAML() (:any, error) {
+	return marshalYAML(:receiver)
+}
+
+
+GO;
+        }
+
+        if ($result === '') {
+            return '';
+        }
+
+        $code = new Code(new PlaceholderString($result, [
+            ':type' => $this->type,
+            ':receiver' => new Code(strtolower($this->type->getTypeString()[0])),
+            ':any' => new Code($this->builder->anyType()),
+        ]));
+        $code->imports()->addByName('gopkg.in/yaml.v3');
+
+        return $code;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,106 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+
+/**
+ * MirrorTags copies `json` field tag decisions into other tag families.
+ */
+class MirrorTags
+{
+    /**
+     * Families that support `omitempty` option.
+     * @var bool[]
+     */
+    private static $omitEmpty = [
+        'yaml' => true,
+        'bson' => true,
+        'mapstructure' => true,
+        'query' => true,
+        'xml' => true,
+    ];
+
+    /**
+     * Options to flatten embedded structure.
+     * @var string[]
+     */
+    private static $inline = [
+        'yaml' => 'inline',
+        'bson' => 'inline',
+        'mapstructure' => 'squash',
+    ];
+
+    /**
+     * @param StructProperty $goProperty
+     * @param string[] $families
+     */
+    public static function apply(StructProperty $goProperty, array $families)
+    {
+        if (empty($families)) {
+            return;
+        }
+
+        $tags = $goProperty->getTags();
+
+        if ($goProperty->isEmbedded()) {
+            foreach ($families as $family) {
+                if (isset(self::$inline[$family])) {
+                    $tags->setTag($family, ',' . self::$inline[$family]);
+                }
+            }
+            return;
+        }
+
+        $json = $tags->getTag('json');
+        if ($json === null) {
+            return;
+        }
+
+        $parts = explode(',', $json);
+        $name = $parts[0];
+        // Zero structures are also omitted by `omitempty` of other families.
+        $omitEmpty = in_array('omitempty', $parts, true) || in_array('omitzero', $parts, true);
+
+        foreach ($families as $family) {
+            $value = $name;
+            if ($name !== '-' && $omitEmpty && isset(self::$omitEmpty[$family])) {
+                $value .= ',omitempty';
+            }
+            $tags->setTag($family, $value);
+        }
+    }
+
+    /**
+     * Applies tags from `x-go-tags` schema extension.
+     *
+     * Value can be an object with tag names as keys, e.g. {"yaml": "name,flow", "db": null},
+     * null value removes the tag. Value can also be a string, e.g. "yaml:\"name,flow\" db:\"-\"".
+     *
+     * @param StructProperty $goProperty
+     * @param \stdClass|array|string $xGoTags
+     */
+    public static function applyExtension(StructProperty $goProperty, $xGoTags)
+    {
+        $tags = $goProperty->getTags();
+
+        if (is_string($xGoTags)) {
+            $xGoTags = trim($xGoTags, " `");
+            if (!preg_match_all('/([\w-]+):"((?:[^"\\\\]|\\\\.)*)"/', $xGoTags, $matches, PREG_SET_ORDER)) {
+                return;
+            }
+            foreach ($matches as $match) {
+                $tags->setTag($match[1], $match[2]);
+            }
+            return;
+        }
+
+        foreach ((array)$xGoTags as $key => $value) {
+            if ($value === null) {
+                $tags->unsetTag($key);
+            } elseif (is_scalar($value)) {
+                $tags->setTag($key, (string)$value);
+            }
+        }
+    }
+}
\ No newline at end of file
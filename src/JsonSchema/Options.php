@@ -126,6 +126,49 @@ class Options extends ClassStructure
      */
     public $nameTags = [];
 
+    /**
+     * Reject unknown keys when unmarshaling, regardless of `additionalProperties`.
+     * @var bool
+     */
+    public $strictUnmarshal = false;
+
+    /**
+     * Schema paths to reject unknown keys for when unmarshaling, paths are the ones of "generated from" comments,
+     * e.g. "#/definitions/Order" for referenced schema or "#->order" for inline property schema.
+     * @var string[]
+     */
+    public $strictUnmarshalPaths = [];
+
+    /**
+     * Add field tags for github.com/swaggest/jsonschema-go to reflect schema constraints.
+     * @var bool
+     */
+    public $jsonSchemaTags = false;
+
+    /**
+     * Add `validate` field tags for github.com/go-playground/validator.
+     * @var bool
+     */
+    public $validateTags = false;
+
+    /**
+     * Set additional field tags mirroring `json` tag, e.g. ["yaml","bson","mapstructure","db","form","query","xml"].
+     * @var string[]
+     */
+    public $mirrorTags = [];
+
+    /**
+     * Generate gopkg.in/yaml.v3 marshaling with the same semantics as JSON marshaling.
+     * @var bool
+     */
+    public $yamlMarshaling = false;
+
+    /**
+     * Minimum Go version of generated code, e.g. "1.18" to use `any`, "1.24" for `omitzero`, older idioms by default.
+     * @var string
+     */
+    public $goVersion;
+
     /**
      * @param Properties|static $properties
      * @param Schema $ownerSchema
@@ -170,5 +213,19 @@ class Options extends ClassStructure
             ->setDescription('Only generate schemas that have `x-generate: true`.');
         $properties->nameTags = Schema::arr()->setItems(Schema::string())
             ->setDescription('Set additional field tags with property name.');
+        $properties->strictUnmarshal = Schema::boolean()
+            ->setDescription('Reject unknown keys when unmarshaling, regardless of `additionalProperties`.');
+        $properties->strictUnmarshalPaths = Schema::arr()->setItems(Schema::string())
+            ->setDescription('Schema paths to reject unknown keys for when unmarshaling, e.g. "#/definitions/Order" for referenced schema or "#->order" for inline property schema.');
+        $properties->jsonSchemaTags = Schema::boolean()
+            ->setDescription('Add field tags for github.com/swaggest/jsonschema-go to reflect schema constraints.');
+        $properties->validateTags = Schema::boolean()
+            ->setDescription('Add `validate` field tags for github.com/go-playground/validator.');
+        $properties->mirrorTags = Schema::arr()->setItems(Schema::string())
+            ->setDescription('Set additional field tags mirroring `json` tag, e.g. ["yaml","bson","mapstructure","db","form","query","xml"].');
+        $properties->yamlMarshaling = Schema::boolean()
+            ->setDescription('Generate gopkg.in/yaml.v3 marshaling with the same semantics as JSON marshaling.');
+        $properties->goVersion = Schema::string()
+            ->setDescription('Minimum Go version of generated code, e.g. "1.18" to use `any`, "1.24" for `omitzero`, older idioms by default.');
     }
 }
\ No newline at end of file
@@ -0,0 +1,192 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoFmt;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Registry renders a map of identifiers to factories of generated types and a function to decode JSON by identifier.
+ *
+ * Generated structures are registered by `$id` of their schemas and by discriminator values, identifiers
+ * can also be added explicitly, e.g. AsyncAPI message names.
+ */
+class Registry extends GoTemplate
+{
+    /** @var GoBuilder */
+    private $builder;
+
+    /** @var string */
+    private $registryName = 'Registry';
+
+    /** @var string */
+    private $decodeName = 'Decode';
+
+    /** @var string|null */
+    private $discriminator;
+
+    /** @var AnyType[] by identifier */
+    private $types = array();
+
+    /**
+     * Registry constructor.
+     * @param GoBuilder $builder
+     */
+    public function __construct(GoBuilder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    /**
+     * @param string $registryName
+     * @return Registry
+     */
+    public function setRegistryName($registryName)
+    {
+        $this->registryName = $registryName;
+        return $this;
+    }
+
+    /**
+     * @param string $decodeName
+     * @return Registry
+     */
+    public function setDecodeName($decodeName)
+    {
+        $this->decodeName = $decodeName;
+        return $this;
+    }
+
+    /**
+     * @return string
+     */
+    public function getDecodeName()
+    {
+        return $this->decodeName;
+    }
+
+    /**
+     * Sets name of property that identifies structure with its constant value.
+     *
+     * @param string $discriminator
+     * @return Registry
+     */
+    public function setDiscriminator($discriminator)
+    {
+        $this->discriminator = $discriminator;
+        return $this;
+    }
+
+    /**
+     * Adds identifier of type, first type wins when identifier is added again.
+     *
+     * @param string $id
+     * @param AnyType $type
+     * @return Registry
+     */
+    public function add($id, AnyType $type)
+    {
+        if (!isset($this->types[$id])) {
+            $this->types[$id] = $type;
+        }
+        return $this;
+    }
+
+    /**
+     * Returns types by identifier, explicitly added identifiers are followed by identifiers of generated structures.
+     *
+     * @return AnyType[]
+     */
+    public function getTypes()
+    {
+        $types = $this->types;
+        foreach ($this->builder->getGeneratedStructs() as $generatedStruct) {
+            $type = $generatedStruct->structDef->getType();
+            foreach ($this->structIDs($generatedStruct) as $id) {
+                if (!isset($types[$id])) {
+                    $types[$id] = $type;
+                }
+            }
+        }
+
+        return $types;
+    }
+
+    /**
+     * @param GeneratedStruct $generatedStruct
+     * @return string[]
+     */
+    private function structIDs(GeneratedStruct $generatedStruct)
+    {
+        $ids = array();
+        $schema = $generatedStruct->schema;
+        if (is_string($schema->id) && $schema->id !== '') {
+            $ids[] = $schema->id;
+        }
+
+        $discriminator = $this->discriminator;
+        if ($discriminator !== null && isset($schema->properties->$discriminator)) {
+            $property = $schema->properties->$discriminator;
+            if (!$property instanceof Schema) {
+                return $ids;
+            }
+
+            if (is_string($property->const)) {
+                $ids[] = $property->const;
+            } elseif (is_array($property->enum) && count($property->enum) === 1 && is_string($property->enum[0])) {
+                $ids[] = $property->enum[0];
+            }
+        }
+
+        return $ids;
+    }
+
+    protected function toString()
+    {
+        $types = $this->getTypes();
+        if (empty($types)) {
+            return '';
+        }
+
+        $code = new Code();
+        $code->imports()->addByName('encoding/json');
+        $code->imports()->addByName('fmt');
+
+        $any = $this->builder->anyType();
+        $elements = '';
+        foreach ($types as $id => $type) {
+            $elements .= "\t" . Literal::quote((string)$id) . ": func() {$any} { return new({$type->render()}) },\n";
+        }
+
+        // Elements are aligned by GoFmt.
+        $code->addSnippet(GoFmt::format(<<<GO
+// {$this->registryName} maps identifiers to factories of generated types.
+var {$this->registryName} = map[string]func() {$any}{
+{$elements}}
+
+// {$this->decodeName} decodes JSON data into a new instance of type registered with id.
+//
+// Result is a pointer to registered type.
+func {$this->decodeName}(id string, data []byte) ({$any}, error) {
+	factory, ok := {$this->registryName}[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown type id %q", id)
+	}
+
+	v := factory()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+GO
+        ) . "\n");
+
+        return $code;
+    }
+}
@@ -0,0 +1,144 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * SchemaTags maps schema keywords to field tags of github.com/swaggest/jsonschema-go.
+ */
+class SchemaTags
+{
+    /**
+     * Keywords with scalar values that have tag equivalents.
+     * @var string[]
+     */
+    private static $scalarKeywords = [
+        'title',
+        'description',
+        'format',
+        'pattern',
+        'minLength',
+        'maxLength',
+        'minimum',
+        'maximum',
+        'multipleOf',
+        'minItems',
+        'maxItems',
+        'uniqueItems',
+        'minProperties',
+        'maxProperties',
+    ];
+
+    /**
+     * @param StructProperty $goProperty
+     * @param Schema $property
+     * @param bool $isRequired
+     */
+    public static function addToProperty(StructProperty $goProperty, Schema $property, $isRequired)
+    {
+        foreach (self::keywords($property, $isRequired) as $keyword => $value) {
+            $value = self::tagValue($value);
+            if ($value === null) {
+                continue;
+            }
+            $goProperty->getTags()->setTag($keyword, $value);
+        }
+    }
+
+    /**
+     * Returns keywords of property schema that can be expressed with tags.
+     *
+     * @param Schema $property
+     * @param bool $isRequired
+     * @return array
+     */
+    public static function keywords(Schema $property, $isRequired)
+    {
+        $result = [];
+        if ($isRequired) {
+            $result['required'] = true;
+        }
+
+        foreach (self::$scalarKeywords as $keyword) {
+            if (isset($property->$keyword) && is_scalar($property->$keyword)) {
+                $result[$keyword] = $property->$keyword;
+            }
+        }
+
+        // Exclusive limits are rendered in draft 6+ numeric form, draft 4 boolean form is converted.
+        if ($property->exclusiveMinimum === true && isset($result['minimum'])) {
+            $result['exclusiveMinimum'] = $result['minimum'];
+            unset($result['minimum']);
+        } elseif (is_int($property->exclusiveMinimum) || is_float($property->exclusiveMinimum)) {
+            $result['exclusiveMinimum'] = $property->exclusiveMinimum;
+        }
+
+        if ($property->exclusiveMaximum === true && isset($result['maximum'])) {
+            $result['exclusiveMaximum'] = $result['maximum'];
+            unset($result['maximum']);
+        } elseif (is_int($property->exclusiveMaximum) || is_float($property->exclusiveMaximum)) {
+            $result['exclusiveMaximum'] = $property->exclusiveMaximum;
+        }
+
+        if ($property->default !== null && is_scalar($property->default)) {
+            $result['default'] = $property->default;
+        }
+
+        if (!empty($property->enum)) {
+            $scalar = true;
+            foreach ($property->enum as $item) {
+                if (!is_scalar($item) || (is_string($item) && strpos($item, ',') !== false)) {
+                    $scalar = false;
+                    break;
+                }
+            }
+            if ($scalar) {
+                $result['enum'] = $property->enum;
+            }
+        }
+
+        return $result;
+    }
+
+    /**
+     * Renders value as tag value, returns null if value can not be used in tag.
+     *
+     * @param mixed $value
+     * @return null|string
+     */
+    private static function tagValue($value)
+    {
+        if (is_array($value)) {
+            $items = [];
+            foreach ($value as $item) {
+                $items[] = self::scalarString($item);
+            }
+            $value = implode(',', $items);
+        } else {
+            $value = self::scalarString($value);
+        }
+
+        // Struct tag is a raw string literal, backtick can not be escaped.
+        if (strpos($value, '`') !== false) {
+            return null;
+        }
+
+        return str_replace(["\\", '"', "\n", "\t", "\r"], ["\\\\", '\"', '\n', '\t', '\r'], $value);
+    }
+
+    private static function scalarString($value)
+    {
+        if ($value === true) {
+            return 'true';
+        }
+        if ($value === false) {
+            return 'false';
+        }
+        if (is_float($value)) {
+            return json_encode($value);
+        }
+        return (string)$value;
+    }
+}
\ No newline at end of file
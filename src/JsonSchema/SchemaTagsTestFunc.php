@@ -0,0 +1,129 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\CodeBuilder\PlaceholderString;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructType;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * SchemaTagsTestFunc makes a test to check that schema reflected from generated structure
+ * is equivalent to the source schema.
+ */
+class SchemaTagsTestFunc
+{
+    /**
+     * @param GeneratedStruct $struct
+     * @return FuncDef|null
+     */
+    public static function make(GeneratedStruct $struct)
+    {
+        $schema = $struct->schema;
+        if ($schema->properties === null) {
+            return null;
+        }
+
+        $required = [];
+        $properties = new \stdClass();
+        foreach ($struct->structDef->getProperties() as $goProperty) {
+            if ($goProperty->isEmbedded()) {
+                continue;
+            }
+
+            $name = self::propertyName($goProperty->getTags()->getTag('json'));
+            if ($name === null || !isset($schema->properties->$name)) {
+                continue;
+            }
+
+            $property = $schema->properties->$name;
+            if (!$property instanceof Schema) {
+                continue;
+            }
+
+            $isRequired = is_array($schema->required) && in_array($name, $schema->required, true);
+            if ($isRequired) {
+                $required[] = $name;
+            }
+
+            // Referenced structures are reflected as `$ref` without property keywords.
+            if (Pointer::tryDereferenceOnce($goProperty->getType()) instanceof StructType) {
+                continue;
+            }
+
+            $keywords = SchemaTags::keywords($property, false);
+            if (!empty($keywords)) {
+                $properties->$name = $keywords;
+            }
+        }
+
+        $expected = json_encode(['required' => $required, 'properties' => $properties], JSON_UNESCAPED_SLASHES);
+
+        $f = new FuncDef('Test' . $struct->structDef->getName() . '_JSONSchema');
+        $f->setArguments((new Arguments())->add('t', TypeUtil::fromString('*testing.T')));
+
+        $c = new Code(new PlaceholderString(<<<GO
+type schema struct {
+	Required   []string                          `json:"required"`
+	Properties map[string]map[string]interface{} `json:"properties"`
+}
+
+var (
+	reflector        = jsonschema.Reflector{}
+	expected, actual schema
+)
+
+s, err := reflector.Reflect(:type{})
+require.NoError(t, err)
+
+j, err := json.Marshal(s)
+require.NoError(t, err)
+
+require.NoError(t, json.Unmarshal(j, &actual))
+require.NoError(t, json.Unmarshal([]byte(`$expected`), &expected))
+
+assert.ElementsMatch(t, expected.Required, actual.Required)
+
+for name, keywords := range expected.Properties {
+	for keyword, value := range keywords {
+		assert.Equal(t, value, actual.Properties[name][keyword], name+"."+keyword)
+	}
+}
+GO
+            , [
+                ':type' => $struct->structDef->getType(),
+            ]));
+
+        $c->imports()
+            ->addByName('encoding/json')
+            ->addByName('github.com/stretchr/testify/assert')
+            ->addByName('github.com/stretchr/testify/require')
+            ->addByName('github.com/swaggest/jsonschema-go');
+
+        $f->setBody($c);
+
+        return $f;
+    }
+
+    /**
+     * @param string|null $jsonTag
+     * @return string|null
+     */
+    private static function propertyName($jsonTag)
+    {
+        if ($jsonTag === null) {
+            return null;
+        }
+
+        $parts = explode(',', $jsonTag);
+        if ($parts[0] === '-' || $parts[0] === '') {
+            return null;
+        }
+
+        return $parts[0];
+    }
+}
\ No newline at end of file
@@ -28,6 +28,7 @@ class TypeBuilder
     const X_OMIT_EMPTY = 'x-omitempty';
     const X_NULLABLE = 'x-nullable';
     const X_GENERATE = 'x-generate';
+    const X_GO_TAGS = 'x-go-tags';
     const NULLABLE = 'nullable';
     const EXAMPLES = 'examples';
     const EXAMPLE = 'example';
@@ -86,12 +87,12 @@ class TypeBuilder
             return $name;
         }
         if ($type instanceof NamedType) {
-            if ($type->getName() === 'interface{}') {
+            if (TypeUtil::isAny($type)) {
                 return self::NAME_ANY;
             }
             return $type->getName();
         }
-        if ($type->getTypeString() === '[]interface{}') {
+        if ($type instanceof Slice && TypeUtil::isAny($type->getType())) {
             return 'SliceOf_' . self::NAME_ANY;
         }
         if ($type instanceof Slice) {
@@ -146,7 +147,8 @@ class TypeBuilder
     private function processAllOfEmbeddableObject($allOfs)
     {
         $allProperties = array();
-        foreach ($allOfs as $allOf) {
+        $partKeys = array();
+        foreach ($allOfs as $i => $allOf) {
             if (!$allOf instanceof Schema) {
                 return false;
             }
@@ -161,22 +163,59 @@ class TypeBuilder
                 }
 
                 $allProperties[$propertyName] = true;
+                $partKeys[$i][] = $propertyName;
             }
         }
 
+        $strict = $this->goBuilder->isStrict($this->schema, $this->path);
+
         $result = $this->makeResultStruct();
+        $parts = [];
         foreach ($allOfs as $i => $allOf) {
             if (!$allOf instanceof Schema) {
                 return false;
             }
 
             $type = $this->goBuilder->getType($allOf, $this->path . '/allOf/' . $i, $result);
-            $result->addProperty(new StructProperty(null, $type));
+            $structProperty = new StructProperty(null, $type);
+            MirrorTags::apply($structProperty, $this->goBuilder->options->mirrorTags);
+            $result->addProperty($structProperty);
+            $parts[$this->embeddedName($type)] = $partKeys[$i];
+        }
+
+        if ($strict) {
+            // Parts are shared with other structures, so only parent structure rejects unknown keys,
+            // each part is decoded from its own keys.
+            $marshalJson = $this->getGeneratedStruct()->marshalJson;
+            foreach ($parts as $name => $keys) {
+                $marshalJson->addSomeOf(Schema::names()->allOf, $name);
+                $marshalJson->setPartKeys($name, $keys);
+            }
+            foreach ($allProperties as $propertyName => $true) {
+                $marshalJson->addKnownKey($propertyName);
+            }
+            $marshalJson->forbidAdditionalProperties();
         }
 
         return true;
     }
 
+    /**
+     * Returns field name of embedded type, e.g. "Type" for "*pkg.Type".
+     *
+     * @param AnyType $type
+     * @return string
+     */
+    private function embeddedName(AnyType $type)
+    {
+        $fieldType = Pointer::tryDereferenceOnce($type);
+        if ($fieldType instanceof GoType) {
+            return $fieldType->getName();
+        }
+
+        return $type->getTypeString();
+    }
+
     /**
      * @param Schema[]|SchemaContract[] $orSchemas
      * @param string|mixed $kind
@@ -197,7 +236,7 @@ class TypeBuilder
                     $path = $this->path . '[' . $item->type . ']';
                 }
                 $itemType = Pointer::tryDereferenceOnce($this->goBuilder->getType($item, $path));
-                if ($itemType->getTypeString() === 'interface{}') {
+                if (TypeUtil::isAny($itemType)) {
                     continue;
                 }
                 $types [] = $itemType;
@@ -256,7 +295,7 @@ class TypeBuilder
                 }
 
                 if ((!$itemType instanceof Map) && (!$itemType instanceof Slice)) {
-                    if ($itemType->getTypeString() === 'interface{}') {
+                    if (TypeUtil::isAny($itemType)) {
                         continue;
                     }
                     $itemType = new Pointer($itemType);
@@ -266,6 +305,7 @@ class TypeBuilder
                 $structProperty = new StructProperty($name, $itemType);
 //                $structProperty->setComment($path);
                 $structProperty->getTags()->setTag('json', '-');
+                MirrorTags::apply($structProperty, $this->goBuilder->options->mirrorTags);
                 $resultStruct->addProperty($structProperty);
 
                 if ($this->goBuilder->options->fluentSetters) {
@@ -363,6 +403,7 @@ class TypeBuilder
                     )
                 );
                 $structProperty->getTags()->setTag('json', '-');
+                MirrorTags::apply($structProperty, $this->goBuilder->options->mirrorTags);
                 $structProperty->setComment('Key must match pattern: `' . $pattern . '`.');
 
                 $resultStruct = $this->makeResultStruct();
@@ -381,6 +422,14 @@ class TypeBuilder
         if ($this->goBuilder->options->defaultAdditionalProperties && $additionalProperties === null) {
             $additionalProperties = true;
         }
+
+        // Strict mode rejects keys that are not declared properties or pattern matches.
+        if (
+            ($this->schema->properties !== null || $this->schema->patternProperties !== null)
+            && $this->goBuilder->isStrict($this->schema, $this->path)
+        ) {
+            $additionalProperties = false;
+        }
         if (
             $additionalProperties instanceof Schema ||
             $additionalProperties === true
@@ -393,7 +442,7 @@ class TypeBuilder
                         $this->path . '->' . Schema::names()->additionalProperties)
                 );
             } else {
-                $goType = TypeUtil::fromString('interface{}');
+                $goType = new GoType($this->goBuilder->anyType());
             }
 
             if (
@@ -428,6 +477,7 @@ class TypeBuilder
                     );
                     $structProperty->setComment('All unmatched properties.');
                     $structProperty->getTags()->setTag('json', '-');
+                    MirrorTags::apply($structProperty, $this->goBuilder->options->mirrorTags);
                     $resultStruct->addProperty($structProperty);
 
                     if ($this->goBuilder->options->fluentSetters) {
@@ -496,13 +546,13 @@ class TypeBuilder
                 return new GoType('string');
 
             case Type::OBJECT:
-                return TypeUtil::fromString('map[string]interface{}');
+                return TypeUtil::fromString('map[string]' . $this->goBuilder->anyType());
 
             case Type::ARR:
-                return TypeUtil::fromString('[]interface{}');
+                return TypeUtil::fromString('[]' . $this->goBuilder->anyType());
 
             case Type::NULL:
-                return new GoType('interface{}');
+                return new GoType($this->goBuilder->anyType());
 
             default:
                 return null;
@@ -538,7 +588,7 @@ class TypeBuilder
 
             $type = new GoType($typeName);
 
-            $baseType = new GoType('interface{}');
+            $baseType = new GoType($this->goBuilder->anyType());
             if (is_string($this->schema->const)) {
                 $baseType = new GoType('string');
             } elseif (is_int($this->schema->const)) {
@@ -765,9 +815,13 @@ GO
                 if (isset($xGoType->type)) {
                     $typeString .= $xGoType->type;
                 }
-                return TypeUtil::fromString($typeString);
+                $type = TypeUtil::fromString($typeString);
+                $this->goBuilder->checkXGoTypeImports($type, $this->path, $this->schema);
+                return $type;
             } elseif (is_string($xGoType)) {
-                return TypeUtil::fromString($xGoType);
+                $type = TypeUtil::fromString($xGoType);
+                $this->goBuilder->checkXGoTypeImports($type, $this->path, $this->schema);
+                return $type;
             }
         }
 
@@ -867,7 +921,7 @@ GO
                     (!$type instanceof Map) &&
                     (!$type instanceof Slice) &&
                     (!$this->isRequired || $this->goBuilder->options->ignoreRequired || $this->nullable) &&
-                    ($type->getTypeString() !== 'interface{}')
+                    !TypeUtil::isAny($type)
                 ) {
                     $type = new Pointer($type);
                     if ($this->nullable && !$this->goBuilder->options->ignoreNullable) {
@@ -892,7 +946,7 @@ GO
         }
 
         if (empty($this->result)) {
-            return new GoType('interface{}');
+            return new GoType($this->goBuilder->anyType());
         } else {
             if (1 === count($this->result)) {
                 return $this->result[0];
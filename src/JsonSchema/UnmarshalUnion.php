@@ -13,6 +13,8 @@ class UnmarshalUnion extends GoTemplate
 
     public $withPatternProperties;
 
+    public $withPartKeys;
+
     public $patterns = [];
     public $regexNames = [];
 
@@ -53,6 +55,38 @@ var (
 $var)
 
 
+GO
+            );
+        }
+
+        if ($this->withPartKeys) {
+            $code->addSnippet(<<<GO
+// unmarshalPart decodes only given keys of JSON object into embedded value.
+func unmarshalPart(data []byte, v {$this->goBuilder->anyType()}, keys ...string) error {
+	var rawMap map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &rawMap)
+	if err != nil {
+		return json.Unmarshal(data, v)
+	}
+
+	part := make(map[string]json.RawMessage, len(keys))
+
+	for _, key := range keys {
+		if value, ok := rawMap[key]; ok {
+			part[key] = value
+		}
+	}
+
+	data, err = json.Marshal(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+
 GO
             );
         }
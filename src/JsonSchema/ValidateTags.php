@@ -0,0 +1,197 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * ValidateTags maps schema keywords to `validate` field tag of github.com/go-playground/validator.
+ */
+class ValidateTags
+{
+    const TAG = 'validate';
+
+    /**
+     * Formats with validator equivalents.
+     * @var string[]
+     */
+    private static $formats = [
+        'email' => 'email',
+        'uuid' => 'uuid',
+        'uri' => 'uri',
+        'url' => 'url',
+        'ipv4' => 'ipv4',
+        'ipv6' => 'ipv6',
+        'ip' => 'ip',
+        'hostname' => 'hostname_rfc1123',
+    ];
+
+    /**
+     * Keywords without validator equivalents.
+     * @var string[]
+     */
+    private static $unsupported = [
+        'pattern',
+        'multipleOf',
+        'const',
+        'contains',
+        'propertyNames',
+        'dependencies',
+        'patternProperties',
+    ];
+
+    /** @var GoBuilder */
+    private $goBuilder;
+
+    /**
+     * ValidateTags constructor.
+     * @param GoBuilder $goBuilder
+     */
+    public function __construct(GoBuilder $goBuilder)
+    {
+        $this->goBuilder = $goBuilder;
+    }
+
+    /**
+     * @param StructProperty $goProperty
+     * @param Schema $property
+     * @param bool $isRequired
+     * @param string $path
+     */
+    public function addToProperty(StructProperty $goProperty, Schema $property, $isRequired, $path)
+    {
+        $rules = $this->rules($property, $path);
+
+        if ($isRequired) {
+            array_unshift($rules, 'required');
+        } elseif (!empty($rules)) {
+            array_unshift($rules, 'omitempty');
+        }
+
+        if (!empty($rules)) {
+            $goProperty->getTags()->setTag(self::TAG, implode(',', $rules));
+        }
+    }
+
+    /**
+     * @param Schema $schema
+     * @param string $path
+     * @return string[]
+     */
+    private function rules(Schema $schema, $path)
+    {
+        $rules = [];
+
+        foreach (['minLength' => 'min', 'minItems' => 'min', 'minProperties' => 'min'] as $keyword => $rule) {
+            if ($schema->$keyword !== null) {
+                $rules[$rule] = $rule . '=' . $schema->$keyword;
+            }
+        }
+
+        foreach (['maxLength' => 'max', 'maxItems' => 'max', 'maxProperties' => 'max'] as $keyword => $rule) {
+            if ($schema->$keyword !== null) {
+                $rules[$rule] = $rule . '=' . $schema->$keyword;
+            }
+        }
+
+        // Equal limits are expressed with exact length.
+        if (isset($rules['min'], $rules['max']) && substr($rules['min'], 4) === substr($rules['max'], 4)) {
+            $rules = ['len' => 'len=' . substr($rules['min'], 4)];
+        }
+
+        if ($schema->minimum !== null) {
+            $rules[] = ($schema->exclusiveMinimum === true ? 'gt=' : 'gte=') . $schema->minimum;
+        }
+        if (is_int($schema->exclusiveMinimum) || is_float($schema->exclusiveMinimum)) {
+            $rules[] = 'gt=' . $schema->exclusiveMinimum;
+        }
+
+        if ($schema->maximum !== null) {
+            $rules[] = ($schema->exclusiveMaximum === true ? 'lt=' : 'lte=') . $schema->maximum;
+        }
+        if (is_int($schema->exclusiveMaximum) || is_float($schema->exclusiveMaximum)) {
+            $rules[] = 'lt=' . $schema->exclusiveMaximum;
+        }
+
+        if ($schema->uniqueItems === true) {
+            $rules[] = 'unique';
+        }
+
+        if ($schema->format !== null) {
+            if (isset(self::$formats[$schema->format])) {
+                $rules[] = self::$formats[$schema->format];
+            } elseif ($schema->format !== 'date-time') { // Date-time values are decoded into time.Time.
+                $this->report('format', $path, $schema);
+            }
+        }
+
+        if ($schema->enum !== null) {
+            $oneOf = $this->oneOf($schema->enum);
+            if ($oneOf !== null) {
+                $rules[] = $oneOf;
+            } else {
+                $this->report('enum', $path, $schema);
+            }
+        }
+
+        foreach (self::$unsupported as $keyword) {
+            if ($schema->$keyword !== null) {
+                $this->report($keyword, $path, $schema);
+            }
+        }
+
+        $rules = array_values($rules);
+
+        $dive = null;
+        if ($schema->items instanceof Schema) {
+            $dive = $this->rules($schema->items, $path . '->' . Schema::names()->items);
+        } elseif ($schema->additionalProperties instanceof Schema) {
+            $dive = $this->rules($schema->additionalProperties, $path . '->' . Schema::names()->additionalProperties);
+        }
+
+        if (!empty($dive)) {
+            $rules[] = 'dive';
+            foreach ($dive as $rule) {
+                $rules[] = $rule;
+            }
+        }
+
+        return $rules;
+    }
+
+    /**
+     * @param array $enum
+     * @return string|null
+     */
+    private function oneOf(array $enum)
+    {
+        $values = [];
+        foreach ($enum as $item) {
+            if (is_string($item)) {
+                if ($item === '' || preg_match('/[\s,|`"\'\\\\]/', $item)) {
+                    return null;
+                }
+                $values[] = $item;
+            } elseif (is_int($item) || is_float($item)) {
+                $values[] = $item;
+            } else {
+                return null;
+            }
+        }
+
+        return 'oneof=' . implode(' ', $values);
+    }
+
+    /**
+     * @param string $keyword
+     * @param string $path
+     * @param Schema $schema
+     */
+    private function report($keyword, $path, Schema $schema)
+    {
+        if ($this->goBuilder->unsupportedKeywordHook !== null) {
+            $this->goBuilder->unsupportedKeywordHook->process($keyword, $path, $schema);
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,26 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+
+class WarningHookCallback implements GoBuilderWarningHook
+{
+    /** @var \Closure */
+    private $closure;
+
+    /**
+     * WarningHookCallback constructor.
+     * @param \Closure $closure
+     */
+    public function __construct(\Closure $closure)
+    {
+        $this->closure = $closure;
+    }
+
+    public function process($message, $path, $schema)
+    {
+        $this->closure->__invoke($message, $path, $schema);
+    }
+
+
+}
\ No newline at end of file
@@ -0,0 +1,130 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\JsonSchema;
+
+
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+class YamlHelpers extends GoTemplate
+{
+    /** @var GoBuilder */
+    private $builder;
+
+    /**
+     * YamlHelpers constructor.
+     * @param GoBuilder $builder
+     */
+    public function __construct(GoBuilder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    protected function toString()
+    {
+        $code = new Code();
+        $code->imports()
+            ->addByName('encoding/json')
+            ->addByName('gopkg.in/yaml.v3');
+
+        if (!$this->builder->options->skipUnmarshal) {
+            $code->imports()->addByName('fmt');
+            $code->addSnippet($this->withAnyType(<<<'GO'
+// unmarshalYAML decodes YAML node into JSON value and unmarshals it with JSON rules.
+func unmarshalYAML(node *yaml.Node, v interface{}) error {
+	var raw interface{}
+
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(yamlToJSONValue(raw))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// yamlToJSONValue converts YAML maps with non-string keys into JSON objects.
+func yamlToJSONValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = yamlToJSONValue(item)
+		}
+
+		return val
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res[fmt.Sprintf("%v", k)] = yamlToJSONValue(item)
+		}
+
+		return res
+	case []interface{}:
+		for i, item := range val {
+			val[i] = yamlToJSONValue(item)
+		}
+
+		return val
+	default:
+		return v
+	}
+}
+
+
+GO
+            ));
+        }
+
+        if (!$this->builder->options->skipMarshal) {
+            $code->addSnippet($this->withAnyType(<<<'GO'
+// marshalYAML encodes value with JSON rules and converts result to YAML node.
+func marshalYAML(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+
+	blockYAMLStyle(&node)
+
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		return node.Content[0], nil
+	}
+
+	return &node, nil
+}
+
+// blockYAMLStyle replaces JSON flow and quoted styles with default YAML styles.
+func blockYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, n := range node.Content {
+		blockYAMLStyle(n)
+	}
+}
+
+
+GO
+            ));
+        }
+
+        return $code;
+    }
+
+    /**
+     * @param string $code
+     * @return string
+     */
+    private function withAnyType($code)
+    {
+        return str_replace('interface{}', $this->builder->anyType(), $code);
+    }
+}
\ No newline at end of file
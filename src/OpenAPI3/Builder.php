@@ -0,0 +1,705 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Style\Comment;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructType;
+use Swaggest\GoCodeBuilder\Templates\Struct\Tags;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Map;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Slice;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+use Swaggest\JsonSchema\Context;
+use Swaggest\JsonSchema\RemoteRef\Preloaded;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder walks operations of OpenAPI 3 document and builds request and response structures.
+ *
+ * Schemas of parameters and bodies are processed with GoBuilder.
+ */
+class Builder
+{
+    /** Document URL for schema references. */
+    const DOCUMENT_URL = 'openapi.json';
+
+    const FORM_URLENCODED = 'application/x-www-form-urlencoded';
+    const FORM_MULTIPART = 'multipart/form-data';
+
+    /** @var string[] HTTP methods of path item in order of specification */
+    public static $methods = array('get', 'put', 'post', 'delete', 'options', 'head', 'patch', 'trace');
+
+    /** @var string[] names of response fields by status code */
+    public static $statusNames = array(
+        '200' => 'OK',
+        '201' => 'Created',
+        '202' => 'Accepted',
+        '204' => 'NoContent',
+        '301' => 'MovedPermanently',
+        '302' => 'Found',
+        '304' => 'NotModified',
+        '400' => 'BadRequest',
+        '401' => 'Unauthorized',
+        '403' => 'Forbidden',
+        '404' => 'NotFound',
+        '405' => 'MethodNotAllowed',
+        '409' => 'Conflict',
+        '410' => 'Gone',
+        '412' => 'PreconditionFailed',
+        '415' => 'UnsupportedMediaType',
+        '422' => 'UnprocessableEntity',
+        '429' => 'TooManyRequests',
+        '500' => 'InternalServerError',
+        '501' => 'NotImplemented',
+        '502' => 'BadGateway',
+        '503' => 'ServiceUnavailable',
+        '504' => 'GatewayTimeout',
+    );
+
+    /** @var GoBuilder */
+    public $goBuilder;
+
+    /** @var PathToNameHook */
+    public $pathToNameHook;
+
+    /** @var \stdClass */
+    private $document;
+
+    /** @var Context */
+    private $context;
+
+    /** @var Operation[] */
+    private $operations = array();
+
+    /** @var bool[] */
+    private $operationNames = array();
+
+    /** @var Code */
+    private $code;
+
+    /**
+     * Builder constructor.
+     * @param \stdClass $document decoded OpenAPI 3 document
+     * @param GoBuilder|null $goBuilder
+     */
+    public function __construct($document, GoBuilder $goBuilder = null)
+    {
+        if ($goBuilder === null) {
+            $goBuilder = new GoBuilder();
+            $goBuilder->options->enableXNullable = true;
+        }
+
+        $this->document = $document;
+        $this->goBuilder = $goBuilder;
+        $this->code = new Code();
+
+        $preloaded = new Preloaded();
+        $preloaded->setSchemaData(static::DOCUMENT_URL, $document);
+        $this->context = new Context($preloaded);
+
+        $this->pathToNameHook = new PathToNameHook($goBuilder->pathToNameHook);
+        $goBuilder->pathToNameHook = $this->pathToNameHook;
+    }
+
+    /**
+     * @return \stdClass
+     */
+    public function getDocument()
+    {
+        return $this->document;
+    }
+
+    /**
+     * Returns code with request and response structures.
+     *
+     * @return Code
+     */
+    public function getCode()
+    {
+        return $this->code;
+    }
+
+    /**
+     * @return Operation[]
+     */
+    public function getOperations()
+    {
+        return $this->operations;
+    }
+
+    /**
+     * Returns API title from document info.
+     *
+     * @return string|null
+     */
+    public function getTitle()
+    {
+        if (isset($this->document->info->title)) {
+            return $this->document->info->title;
+        }
+        return null;
+    }
+
+    /**
+     * Builds operations of all paths.
+     *
+     * @return $this
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function build()
+    {
+        if (!isset($this->document->paths)) {
+            return $this;
+        }
+
+        foreach ($this->document->paths as $path => $pathItem) {
+            $pointer = '#/paths/' . self::escapePointer($path);
+            list($pathItem, $pointer) = $this->resolve($pathItem, $pointer);
+
+            foreach (static::$methods as $method) {
+                if (!isset($pathItem->$method)) {
+                    continue;
+                }
+
+                $parameters = array();
+                if (isset($pathItem->parameters)) {
+                    $parameters = $this->collectParameters($pathItem->parameters, $pointer . '/parameters');
+                }
+                if (isset($pathItem->$method->parameters)) {
+                    // Operation parameters override path item parameters with same name and location.
+                    $parameters = array_merge($parameters,
+                        $this->collectParameters($pathItem->$method->parameters, $pointer . '/' . $method . '/parameters'));
+                }
+
+                $this->addOperation($this->buildOperation($method, $path, $pathItem->$method, $pointer . '/' . $method, $parameters));
+            }
+        }
+
+        return $this;
+    }
+
+    /**
+     * Adds operation with its request and response structures.
+     *
+     * @param Operation $operation
+     * @return $this
+     */
+    public function addOperation(Operation $operation)
+    {
+        $this->operations[] = $operation;
+        $this->code->addSnippet($operation->request);
+        $this->code->addSnippet($operation->response);
+        return $this;
+    }
+
+    /**
+     * @param string $method
+     * @param string $path
+     * @param \stdClass $data
+     * @param string $pointer
+     * @param array[] $parameters pairs of parameter data and pointer
+     * @return Operation
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    protected function buildOperation($method, $path, $data, $pointer, array $parameters)
+    {
+        $operation = $this->makeOperation($method, $path, $data);
+
+        $fieldNames = array();
+        foreach ($parameters as $item) {
+            $parameter = $this->buildParameter($operation, $item[0], $item[1], $fieldNames);
+            $this->addParameter($operation, $parameter);
+        }
+
+        if (isset($data->requestBody)) {
+            list($requestBody, $bodyPointer) = $this->resolve($data->requestBody, $pointer . '/requestBody');
+            $mediaType = isset($requestBody->content) ? self::jsonMediaType($requestBody->content) : null;
+            if ($mediaType !== null && isset($requestBody->content->$mediaType->schema)) {
+                $schemaPointer = $bodyPointer . '/content/' . self::escapePointer($mediaType) . '/schema';
+                $this->pathToNameHook->names[$schemaPointer] = $operation->name . 'RequestBody';
+
+                $operation->bodyRequired = !empty($requestBody->required);
+                $operation->bodyContentType = $mediaType;
+                $this->setBody($operation, $this->getType($schemaPointer), $fieldNames);
+            } elseif ($mediaType === null && isset($requestBody->content)) {
+                $this->buildForm($operation, $requestBody->content, $bodyPointer . '/content', $fieldNames);
+            }
+        }
+
+        if (isset($data->responses)) {
+            foreach ($data->responses as $status => $responseData) {
+                $status = (string)$status;
+                list($responseData, $responsePointer) = $this->resolve($responseData, $pointer . '/responses/' . $status);
+
+                $response = new Response();
+                $response->status = $status;
+                $response->fieldName = self::statusName($status);
+                if (isset($responseData->description)) {
+                    $response->description = $responseData->description;
+                }
+
+                $mediaType = isset($responseData->content) ? self::jsonMediaType($responseData->content) : null;
+                if ($mediaType !== null && isset($responseData->content->$mediaType->schema)) {
+                    $schemaPointer = $responsePointer . '/content/' . self::escapePointer($mediaType) . '/schema';
+                    $this->pathToNameHook->names[$schemaPointer] = $operation->name . 'Response' . $response->fieldName;
+
+                    $response->contentType = $mediaType;
+                    $response->type = $this->getType($schemaPointer);
+                }
+
+                $this->addResponse($operation, $response);
+            }
+        }
+
+        $this->checkPathParameters($operation);
+
+        return $operation;
+    }
+
+    /**
+     * Creates operation with empty request and response structures.
+     *
+     * @param string $method
+     * @param string $path
+     * @param \stdClass $data
+     * @return Operation
+     */
+    public function makeOperation($method, $path, $data)
+    {
+        $operation = new Operation();
+        $operation->method = strtoupper($method);
+        $operation->path = $path;
+        $operation->id = isset($data->operationId) ? $data->operationId : $method . ' ' . $path;
+        if (isset($data->tags)) {
+            $operation->tags = $data->tags;
+        }
+        if (isset($data->summary)) {
+            $operation->summary = $data->summary;
+        }
+        if (isset($data->description)) {
+            $operation->description = $data->description;
+        }
+
+        $name = $this->goBuilder->codeBuilder->exportableName($operation->id);
+        $preferredName = $name;
+        $i = 2;
+        while (isset($this->operationNames[$name])) {
+            $name = $preferredName . $i;
+            $i++;
+        }
+        $this->operationNames[$name] = true;
+        $operation->name = $name;
+
+        $operation->request = new StructDef($name . 'Request',
+            $name . 'Request is a request of ' . $operation->method . ' ' . $path . '.');
+        $operation->response = new StructDef($name . 'Response',
+            $name . 'Response is a response of ' . $operation->method . ' ' . $path . '.');
+        $operation->response->addProperty(new StructProperty('StatusCode', new Type('int')));
+
+        return $operation;
+    }
+
+    /**
+     * Adds parameter field to request structure.
+     *
+     * @param Operation $operation
+     * @param Parameter $parameter
+     */
+    public function addParameter(Operation $operation, Parameter $parameter)
+    {
+        $operation->parameters[] = $parameter;
+
+        $property = new StructProperty($parameter->fieldName, $parameter->type,
+            (new Tags())->setTag($parameter->in, $parameter->name));
+        if ($parameter->description && false === strpos(trim($parameter->description), "\n")) {
+            $property->setComment(Comment::sentence($parameter->description));
+        }
+        $operation->request->addProperty($property);
+    }
+
+    /**
+     * Adds body field to request structure.
+     *
+     * @param Operation $operation
+     * @param AnyType $type
+     * @param bool[] $fieldNames names of request fields
+     */
+    public function setBody(Operation $operation, AnyType $type, array &$fieldNames)
+    {
+        if (!$operation->bodyRequired && $type instanceof StructType) {
+            $type = new Pointer($type);
+        }
+
+        $operation->bodyField = self::uniqueName('Body', $fieldNames, 'Request');
+        $operation->bodyType = $type;
+        $operation->request->addProperty(new StructProperty($operation->bodyField, $type));
+    }
+
+    /**
+     * Adds response field to response structure.
+     *
+     * @param Operation $operation
+     * @param Response $response
+     */
+    public function addResponse(Operation $operation, Response $response)
+    {
+        if ($response->type instanceof StructType) {
+            $response->type = new Pointer($response->type);
+        }
+
+        $operation->responses[] = $response;
+        if ($response->type !== null) {
+            $operation->response->addProperty(new StructProperty($response->fieldName, $response->type));
+        }
+    }
+
+    /**
+     * @param array $parameters
+     * @param string $pointer
+     * @return array[] pairs of parameter data and pointer by location and name
+     * @throws Exception
+     */
+    protected function collectParameters(array $parameters, $pointer)
+    {
+        $result = array();
+        foreach ($parameters as $i => $parameter) {
+            $item = $this->resolve($parameter, $pointer . '/' . $i);
+            $result[$item[0]->in . ':' . $item[0]->name] = $item;
+        }
+        return $result;
+    }
+
+    /**
+     * @param Operation $operation
+     * @param \stdClass $data
+     * @param string $pointer
+     * @param bool[] $fieldNames
+     * @return Parameter
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildParameter(Operation $operation, $data, $pointer, array &$fieldNames)
+    {
+        $parameter = new Parameter();
+        $parameter->name = $data->name;
+        $parameter->in = $data->in;
+        $parameter->required = $data->in === Parameter::IN_PATH || !empty($data->required);
+        if (isset($data->description)) {
+            $parameter->description = $data->description;
+        }
+
+        $parameter->fieldName = self::uniqueName(
+            $this->goBuilder->codeBuilder->exportableName($data->name),
+            $fieldNames,
+            $this->goBuilder->codeBuilder->exportableName($data->in)
+        );
+
+        $schemaPointer = null;
+        if (isset($data->schema)) {
+            $schemaPointer = $pointer . '/schema';
+        } elseif (isset($data->content)) {
+            foreach ($data->content as $mediaType => $mediaTypeData) {
+                if (isset($mediaTypeData->schema)) {
+                    $schemaPointer = $pointer . '/content/' . self::escapePointer($mediaType) . '/schema';
+                }
+                break;
+            }
+        }
+
+        if ($schemaPointer !== null) {
+            $this->pathToNameHook->names[$schemaPointer] = $operation->name . $parameter->fieldName;
+            $type = $this->getType($schemaPointer);
+        } else {
+            $type = new Type('string');
+        }
+
+        if (!$parameter->required && !self::isNillable($type)) {
+            $type = new Pointer($type);
+        }
+        $parameter->type = $type;
+
+        $style = isset($data->style) ? $data->style
+            : ($data->in === Parameter::IN_QUERY || $data->in === Parameter::IN_COOKIE ? 'form' : 'simple');
+        $parameter->explode = isset($data->explode) ? (bool)$data->explode : $style === 'form';
+        if ($style === 'spaceDelimited') {
+            $parameter->delimiter = ' ';
+        } elseif ($style === 'pipeDelimited') {
+            $parameter->delimiter = '|';
+        }
+
+        return $parameter;
+    }
+
+    /**
+     * Adds properties of form request body as formData parameters, so that they are sent and parsed like
+     * formData parameters of Swagger 2, request body of other media types is skipped with a warning.
+     *
+     * @param Operation $operation
+     * @param \stdClass $content request body data by media type
+     * @param string $pointer
+     * @param bool[] $fieldNames
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildForm(Operation $operation, $content, $pointer, array &$fieldNames)
+    {
+        $mediaType = null;
+        foreach (array(self::FORM_URLENCODED, self::FORM_MULTIPART) as $formMediaType) {
+            if (isset($content->$formMediaType->schema)) {
+                $mediaType = $formMediaType;
+                break;
+            }
+        }
+        if ($mediaType === null) {
+            $this->warn('Request body of ' . $operation->name . ' is skipped, media types '
+                . implode(', ', array_keys((array)$content)) . ' are not supported', $pointer);
+            return;
+        }
+
+        list($schema, $schemaPointer) = $this->resolve($content->$mediaType->schema,
+            $pointer . '/' . self::escapePointer($mediaType) . '/schema');
+        if (!isset($schema->properties)) {
+            $this->warn('Request body of ' . $operation->name . ' is skipped, form schema has no properties',
+                $schemaPointer);
+            return;
+        }
+
+        $required = isset($schema->required) ? $schema->required : array();
+        foreach ($schema->properties as $name => $propertyData) {
+            $propertyPointer = $schemaPointer . '/properties/' . self::escapePointer($name);
+            list($propertyData) = $this->resolve($propertyData, $propertyPointer);
+
+            $parameter = new Parameter();
+            $parameter->name = $name;
+            $parameter->in = Parameter::IN_FORM_DATA;
+            $parameter->required = in_array($name, $required, true);
+            if (isset($propertyData->description)) {
+                $parameter->description = $propertyData->description;
+            }
+
+            $parameter->fieldName = self::uniqueName(
+                $this->goBuilder->codeBuilder->exportableName($name),
+                $fieldNames,
+                'FormData'
+            );
+
+            if ($mediaType === self::FORM_MULTIPART && isset($propertyData->type, $propertyData->format)
+                && $propertyData->type === 'string' && $propertyData->format === 'binary') {
+                // File is streamed from multipart form, nil interface stands for a missing file.
+                $parameter->file = true;
+                $parameter->type = TypeUtil::fromString('io.Reader');
+            } else {
+                $this->pathToNameHook->names[$propertyPointer] = $operation->name . $parameter->fieldName;
+                $type = $this->getType($propertyPointer);
+                if (!$parameter->required && !self::isNillable($type)) {
+                    $type = new Pointer($type);
+                }
+                $parameter->type = $type;
+            }
+
+            $this->addParameter($operation, $parameter);
+        }
+
+        $operation->bodyContentType = $mediaType;
+    }
+
+    /**
+     * Reports possible problem of document to warning hook of GoBuilder.
+     *
+     * @param string $message
+     * @param string $pointer
+     */
+    protected function warn($message, $pointer)
+    {
+        if ($this->goBuilder->warningHook !== null) {
+            $this->goBuilder->warningHook->process($message, $pointer, null);
+        }
+    }
+
+    /**
+     * Checks that all path template variables have parameters.
+     *
+     * @param Operation $operation
+     * @throws Exception
+     */
+    public function checkPathParameters(Operation $operation)
+    {
+        preg_match_all('/\{([^}]+)\}/', $operation->path, $matches);
+        foreach ($matches[1] as $name) {
+            $found = false;
+            foreach ($operation->parametersIn(Parameter::IN_PATH) as $parameter) {
+                if ($parameter->name === $name) {
+                    $found = true;
+                    break;
+                }
+            }
+            if (!$found) {
+                throw new Exception('Missing path parameter "' . $name . '" in '
+                    . $operation->method . ' ' . $operation->path);
+            }
+        }
+    }
+
+    /**
+     * Returns Go type of schema located in document.
+     *
+     * @param string $pointer JSON pointer of schema, e.g. #/components/schemas/Order
+     * @return AnyType
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function getType($pointer)
+    {
+        $schema = Schema::import((object)array(Schema::PROP_REF => static::DOCUMENT_URL . $pointer), $this->context);
+
+        return $this->goBuilder->getType($schema, $pointer);
+    }
+
+    /**
+     * Follows local references of document.
+     *
+     * @param mixed $value
+     * @param string $pointer
+     * @return array pair of resolved value and its pointer
+     * @throws Exception
+     */
+    public function resolve($value, $pointer)
+    {
+        return self::resolveRef($this->document, $value, $pointer);
+    }
+
+    /**
+     * Follows local references of a document.
+     *
+     * @param \stdClass $document
+     * @param mixed $value
+     * @param string $pointer
+     * @return array pair of resolved value and its pointer
+     * @throws Exception
+     */
+    public static function resolveRef($document, $value, $pointer)
+    {
+        $visited = array();
+        while (is_object($value) && isset($value->{'$ref'})) {
+            $ref = $value->{'$ref'};
+            if (0 !== strpos($ref, '#') || isset($visited[$ref])) {
+                throw new Exception('Unsupported reference: ' . $ref);
+            }
+            $visited[$ref] = true;
+
+            $pointer = $ref;
+            $value = $document;
+            if ($ref === '#') {
+                continue;
+            }
+            foreach (explode('/', substr($ref, 2)) as $segment) {
+                $segment = strtr(rawurldecode($segment), array('~1' => '/', '~0' => '~'));
+                if (is_object($value) && property_exists($value, $segment)) {
+                    $value = $value->$segment;
+                } elseif (is_array($value) && array_key_exists($segment, $value)) {
+                    $value = $value[$segment];
+                } else {
+                    throw new Exception('Unresolvable reference: ' . $ref);
+                }
+            }
+        }
+
+        return array($value, $pointer);
+    }
+
+    /**
+     * Returns JSON media type of content, `application/json` is preferred.
+     *
+     * @param \stdClass $content
+     * @return string|null
+     */
+    public static function jsonMediaType($content)
+    {
+        $result = null;
+        foreach ($content as $mediaType => $data) {
+            if ($mediaType === 'application/json') {
+                return $mediaType;
+            }
+            if ($result === null && preg_match('/[\/+]json(;|$)/', $mediaType)) {
+                $result = $mediaType;
+            }
+        }
+        return $result;
+    }
+
+    /**
+     * Returns name of response field, e.g. OK, NotFound, Status2XX or Default.
+     *
+     * @param string $status
+     * @return string
+     */
+    public static function statusName($status)
+    {
+        if ($status === 'default') {
+            return 'Default';
+        }
+        if (isset(static::$statusNames[$status])) {
+            return static::$statusNames[$status];
+        }
+        return 'Status' . strtoupper($status);
+    }
+
+    /**
+     * @param string $value
+     * @return string
+     */
+    public static function escapePointer($value)
+    {
+        return strtr($value, array('~' => '~0', '/' => '~1'));
+    }
+
+    /**
+     * Checks if type has nil value.
+     *
+     * @param AnyType $type
+     * @return bool
+     */
+    public static function isNillable(AnyType $type)
+    {
+        return $type instanceof Pointer || $type instanceof Slice || $type instanceof Map || TypeUtil::isAny($type);
+    }
+
+    /**
+     * @param string $name
+     * @param bool[] $taken
+     * @param string $suffix added to name in case of conflict
+     * @return string
+     */
+    protected static function uniqueName($name, array &$taken, $suffix)
+    {
+        if (isset($taken[$name])) {
+            $name .= $suffix;
+        }
+        $preferredName = $name;
+        $i = 2;
+        while (isset($taken[$name])) {
+            $name = $preferredName . $i;
+            $i++;
+        }
+        $taken[$name] = true;
+
+        return $name;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,471 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Argument;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceDef;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Slice;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+
+/**
+ * Client renders API client interface and its implementation with net/http.
+ */
+class Client extends GoTemplate
+{
+    /** @var Builder */
+    private $builder;
+
+    /** @var string */
+    private $name = 'Client';
+
+    /** @var string */
+    private $implementationName = 'HTTPClient';
+
+    /**
+     * Client constructor.
+     * @param Builder $builder
+     */
+    public function __construct(Builder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    /**
+     * @param string $name
+     * @return Client
+     */
+    public function setName($name)
+    {
+        $this->name = $name;
+        return $this;
+    }
+
+    /**
+     * @param string $implementationName
+     * @return Client
+     */
+    public function setImplementationName($implementationName)
+    {
+        $this->implementationName = $implementationName;
+        return $this;
+    }
+
+    /**
+     * Returns client interface, it can also be used to render mocks.
+     *
+     * @return IfaceDef
+     */
+    public function getIface()
+    {
+        $title = $this->builder->getTitle();
+        $iface = new IfaceDef($this->name, $title
+            ? $this->name . ' is a client of ' . rtrim($title, '.') . ' API.'
+            : $this->name . ' is an API client.');
+
+        foreach ($this->builder->getOperations() as $operation) {
+            $iface->addFunc(self::signature($operation));
+        }
+
+        return $iface;
+    }
+
+    /**
+     * Returns operation method that takes context and request and returns response.
+     *
+     * @param Operation $operation
+     * @return FuncDef
+     */
+    public static function signature(Operation $operation)
+    {
+        $func = new FuncDef($operation->name, $operation->comment());
+        $func->setArguments((new Arguments())
+            ->add('ctx', TypeUtil::fromString('context.Context'))
+            ->add('req', $operation->request->getType()));
+        $func->setResult((new Result())
+            ->add(null, $operation->response->getType())
+            ->add(null, new Type('error')));
+
+        return $func;
+    }
+
+    protected function toString()
+    {
+        $code = new Code();
+        $code->imports()->addByName('net/http');
+
+        $code->addSnippet($this->getIface());
+        $code->addSnippet(<<<GO
+// {$this->implementationName} implements {$this->name} with net/http.
+type {$this->implementationName} struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// New{$this->implementationName} creates {$this->implementationName} for API server at base URL.
+func New{$this->implementationName}(baseURL string) *{$this->implementationName} {
+	return &{$this->implementationName}{
+		BaseURL: baseURL,
+		Client:  http.DefaultClient,
+	}
+}
+
+
+GO
+        );
+
+        foreach ($this->builder->getOperations() as $operation) {
+            $code->addSnippet($this->method($operation));
+        }
+
+        return $code;
+    }
+
+    /**
+     * @param Operation $operation
+     * @return FuncDef
+     */
+    private function method(Operation $operation)
+    {
+        $func = self::signature($operation);
+        $func->setSelf(new Argument('c', new Pointer(new Type($this->implementationName))));
+
+        $code = new Code();
+        $code->imports()->addByName('net/http');
+
+        $body = "var resp {$operation->response->getName()}\n\n";
+
+        $query = '';
+        foreach ($operation->parametersIn(Parameter::IN_QUERY) as $parameter) {
+            $query .= $this->encode($parameter, 'query.Set(%s, %s)', 'query.Add(%s, %s)', $code);
+        }
+        if ($query !== '') {
+            $code->imports()->addByName('net/url');
+            $body .= "query := make(url.Values)\n" . $query . "\n";
+        }
+
+        $body .= $this->url($operation, $code);
+        if ($query !== '') {
+            $body .= "if len(query) > 0 {\n\tu += \"?\" + query.Encode()\n}\n";
+        }
+        $body .= "\n";
+
+        $requestBody = 'nil';
+        $headers = '';
+        if ($operation->bodyType !== null) {
+            $code->imports()->addByName('encoding/json');
+            $code->imports()->addByName('bytes');
+            $contentType = Literal::quote($operation->bodyContentType);
+            $field = 'req.' . $operation->bodyField;
+            if (!$operation->bodyRequired && Builder::isNillable($operation->bodyType)) {
+                $code->imports()->addByName('io');
+                $requestBody = 'body';
+                $body .= <<<GO
+var body io.Reader
+if {$field} != nil {
+	b, err := json.Marshal({$field})
+	if err != nil {
+		return resp, err
+	}
+
+	body = bytes.NewReader(b)
+}
+
+
+GO;
+                $headers .= "if body != nil {\n\tr.Header.Set(\"Content-Type\", {$contentType})\n}\n";
+            } else {
+                $requestBody = 'bytes.NewReader(body)';
+                $body .= <<<GO
+body, err := json.Marshal({$field})
+if err != nil {
+	return resp, err
+}
+
+
+GO;
+                $headers .= "r.Header.Set(\"Content-Type\", {$contentType})\n";
+            }
+        }
+
+        $formParameters = $operation->parametersIn(Parameter::IN_FORM_DATA);
+        if (!empty($formParameters)) {
+            list($statements, $requestBody, $contentType) = $this->form($operation, $formParameters, $code);
+            $body .= $statements;
+            $headers .= "r.Header.Set(\"Content-Type\", {$contentType})\n";
+        }
+
+        foreach ($operation->parametersIn(Parameter::IN_HEADER) as $parameter) {
+            $headers .= $this->encode($parameter, 'r.Header.Set(%s, %s)', 'r.Header.Add(%s, %s)', $code);
+        }
+        foreach ($operation->parametersIn(Parameter::IN_COOKIE) as $parameter) {
+            $cookie = 'r.AddCookie(&http.Cookie{Name: %s, Value: %s})';
+            $headers .= $this->encode($parameter, $cookie, $cookie, $code);
+        }
+
+        $method = 'http.Method' . ucfirst(strtolower($operation->method));
+        $body .= <<<GO
+r, err := http.NewRequestWithContext(ctx, {$method}, u, {$requestBody})
+if err != nil {
+	return resp, err
+}
+
+
+GO;
+        if ($headers !== '') {
+            $body .= $headers . "\n";
+        }
+
+        $body .= <<<GO
+res, err := c.Client.Do(r)
+if err != nil {
+	return resp, err
+}
+
+defer res.Body.Close()
+
+resp.StatusCode = res.StatusCode
+
+switch {
+
+GO;
+        $body .= $this->decode($operation, $code);
+        $body .= "}\n\nreturn resp, err";
+
+        $code->addSnippet($body);
+        $func->setBody($code);
+
+        return $func;
+    }
+
+    /**
+     * Renders statement that builds request URL with escaped path parameters.
+     *
+     * @param Operation $operation
+     * @param Code $code
+     * @return string
+     */
+    private function url(Operation $operation, Code $code)
+    {
+        $parameters = array();
+        foreach ($operation->parametersIn(Parameter::IN_PATH) as $parameter) {
+            $parameters[$parameter->name] = $parameter;
+        }
+
+        $statements = '';
+        $parts = array('c.BaseURL');
+        foreach (preg_split('/(\{[^}]+\})/', $operation->path, -1, PREG_SPLIT_DELIM_CAPTURE | PREG_SPLIT_NO_EMPTY) as $part) {
+            $name = substr($part, 1, -1);
+            if ($part[0] !== '{' || !isset($parameters[$name])) {
+                $parts[] = Literal::quote($part);
+                continue;
+            }
+
+            $code->imports()->addByName('net/url');
+            $parameter = $parameters[$name];
+            $field = 'req.' . $parameter->fieldName;
+            if ($parameter->type instanceof Slice) {
+                list($prelude, $value) = ParamCodec::join($field, $parameter->type->getType(), $parameter->delimiter,
+                    'path' . $parameter->fieldName, $code);
+                $statements .= $prelude;
+            } elseif ($parameter->type instanceof Pointer) {
+                $value = ParamCodec::format('*' . $field, $parameter->type, $code);
+            } else {
+                $value = ParamCodec::format($field, $parameter->type, $code);
+            }
+            $parts[] = 'url.PathEscape(' . $value . ')';
+        }
+
+        return $statements . 'u := ' . implode(' + ', $parts) . "\n";
+    }
+
+    /**
+     * Renders statements that build request body of form parameters.
+     *
+     * @param Operation $operation
+     * @param Parameter[] $parameters
+     * @param Code $code
+     * @return string[] statements, request body expression and content type expression
+     */
+    private function form(Operation $operation, array $parameters, Code $code)
+    {
+        $values = '';
+        $files = '';
+        foreach ($parameters as $parameter) {
+            if ($parameter->file) {
+                $files .= $this->formFile($parameter, $code);
+            } else {
+                $values .= $this->encode($parameter, 'form.Set(%s, %s)', 'form.Add(%s, %s)', $code);
+            }
+        }
+
+        $statements = '';
+        if ($values !== '') {
+            $code->imports()->addByName('net/url');
+            $statements = "form := make(url.Values)\n" . $values . "\n";
+        }
+
+        if (!$operation->isMultipart()) {
+            $code->imports()->addByName('strings');
+            return array($statements, 'strings.NewReader(form.Encode())', Literal::quote($operation->bodyContentType));
+        }
+
+        $code->imports()->addByName('bytes');
+        $code->imports()->addByName('mime/multipart');
+        $statements .= "var body bytes.Buffer\nmw := multipart.NewWriter(&body)\n";
+        if ($values !== '') {
+            $statements .= <<<GO
+for name, values := range form {
+	for _, v := range values {
+		if err := mw.WriteField(name, v); err != nil {
+			return resp, err
+		}
+	}
+}
+
+GO;
+        }
+
+        $statements .= "\n" . $files . <<<GO
+if err := mw.Close(); err != nil {
+	return resp, err
+}
+
+
+GO;
+
+        return array($statements, '&body', 'mw.FormDataContentType()');
+    }
+
+    /**
+     * Renders statements that write file parameter to multipart form.
+     *
+     * @param Parameter $parameter
+     * @param Code $code
+     * @return string
+     */
+    private function formFile(Parameter $parameter, Code $code)
+    {
+        $code->imports()->addByName('io');
+        $name = Literal::quote($parameter->name);
+        $field = 'req.' . $parameter->fieldName;
+        $part = 'part' . $parameter->fieldName;
+
+        $statements = <<<GO
+{$part}, err := mw.CreateFormFile({$name}, {$name})
+if err != nil {
+	return resp, err
+}
+
+if _, err := io.Copy({$part}, {$field}); err != nil {
+	return resp, err
+}
+
+GO;
+        if (!$parameter->required) {
+            $statements = "if {$field} != nil {\n" . $this->padLines("\t", $statements, false) . "}\n";
+        }
+
+        return $statements . "\n";
+    }
+
+    /**
+     * Renders statements that add parameter values to request.
+     *
+     * @param Parameter $parameter
+     * @param string $set format of statement that sets single value
+     * @param string $add format of statement that adds one of multiple values
+     * @param Code $code
+     * @return string
+     */
+    private function encode(Parameter $parameter, $set, $add, Code $code)
+    {
+        $name = Literal::quote($parameter->name);
+        $field = 'req.' . $parameter->fieldName;
+        $type = $parameter->type;
+
+        if ($type instanceof Slice) {
+            if ($parameter->explode && Parameter::isRepeatable($parameter->in)) {
+                $value = ParamCodec::format('v', $type->getType(), $code);
+                return "for _, v := range {$field} {\n\t" . sprintf($add, $name, $value) . "\n}\n";
+            }
+
+            list($prelude, $value) = ParamCodec::join($field, $type->getType(), $parameter->delimiter, 'values', $code);
+            return "if len({$field}) > 0 {\n" . $this->padLines("\t", $prelude . sprintf($set, $name, $value), false)
+                . "\n}\n";
+        }
+
+        if ($type instanceof Pointer) {
+            $value = ParamCodec::format('*' . $field, $type, $code);
+            return "if {$field} != nil {\n\t" . sprintf($set, $name, $value) . "\n}\n";
+        }
+
+        return sprintf($set, $name, ParamCodec::format($field, $type, $code)) . "\n";
+    }
+
+    /**
+     * Renders switch cases that decode response by status code.
+     *
+     * @param Operation $operation
+     * @param Code $code
+     * @return string
+     */
+    private function decode(Operation $operation, Code $code)
+    {
+        $cases = array();
+        $ranges = array();
+        $default = null;
+
+        foreach ($operation->responses as $response) {
+            $statements = '';
+            if ($response->type !== null) {
+                $code->imports()->addByName('encoding/json');
+                $field = 'resp.' . $response->fieldName;
+                if ($response->type instanceof Pointer) {
+                    $statements = "\t{$field} = new({$response->type->getType()->render()})\n"
+                        . "\terr = json.NewDecoder(res.Body).Decode({$field})\n";
+                } else {
+                    $statements = "\terr = json.NewDecoder(res.Body).Decode(&{$field})\n";
+                }
+            }
+
+            if ($response->isDefault()) {
+                $default = "default:\n" . $statements;
+            } elseif ($response->isRange()) {
+                $from = (int)$response->status[0] * 100;
+                $to = $from + 100;
+                $ranges[] = "case res.StatusCode >= {$from} && res.StatusCode < {$to}:\n" . $statements;
+            } else {
+                $cases[] = "case res.StatusCode == " . self::statusConst($response->status) . ":\n" . $statements;
+            }
+        }
+
+        if ($default === null) {
+            $code->imports()->addByName('fmt');
+            $default = "default:\n\terr = fmt.Errorf(\"unexpected response status: %s\", res.Status)\n";
+        }
+
+        return implode('', $cases) . implode('', $ranges) . $default;
+    }
+
+    /**
+     * Returns net/http constant of status code if available.
+     *
+     * @param string $status
+     * @return string
+     */
+    public static function statusConst($status)
+    {
+        if (isset(Builder::$statusNames[$status])) {
+            return 'http.Status' . Builder::$statusNames[$status];
+        }
+        return $status;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+
+class Exception extends \Exception
+{
+
+}
\ No newline at end of file
@@ -0,0 +1,97 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\Style\Comment;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Operation describes API operation with its request and response structures.
+ */
+class Operation
+{
+    /** @var string upper case HTTP method */
+    public $method;
+
+    /** @var string path template, e.g. /orders/{orderId} */
+    public $path;
+
+    /** @var string operationId or name derived from method and path */
+    public $id;
+
+    /** @var string exported Go name of operation */
+    public $name;
+
+    /** @var string[] */
+    public $tags = array();
+
+    /** @var string */
+    public $summary;
+
+    /** @var string */
+    public $description;
+
+    /** @var Parameter[] */
+    public $parameters = array();
+
+    /** @var string name of body field in request structure */
+    public $bodyField = 'Body';
+
+    /** @var AnyType|null type of request body */
+    public $bodyType;
+
+    /** @var bool */
+    public $bodyRequired = false;
+
+    /** @var string|null media type of JSON body or form parameters */
+    public $bodyContentType;
+
+    /** @var Response[] */
+    public $responses = array();
+
+    /** @var StructDef */
+    public $request;
+
+    /** @var StructDef */
+    public $response;
+
+    /**
+     * @param string $in
+     * @return Parameter[]
+     */
+    public function parametersIn($in)
+    {
+        $result = array();
+        foreach ($this->parameters as $parameter) {
+            if ($parameter->in === $in) {
+                $result[] = $parameter;
+            }
+        }
+        return $result;
+    }
+
+    /**
+     * Checks if form parameters are sent as multipart form.
+     *
+     * @return bool
+     */
+    public function isMultipart()
+    {
+        return $this->bodyContentType === 'multipart/form-data';
+    }
+
+    /**
+     * Returns comment of operation method.
+     *
+     * @return string
+     */
+    public function comment()
+    {
+        $comment = $this->name . ' performs ' . $this->method . ' ' . $this->path . '.';
+        if ($this->summary) {
+            $comment .= "\n\n" . Comment::sentence($this->summary);
+        }
+        return $comment;
+    }
+}
\ No newline at end of file
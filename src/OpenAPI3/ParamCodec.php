@@ -0,0 +1,144 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+
+/**
+ * ParamCodec renders Go expressions that convert parameter values to and from strings.
+ */
+class ParamCodec
+{
+    /**
+     * Returns Go expression that formats value as parameter string.
+     *
+     * @param string $expr Go expression of value, pointers have to be dereferenced with `*`
+     * @param AnyType $type
+     * @param Code $code receives imports
+     * @return string
+     */
+    public static function format($expr, AnyType $type, Code $code)
+    {
+        $type = TypeUtil::resolvePointer($type);
+
+        if (TypeUtil::getBasicType($type) === 'string') {
+            return $expr;
+        }
+
+        if (self::isTime($type)) {
+            $code->imports()->addByName('time');
+            // Methods are available on pointer, so dereference is not needed.
+            return ltrim($expr, '*') . '.Format(time.RFC3339)';
+        }
+
+        $code->imports()->addByName('fmt');
+        return 'fmt.Sprint(' . $expr . ')';
+    }
+
+    /**
+     * Returns statements and Go expression that joins slice values into a single parameter string.
+     *
+     * @param string $expr Go expression of slice
+     * @param AnyType $itemType
+     * @param string $delimiter
+     * @param string $var name of variable for formatted values
+     * @param Code $code receives imports
+     * @return string[] statements and expression
+     */
+    public static function join($expr, AnyType $itemType, $delimiter, $var, Code $code)
+    {
+        $code->imports()->addByName('strings');
+        $delimiter = Literal::quote($delimiter);
+
+        if (TypeUtil::getBasicType($itemType) === 'string') {
+            return array('', "strings.Join({$expr}, {$delimiter})");
+        }
+
+        $value = self::format('v', $itemType, $code);
+        $statements = <<<GO
+{$var} := make([]string, 0, len({$expr}))
+for _, v := range {$expr} {
+	{$var} = append({$var}, {$value})
+}
+
+GO;
+
+        return array($statements, "strings.Join({$var}, {$delimiter})");
+    }
+
+    /**
+     * Returns statements and Go expression that parse parameter string into value of type.
+     *
+     * Named types that are not builtin are decoded from JSON string, this fits string enumerations.
+     *
+     * @param string $source Go expression of parameter string
+     * @param AnyType $type type of value, pointer is resolved
+     * @param string $onError statements to handle `err`, e.g. write response and return
+     * @param Code $code receives imports
+     * @return string[] statements and expression
+     */
+    public static function parse($source, AnyType $type, $onError, Code $code)
+    {
+        $type = TypeUtil::resolvePointer($type);
+        $basicType = TypeUtil::getBasicType($type);
+        $onError = "\t" . str_replace("\n", "\n\t", $onError);
+
+        if (in_array($basicType, array('string', 'any', 'interface{}'), true)) {
+            return array('', $source);
+        }
+
+        if (self::isTime($type)) {
+            $code->imports()->addByName('time');
+            return array("x, err := time.Parse(time.RFC3339, {$source})\nif err != nil {\n{$onError}\n}\n", 'x');
+        }
+
+        $code->imports()->addByName('strconv');
+        switch ($basicType) {
+            case 'bool':
+                return array("x, err := strconv.ParseBool({$source})\nif err != nil {\n{$onError}\n}\n", 'x');
+            case 'int64':
+                return array("x, err := strconv.ParseInt({$source}, 10, 64)\nif err != nil {\n{$onError}\n}\n", 'x');
+            case 'uint64':
+                return array("x, err := strconv.ParseUint({$source}, 10, 64)\nif err != nil {\n{$onError}\n}\n", 'x');
+            case 'float64':
+                return array("x, err := strconv.ParseFloat({$source}, 64)\nif err != nil {\n{$onError}\n}\n", 'x');
+            case 'int':
+            case 'int8':
+            case 'int16':
+            case 'int32':
+                $bits = $basicType === 'int' ? 0 : substr($basicType, 3);
+                return array("n, err := strconv.ParseInt({$source}, 10, {$bits})\nif err != nil {\n{$onError}\n}\n\n"
+                    . "x := {$basicType}(n)\n", 'x');
+            case 'uint':
+            case 'uint8':
+            case 'uint16':
+            case 'uint32':
+                $bits = $basicType === 'uint' ? 0 : substr($basicType, 4);
+                return array("n, err := strconv.ParseUint({$source}, 10, {$bits})\nif err != nil {\n{$onError}\n}\n\n"
+                    . "x := {$basicType}(n)\n", 'x');
+            case 'float32':
+                return array("n, err := strconv.ParseFloat({$source}, 32)\nif err != nil {\n{$onError}\n}\n\n"
+                    . "x := float32(n)\n", 'x');
+        }
+
+        $code->imports()->addByName('encoding/json');
+        return array("var x {$type->render()}\n"
+            . "if err := json.Unmarshal([]byte(strconv.Quote({$source})), &x); err != nil {\n{$onError}\n}\n", 'x');
+    }
+
+    /**
+     * @param AnyType $type
+     * @return bool
+     */
+    public static function isTime(AnyType $type)
+    {
+        $type = TypeUtil::resolvePointer($type);
+
+        return $type instanceof Type && $type->getName() === 'Time'
+            && $type->getImport() !== null && $type->getImport()->name === 'time';
+    }
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Parameter describes operation parameter and its field in request structure.
+ */
+class Parameter
+{
+    const IN_PATH = 'path';
+    const IN_QUERY = 'query';
+    const IN_HEADER = 'header';
+    const IN_COOKIE = 'cookie';
+    const IN_FORM_DATA = 'formData';
+
+    /** @var string */
+    public $name;
+
+    /** @var string one of path, query, header, cookie or formData */
+    public $in;
+
+    /** @var bool */
+    public $required = false;
+
+    /** @var string */
+    public $description;
+
+    /** @var string name of field in request structure */
+    public $fieldName;
+
+    /** @var AnyType type of field in request structure, optional scalars are pointers */
+    public $type;
+
+    /** @var bool array values are sent as repeated parameters */
+    public $explode = true;
+
+    /** @var string separator of array values that are not exploded */
+    public $delimiter = ',';
+
+    /** @var bool parameter is a file of multipart form */
+    public $file = false;
+
+    /**
+     * Checks if exploded array values of location are sent as repeated parameters.
+     *
+     * @param string $in
+     * @return bool
+     */
+    public static function isRepeatable($in)
+    {
+        return $in === self::IN_QUERY || $in === self::IN_FORM_DATA;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilderPathToNameHook;
+
+/**
+ * PathToNameHook names schemas of API document, inline operation schemas get names of operation.
+ */
+class PathToNameHook implements GoBuilderPathToNameHook
+{
+    /** @var string[] names by JSON pointer of schema */
+    public $names = array();
+
+    /** @var string[] */
+    public $prefixes = array(
+        '#/components/schemas',
+    );
+
+    /** @var GoBuilderPathToNameHook|null */
+    private $fallback;
+
+    /**
+     * PathToNameHook constructor.
+     * @param GoBuilderPathToNameHook|null $fallback hook for paths outside of document
+     */
+    public function __construct(GoBuilderPathToNameHook $fallback = null)
+    {
+        $this->fallback = $fallback;
+    }
+
+    function pathToName($path)
+    {
+        // Document URL is removed from reference, e.g. openapi.json#/paths => #/paths.
+        if (false !== $pos = strpos($path, '#')) {
+            $path = substr($path, $pos);
+        }
+
+        foreach ($this->names as $pointer => $name) {
+            if ($path === $pointer) {
+                return $name;
+            }
+            if (0 === strpos($path, $pointer . '->')) {
+                return $name . substr($path, strlen($pointer));
+            }
+        }
+
+        foreach ($this->prefixes as $prefix) {
+            if (0 === strpos($path, $prefix)) {
+                return substr($path, strlen($prefix));
+            }
+        }
+
+        if ($this->fallback !== null) {
+            return $this->fallback->pathToName($path);
+        }
+
+        return $path;
+    }
+}
\ No newline at end of file
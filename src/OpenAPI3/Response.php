@@ -0,0 +1,42 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+
+/**
+ * Response describes operation response and its field in response structure.
+ */
+class Response
+{
+    /** @var string status code, range (e.g. 2XX) or default */
+    public $status;
+
+    /** @var string */
+    public $description;
+
+    /** @var string name of field in response structure */
+    public $fieldName;
+
+    /** @var AnyType|null type of decoded body, null if response has no JSON content */
+    public $type;
+
+    /** @var string|null */
+    public $contentType;
+
+    /**
+     * @return bool
+     */
+    public function isDefault()
+    {
+        return $this->status === 'default';
+    }
+
+    /**
+     * @return bool
+     */
+    public function isRange()
+    {
+        return (bool)preg_match('/^[1-5]XX$/i', $this->status);
+    }
+}
\ No newline at end of file
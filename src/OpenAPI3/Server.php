@@ -0,0 +1,587 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\OpenAPI3;
+
+use Swaggest\GoCodeBuilder\GoCodeBuilder;
+use Swaggest\GoCodeBuilder\Style\Literal;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceDef;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceUnimplemented;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Slice;
+
+/**
+ * Server renders server interfaces of operations grouped by tag and net/http router that decodes requests,
+ * calls interface methods and encodes responses.
+ */
+class Server extends GoTemplate
+{
+    /** @var Builder */
+    private $builder;
+
+    /** @var string */
+    private $routerName = 'Router';
+
+    /** @var bool */
+    private $groupByTag = true;
+
+    /** @var bool */
+    private $unimplemented = true;
+
+    /**
+     * Server constructor.
+     * @param Builder $builder
+     */
+    public function __construct(Builder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    /**
+     * @param string $routerName
+     * @return Server
+     */
+    public function setRouterName($routerName)
+    {
+        $this->routerName = $routerName;
+        return $this;
+    }
+
+    /**
+     * Put operations of first tag in a separate interface, otherwise all operations belong to a single interface.
+     *
+     * @param bool $groupByTag
+     * @return Server
+     */
+    public function setGroupByTag($groupByTag)
+    {
+        $this->groupByTag = $groupByTag;
+        return $this;
+    }
+
+    /**
+     * Render embeddable structures with not implemented methods.
+     *
+     * @param bool $unimplemented
+     * @return Server
+     */
+    public function setUnimplemented($unimplemented)
+    {
+        $this->unimplemented = $unimplemented;
+        return $this;
+    }
+
+    /**
+     * Returns server interfaces by name.
+     *
+     * @return IfaceDef[]
+     */
+    public function getIfaces()
+    {
+        $ifaces = array();
+        foreach ($this->groups() as $name => $group) {
+            $ifaces[$name] = $group['iface'];
+        }
+        return $ifaces;
+    }
+
+    /**
+     * Groups operations into server interfaces.
+     *
+     * @return array[] interface and operations by interface name
+     */
+    private function groups()
+    {
+        $codeBuilder = new GoCodeBuilder();
+        $title = $this->builder->getTitle();
+
+        $groups = array();
+        foreach ($this->builder->getOperations() as $operation) {
+            $tag = '';
+            if ($this->groupByTag && !empty($operation->tags)) {
+                $tag = $operation->tags[0];
+            }
+
+            $name = ($tag !== '' ? $codeBuilder->exportableName($tag) : '') . 'Server';
+            if (!isset($groups[$name])) {
+                $comment = $name . ' handles ' . ($tag !== '' ? $tag . ' ' : '') . 'operations';
+                $comment .= $title ? ' of ' . rtrim($title, '.') . ' API.' : '.';
+                $groups[$name] = array(
+                    'iface' => new IfaceDef($name, $comment),
+                    'operations' => array(),
+                );
+            }
+
+            $groups[$name]['iface']->addFunc(Client::signature($operation));
+            $groups[$name]['operations'][] = $operation;
+        }
+
+        return $groups;
+    }
+
+    protected function toString()
+    {
+        $code = new Code();
+        $code->imports()->addByName('net/http');
+        $code->imports()->addByName('net/url');
+        $code->imports()->addByName('strings');
+        $code->imports()->addByName('errors');
+        $code->imports()->addByName('encoding/json');
+
+        $groups = $this->groups();
+        foreach ($groups as $group) {
+            $code->addSnippet($group['iface']);
+            if ($this->unimplemented) {
+                $code->addSnippet(new IfaceUnimplemented($group['iface']));
+            }
+        }
+
+        $rt = $this->routerName;
+        $any = $this->builder->goBuilder->anyType();
+        $code->addSnippet(<<<GO
+// {$rt} dispatches HTTP requests to server operations by method and path.
+type {$rt} struct {
+	// OnError writes error response, http.Error is used if nil.
+	OnError func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+	// Validate checks decoded request structure, error is written with http.StatusBadRequest.
+	// Schema constraints of parameters and body (e.g. enum, minimum, pattern) are not checked otherwise.
+	Validate func(r *http.Request, req {$any}) error
+
+	routes []route
+}
+
+type routeHandler func(w http.ResponseWriter, r *http.Request, path map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handle   routeHandler
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *{$rt}) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		handle           routeHandler
+		params           map[string]string
+		methodNotAllowed bool
+	)
+
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+
+	for _, rr := range rt.routes {
+		path, ok := matchPath(rr.segments, segments)
+		if !ok {
+			continue
+		}
+
+		if rr.method != r.Method {
+			methodNotAllowed = true
+
+			continue
+		}
+
+		// Route with less parameters is more specific.
+		if handle == nil || len(path) < len(params) {
+			handle = rr.handle
+			params = path
+		}
+	}
+
+	switch {
+	case handle != nil:
+		handle(w, r, params)
+	case methodNotAllowed:
+		rt.fail(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	default:
+		rt.fail(w, r, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (rt *{$rt}) add(method, pattern string, handle routeHandler) {
+	rt.routes = append(rt.routes, route{method: method, segments: strings.Split(pattern, "/"), handle: handle})
+}
+
+func (rt *{$rt}) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if rt.OnError != nil {
+		rt.OnError(w, r, status, err)
+
+		return
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+func matchPath(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	path := make(map[string]string)
+
+	for i, p := range pattern {
+		if len(p) > 2 && p[0] == '{' && p[len(p)-1] == '}' {
+			v, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+
+			path[p[1:len(p)-1]] = v
+
+			continue
+		}
+
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+
+	return path, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, v {$any}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+
+GO
+        );
+
+        $hasCookies = false;
+        foreach ($groups as $name => $group) {
+            $result = "// Register{$name} adds routes of {$name} operations.\n"
+                . "func (rt *{$rt}) Register{$name}(srv {$name}) {\n";
+            foreach ($group['operations'] as $operation) {
+                $method = 'http.Method' . ucfirst(strtolower($operation->method));
+                $result .= "\trt.add({$method}, " . Literal::quote($operation->path)
+                    . ", func(w http.ResponseWriter, r *http.Request, path map[string]string) {\n"
+                    . "\t\trt.serve{$operation->name}(srv, w, r, path)\n"
+                    . "\t})\n";
+            }
+            $result .= "}\n\n";
+            $code->addSnippet($result);
+
+            foreach ($group['operations'] as $operation) {
+                $code->addSnippet($this->serve($operation, $name, $code));
+                if ($operation->parametersIn(Parameter::IN_COOKIE)) {
+                    $hasCookies = true;
+                }
+            }
+        }
+
+        if ($hasCookies) {
+            $code->addSnippet(<<<GO
+func cookieValue(r *http.Request, name string) string {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return c.Value
+}
+
+
+GO
+            );
+        }
+
+        return $code;
+    }
+
+    /**
+     * Renders method that serves operation.
+     *
+     * @param Operation $operation
+     * @param string $iface
+     * @param Code $code receives imports
+     * @return string
+     */
+    private function serve(Operation $operation, $iface, Code $code)
+    {
+        $body = "var req {$operation->request->getName()}\n\n";
+
+        if ($operation->parametersIn(Parameter::IN_QUERY)) {
+            $body .= "query := r.URL.Query()\n\n";
+        }
+
+        foreach (array(Parameter::IN_PATH, Parameter::IN_QUERY, Parameter::IN_HEADER, Parameter::IN_COOKIE) as $in) {
+            foreach ($operation->parametersIn($in) as $parameter) {
+                $body .= $this->decode($parameter, $code) . "\n";
+            }
+        }
+
+        $formParameters = $operation->parametersIn(Parameter::IN_FORM_DATA);
+        if (!empty($formParameters)) {
+            $body .= $this->parseForm($operation, $formParameters, $code);
+            foreach ($formParameters as $parameter) {
+                $body .= ($parameter->file ? $this->decodeFile($parameter, $code) : $this->decode($parameter, $code)) . "\n";
+            }
+        }
+
+        if ($operation->bodyType !== null) {
+            $code->imports()->addByName('fmt');
+            $check = 'err != nil';
+            if (!$operation->bodyRequired && Builder::isNillable($operation->bodyType)) {
+                $code->imports()->addByName('io');
+                $check .= ' && err != io.EOF';
+            }
+            $body .= <<<GO
+if err := json.NewDecoder(r.Body).Decode(&req.{$operation->bodyField}); {$check} {
+	rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+	return
+}
+
+
+GO;
+        }
+
+        $body .= <<<GO
+if rt.Validate != nil {
+	if err := rt.Validate(r, req); err != nil {
+		rt.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+}
+
+resp, err := srv.{$operation->name}(r.Context(), req)
+if err != nil {
+	rt.fail(w, r, http.StatusInternalServerError, err)
+	return
+}
+
+if resp.StatusCode == 0 {
+	resp.StatusCode = {$this->defaultStatus($operation)}
+}
+
+
+GO;
+        $body .= $this->encode($operation);
+
+        return "func (rt *{$this->routerName}) serve{$operation->name}(srv {$iface}, w http.ResponseWriter, "
+            . "r *http.Request, path map[string]string) {\n"
+            . $this->padLines("\t", $body, false) . "}\n\n";
+    }
+
+    /**
+     * Renders statements that parse request form.
+     *
+     * @param Operation $operation
+     * @param Parameter[] $parameters form parameters
+     * @param Code $code receives imports
+     * @return string
+     */
+    private function parseForm(Operation $operation, array $parameters, Code $code)
+    {
+        $code->imports()->addByName('fmt');
+        $parse = $operation->isMultipart() ? 'r.ParseMultipartForm(32 << 20)' : 'r.ParseForm()';
+        $result = <<<GO
+if err := {$parse}; err != nil {
+	rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid form: %w", err))
+	return
+}
+
+
+GO;
+
+        foreach ($parameters as $parameter) {
+            if (!$parameter->file) {
+                return $result . "form := r.PostForm\n\n";
+            }
+        }
+
+        return $result;
+    }
+
+    /**
+     * Renders statements that take file of multipart form.
+     *
+     * @param Parameter $parameter
+     * @param Code $code receives imports
+     * @return string
+     */
+    private function decodeFile(Parameter $parameter, Code $code)
+    {
+        $code->imports()->addByName('fmt');
+        $name = Literal::quote($parameter->name);
+        $result = "if f, _, err := r.FormFile({$name}); err == nil {\n"
+            . "\tdefer f.Close()\n"
+            . "\treq.{$parameter->fieldName} = f\n"
+            . "} else if err != http.ErrMissingFile {\n"
+            . "\trt.fail(w, r, http.StatusBadRequest, fmt.Errorf("
+            . Literal::quote('invalid ' . $parameter->in . ' parameter ' . $parameter->name . ': %w') . ", err))\n"
+            . "\treturn\n";
+
+        if ($parameter->required) {
+            $code->imports()->addByName('errors');
+            $result .= "} else {\n"
+                . "\trt.fail(w, r, http.StatusBadRequest, errors.New("
+                . Literal::quote('missing ' . $parameter->in . ' parameter ' . $parameter->name) . "))\n"
+                . "\treturn\n";
+        }
+
+        return $result . "}\n";
+    }
+
+    /**
+     * Renders statements that decode and validate parameter.
+     *
+     * @param Parameter $parameter
+     * @param Code $code receives imports
+     * @return string
+     */
+    private function decode(Parameter $parameter, Code $code)
+    {
+        $name = Literal::quote($parameter->name);
+        switch ($parameter->in) {
+            case Parameter::IN_PATH:
+                $source = "path[{$name}]";
+                break;
+            case Parameter::IN_QUERY:
+                $source = "query.Get({$name})";
+                break;
+            case Parameter::IN_HEADER:
+                $source = "r.Header.Get({$name})";
+                break;
+            case Parameter::IN_FORM_DATA:
+                $source = "form.Get({$name})";
+                break;
+            default:
+                $source = "cookieValue(r, {$name})";
+        }
+
+        $onError = 'rt.fail(w, r, http.StatusBadRequest, fmt.Errorf('
+            . Literal::quote('invalid ' . $parameter->in . ' parameter ' . $parameter->name . ': %w')
+            . ", err))\nreturn";
+        $missing = 'rt.fail(w, r, http.StatusBadRequest, errors.New('
+            . Literal::quote('missing ' . $parameter->in . ' parameter ' . $parameter->name)
+            . "))\n\treturn\n";
+        $field = 'req.' . $parameter->fieldName;
+        $type = $parameter->type;
+
+        if ($type instanceof Slice) {
+            list($statements, $value) = ParamCodec::parse('v', $type->getType(), $onError, $code);
+            $append = self::separate($statements) . "{$field} = append({$field}, {$value})\n";
+            if ($statements !== '') {
+                $code->imports()->addByName('fmt');
+            }
+
+            if ($parameter->explode && Parameter::isRepeatable($parameter->in)) {
+                $values = ($parameter->in === Parameter::IN_QUERY ? 'query' : 'form') . "[{$name}]";
+                $result = '';
+                if ($parameter->required) {
+                    $result .= "if len({$values}) == 0 {\n\t{$missing}}\n\n";
+                }
+                return $result . "for _, v := range {$values} {\n" . $this->padLines("\t", $append, false) . "}\n";
+            }
+
+            $code->imports()->addByName('strings');
+            list($statements, $value) = ParamCodec::parse('s', $type->getType(), $onError, $code);
+            if ($statements !== '') {
+                $code->imports()->addByName('fmt');
+            }
+            $append = self::separate($statements) . "{$field} = append({$field}, {$value})\n";
+            $result = "if v := {$source}; v != \"\" {\n"
+                . "\tfor _, s := range strings.Split(v, " . Literal::quote($parameter->delimiter) . ") {\n"
+                . $this->padLines("\t\t", $append, false)
+                . "\t}\n";
+        } else {
+            list($statements, $value) = ParamCodec::parse('v', $type, $onError, $code);
+            if ($statements !== '') {
+                $code->imports()->addByName('fmt');
+            }
+            $assign = $field . ' = ' . ($type instanceof Pointer ? '&' : '') . $value;
+            $result = "if v := {$source}; v != \"\" {\n"
+                . $this->padLines("\t", self::separate($statements) . $assign . "\n", false);
+        }
+
+        if ($parameter->required) {
+            $result .= "} else {\n\t{$missing}";
+        }
+
+        return $result . "}\n";
+    }
+
+    /**
+     * Renders HTTP status that is used when operation did not set response status code.
+     *
+     * @param Operation $operation
+     * @return string
+     */
+    private function defaultStatus(Operation $operation)
+    {
+        foreach ($operation->responses as $response) {
+            $status = (string)$response->status;
+            if (!$response->isDefault() && !$response->isRange() && $status[0] === '2') {
+                return Client::statusConst($response->status);
+            }
+        }
+        return 'http.StatusOK';
+    }
+
+    /**
+     * Renders statements that write response by status code.
+     *
+     * @param Operation $operation
+     * @return string
+     */
+    private function encode(Operation $operation)
+    {
+        $cases = array();
+        $ranges = array();
+        $default = "\tw.WriteHeader(resp.StatusCode)\n";
+        $hasDefaultBody = false;
+
+        foreach ($operation->responses as $response) {
+            $statements = "\tw.WriteHeader(resp.StatusCode)\n";
+            if ($response->type !== null) {
+                $contentType = Literal::quote($response->contentType ? $response->contentType : 'application/json');
+                $statements = "\twriteJSON(w, resp.StatusCode, {$contentType}, resp.{$response->fieldName})\n";
+            }
+
+            if ($response->isDefault()) {
+                $default = $statements;
+                $hasDefaultBody = $response->type !== null;
+            } elseif ($response->isRange()) {
+                $from = (int)$response->status[0] * 100;
+                $to = $from + 100;
+                $ranges[] = array($response->type !== null,
+                    "case resp.StatusCode >= {$from} && resp.StatusCode < {$to}:\n" . $statements);
+            } else {
+                $cases[] = array($response->type !== null,
+                    'case resp.StatusCode == ' . Client::statusConst($response->status) . ":\n" . $statements);
+            }
+        }
+
+        $result = '';
+        foreach (array_merge($cases, $ranges) as $case) {
+            // Responses without body only need a case to bypass default body.
+            if ($case[0] || $hasDefaultBody) {
+                $result .= $case[1];
+            }
+        }
+
+        if ($result === '') {
+            return substr($default, 1);
+        }
+
+        return "switch {\n" . $result . "default:\n" . $default . "}\n";
+    }
+
+    /**
+     * Adds empty line after statements that end with a block.
+     *
+     * @param string $statements
+     * @return string
+     */
+    private static function separate($statements)
+    {
+        if (substr($statements, -2) === "}\n") {
+            return $statements . "\n";
+        }
+        return $statements;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,411 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\GoCodeBuilder\Import;
+use Swaggest\GoCodeBuilder\JsonSchema\GeneratedStruct;
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Templates\Constant\TypeConstBlock;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Map;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Slice;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder maps structures and enums generated by GoBuilder to protobuf messages and enums.
+ *
+ * Properties become fields named in snake case after JSON names, slices are repeated fields, maps with string keys
+ * are map fields, pointers to scalars are optional fields, "oneOf" unions are oneof fields and times are
+ * google.protobuf.Timestamp fields. Properties of other types fail the build. Field numbers are taken from lock file.
+ */
+class Builder
+{
+    const TIMESTAMP = 'google.protobuf.Timestamp';
+    const TIMESTAMP_PROTO = 'google/protobuf/timestamp.proto';
+    const TIMESTAMP_GO_PACKAGE = 'google.golang.org/protobuf/types/known/timestamppb';
+
+    /** @var string[] proto scalar types by Go types */
+    public static $scalars = array(
+        'bool' => 'bool',
+        'string' => 'string',
+        'int' => 'int64',
+        'int8' => 'int32',
+        'int16' => 'int32',
+        'int32' => 'int32',
+        'int64' => 'int64',
+        'uint' => 'uint64',
+        'uint8' => 'uint32',
+        'uint16' => 'uint32',
+        'uint32' => 'uint32',
+        'uint64' => 'uint64',
+        'float32' => 'float',
+        'float64' => 'double',
+    );
+
+    /** @var string[] Go types of proto scalar types */
+    private static $goScalars = array(
+        'bool' => 'bool',
+        'string' => 'string',
+        'int32' => 'int32',
+        'int64' => 'int64',
+        'uint32' => 'uint32',
+        'uint64' => 'uint64',
+        'float' => 'float32',
+        'double' => 'float64',
+    );
+
+    /** @var GoBuilder */
+    public $goBuilder;
+
+    /** @var LockFile */
+    public $lockFile;
+
+    /** @var string */
+    private $package;
+
+    /** @var Import */
+    private $goPackage;
+
+    /** @var Message[] by type strings of generated structures */
+    private $messages = array();
+
+    /** @var Enum[] by type strings of generated enums */
+    private $enums = array();
+
+    /**
+     * Builder constructor.
+     * @param GoBuilder $goBuilder
+     * @param string $package proto package, e.g. "users.v1"
+     * @param string $goPackage import path of protoc generated package
+     * @param LockFile|null $lockFile
+     */
+    public function __construct(GoBuilder $goBuilder, $package, $goPackage, LockFile $lockFile = null)
+    {
+        if ($lockFile === null) {
+            $lockFile = new LockFile();
+        }
+
+        $this->goBuilder = $goBuilder;
+        $this->package = $package;
+        $this->goPackage = new Import($goPackage);
+        $this->lockFile = $lockFile;
+    }
+
+    /**
+     * Maps generated structures and enums, types should be built with GoBuilder before.
+     *
+     * @return $this
+     * @throws Exception
+     */
+    public function build()
+    {
+        $this->messages = array();
+        $this->enums = array();
+
+        $typeConstBlocks = array();
+        if ($this->goBuilder->getCode()->snippets !== null) {
+            foreach ($this->goBuilder->getCode()->snippets as $snippet) {
+                if ($snippet instanceof TypeConstBlock) {
+                    $enum = new Enum();
+                    $enum->type = $snippet->getType();
+                    $enum->name = $enum->type->getName();
+                    $enum->pbType = new Type(self::goCamelCase($enum->name), $this->goPackage);
+                    $this->enums[$enum->type->getTypeString()] = $enum;
+                    $typeConstBlocks[] = $snippet;
+                }
+            }
+        }
+
+        foreach ($this->goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $message = new Message();
+            $message->structDef = $generatedStruct->structDef;
+            $message->name = $message->structDef->getName();
+            $message->pbType = new Type(self::goCamelCase($message->name), $this->goPackage);
+            if (is_string($generatedStruct->schema->description)) {
+                $message->comment = $generatedStruct->schema->description;
+            }
+            $this->messages[$message->structDef->getType()->getTypeString()] = $message;
+        }
+
+        foreach ($typeConstBlocks as $typeConstBlock) {
+            $this->addValues($this->enums[$typeConstBlock->getType()->getTypeString()], $typeConstBlock);
+        }
+
+        foreach ($this->goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $this->addFields($this->messages[$generatedStruct->structDef->getType()->getTypeString()], $generatedStruct);
+        }
+
+        return $this;
+    }
+
+    /**
+     * @return string
+     */
+    public function getPackage()
+    {
+        return $this->package;
+    }
+
+    /**
+     * @return Import
+     */
+    public function getGoPackage()
+    {
+        return $this->goPackage;
+    }
+
+    /**
+     * @return Message[]
+     */
+    public function getMessages()
+    {
+        return $this->messages;
+    }
+
+    /**
+     * @return Enum[]
+     */
+    public function getEnums()
+    {
+        return $this->enums;
+    }
+
+    /**
+     * Converts name to snake case, e.g. "externalID" to "external_id".
+     *
+     * @param string $name
+     * @return string
+     */
+    public static function snakeCase($name)
+    {
+        $name = preg_replace('/([a-z0-9])([A-Z])/', '$1_$2', $name);
+        $name = preg_replace('/([A-Z]+)([A-Z][a-z])/', '$1_$2', $name);
+        $name = trim(strtolower(preg_replace('/[^A-Za-z0-9]+/', '_', $name)), '_');
+
+        return $name;
+    }
+
+    /**
+     * Converts proto name to Go name like protoc-gen-go does, e.g. "external_id" to "ExternalId".
+     *
+     * @param string $name
+     * @return string
+     */
+    public static function goCamelCase($name)
+    {
+        $result = '';
+        $length = strlen($name);
+        for ($i = 0; $i < $length; $i++) {
+            $c = $name[$i];
+            if ($c === '.' && $i + 1 < $length && self::isLower($name[$i + 1])) {
+                continue;
+            } elseif ($c === '.') {
+                $result .= '_';
+            } elseif ($c === '_' && ($i === 0 || $name[$i - 1] === '.')) {
+                $result .= 'X';
+            } elseif ($c === '_' && $i + 1 < $length && self::isLower($name[$i + 1])) {
+                continue;
+            } elseif ($c >= '0' && $c <= '9') {
+                $result .= $c;
+            } else {
+                $result .= strtoupper($c);
+                for (; $i + 1 < $length && self::isLower($name[$i + 1]); $i++) {
+                    $result .= $name[$i + 1];
+                }
+            }
+        }
+
+        return $result;
+    }
+
+    private static function isLower($c)
+    {
+        return $c >= 'a' && $c <= 'z';
+    }
+
+    private function addValues(Enum $enum, TypeConstBlock $typeConstBlock)
+    {
+        $prefix = strtoupper(self::snakeCase($enum->name));
+        $enum->values[$prefix . '_UNSPECIFIED'] = 0;
+
+        foreach ($typeConstBlock->getValues() as $constName => $value) {
+            if (!is_scalar($value)) {
+                continue;
+            }
+
+            if (is_bool($value)) {
+                $enum->zeroValue = 'false';
+                $value = $value ? 'true' : 'false';
+            } elseif (!is_string($value)) {
+                $enum->zeroValue = '0';
+            }
+
+            $name = strtoupper(self::snakeCase((string)$value));
+            if ($name === '') {
+                $name = 'EMPTY';
+            }
+            $name = $this->uniqueName($prefix . '_' . $name, $enum->values);
+
+            $enum->values[$name] = $this->lockFile->valueNumber($enum->name, $name);
+            $enum->constNames[$name] = $constName;
+        }
+
+        $enum->reserved = $this->lockFile->reservedValues($enum->name, array_keys($enum->constNames));
+    }
+
+    /**
+     * @param Message $message
+     * @param GeneratedStruct $generatedStruct
+     * @throws Exception
+     */
+    private function addFields(Message $message, GeneratedStruct $generatedStruct)
+    {
+        $oneofs = array();
+        if ($generatedStruct->marshalJson !== null) {
+            foreach (array(Schema::names()->oneOf, Schema::names()->type) as $kind) {
+                foreach ($generatedStruct->marshalJson->getSomeOf($kind) as $propertyName) {
+                    $oneofs[$propertyName] = self::snakeCase($kind);
+                }
+            }
+        }
+
+        foreach ($message->structDef->getProperties() as $property) {
+            $name = $this->uniqueName($this->fieldName($property), $message->fields);
+
+            try {
+                $field = $this->makeField($property->getType(), isset($oneofs[$property->getName()]));
+            } catch (Exception $exception) {
+                $path = $message->name . '.' . $property->getName();
+                throw new Exception('Failed to map ' . $path . ': ' . lcfirst($exception->getMessage()), 0, $exception);
+            }
+
+            $field->name = $name;
+            $field->goName = self::goCamelCase($name);
+            $field->property = $property;
+            if (isset($oneofs[$property->getName()])) {
+                $field->oneof = $oneofs[$property->getName()];
+            }
+            $field->number = $this->lockFile->fieldNumber($message->name, $name);
+
+            $message->fields[$name] = $field;
+        }
+
+        $message->reserved = $this->lockFile->reservedFields($message->name, array_keys($message->fields));
+    }
+
+    /**
+     * @param StructProperty $property
+     * @return string
+     */
+    private function fieldName(StructProperty $property)
+    {
+        $name = $property->getName();
+        $jsonTag = $property->getTags()->getTag('json');
+        if (is_string($jsonTag)) {
+            $jsonName = explode(',', $jsonTag)[0];
+            if ($jsonName !== '' && $jsonName !== '-') {
+                $name = $jsonName;
+            }
+        }
+
+        $name = self::snakeCase($name);
+        if ($name === '') {
+            $name = 'field';
+        } elseif ($name[0] >= '0' && $name[0] <= '9') {
+            $name = 'field_' . $name;
+        }
+
+        return $name;
+    }
+
+    /**
+     * @param string $name
+     * @param array $taken
+     * @return string
+     */
+    private function uniqueName($name, array $taken)
+    {
+        $unique = $name;
+        for ($i = 2; isset($taken[$unique]); $i++) {
+            $unique = $name . '_' . $i;
+        }
+
+        return $unique;
+    }
+
+    /**
+     * @param AnyType $type
+     * @param bool $oneof
+     * @return Field
+     * @throws Exception
+     */
+    private function makeField(AnyType $type, $oneof)
+    {
+        $field = new Field();
+
+        $valueType = $type;
+        if ($type instanceof Slice) {
+            $field->label = Field::REPEATED;
+            $valueType = $type->getType();
+        } elseif ($type instanceof Map) {
+            if ($type->getKeyType()->getTypeString() !== 'string') {
+                throw new Exception('Unsupported type ' . $type->getTypeString());
+            }
+            $field->keyType = 'string';
+            $valueType = $type->getValueType();
+        }
+
+        $container = $valueType !== $type;
+        if ($oneof && $container) {
+            throw new Exception('Unsupported type ' . $type->getTypeString() . ' in oneof');
+        }
+
+        if ($valueType instanceof Pointer) {
+            $field->pointer = true;
+            $valueType = $valueType->getType();
+        }
+
+        if (!$valueType instanceof Type) {
+            throw new Exception('Unsupported type ' . $type->getTypeString());
+        }
+
+        $typeString = $valueType->getTypeString();
+        $field->goType = $valueType;
+        if (isset($this->messages[$typeString])) {
+            $field->kind = Field::MESSAGE;
+            $field->message = $this->messages[$typeString];
+            $field->type = $field->message->name;
+            $field->pbType = $field->message->pbType;
+        } elseif (isset($this->enums[$typeString])) {
+            $field->kind = Field::ENUM;
+            $field->enum = $this->enums[$typeString];
+            $field->type = $field->enum->name;
+            $field->pbType = $field->enum->pbType;
+        } elseif ($typeString === 'time.Time') {
+            $field->kind = Field::TIME;
+            $field->type = self::TIMESTAMP;
+            $field->pbType = new Type('Timestamp', new Import(self::TIMESTAMP_GO_PACKAGE));
+        } elseif (false !== ($basicType = TypeUtil::getBasicType($valueType)) && isset(self::$scalars[$basicType])) {
+            $field->kind = Field::SCALAR;
+            $field->type = self::$scalars[$basicType];
+            $field->pbType = new Type(self::$goScalars[$field->type]);
+        } else {
+            throw new Exception('Unsupported type ' . $type->getTypeString());
+        }
+
+        // Elements of repeated and map fields can not be null, only pointers to messages are allowed.
+        if ($field->pointer && $container && $field->kind !== Field::MESSAGE) {
+            throw new Exception('Unsupported type ' . $type->getTypeString());
+        }
+
+        if ($field->pointer && !$oneof && !$container && !$field->isMessage()) {
+            $field->label = Field::OPTIONAL;
+        }
+
+        return $field;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\TypeCast\CastFunctions;
+use Swaggest\GoCodeBuilder\TypeCast\CastRegistry;
+use Swaggest\GoCodeBuilder\TypeCast\RegistryMux;
+
+/**
+ * Converters renders MapTo and LoadFrom functions of generated structures and enums mapped by Builder,
+ * MapTo returns protoc generated value, LoadFrom loads generated value from protoc generated value.
+ *
+ * Times are converted to google.protobuf.Timestamp, so conversions do not fail.
+ */
+class Converters extends GoTemplate
+{
+    /** @var CastRegistry */
+    private $castRegistry;
+
+    /** @var CastFunctions[] */
+    private $casts = array();
+
+    /**
+     * Converters constructor.
+     * @param Builder $builder built mapping of messages and enums
+     * @param CastRegistry|null $castRegistry receives casts of messages and enums
+     */
+    public function __construct(Builder $builder, CastRegistry $castRegistry = null)
+    {
+        if ($castRegistry === null) {
+            $castRegistry = new CastRegistry();
+        }
+        $this->castRegistry = $castRegistry;
+
+        $registry = RegistryMux::getStd()->addRegistry($castRegistry);
+        foreach ($builder->getMessages() as $message) {
+            $this->casts[] = new MessageCast($message, $registry);
+        }
+        foreach ($builder->getEnums() as $enum) {
+            $this->casts[] = new EnumCast($enum);
+        }
+
+        foreach ($this->casts as $cast) {
+            $castRegistry->addStructCast($cast);
+        }
+    }
+
+    /**
+     * @return CastRegistry
+     */
+    public function getCastRegistry()
+    {
+        return $this->castRegistry;
+    }
+
+    protected function toString()
+    {
+        $result = '';
+        foreach ($this->casts as $cast) {
+            $result .= $cast->getMapTo()->render() . $cast->getLoadFrom()->render();
+        }
+
+        return $result;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+
+/**
+ * Enum is a generated enum type mapped to protobuf enum.
+ */
+class Enum
+{
+    /** @var string */
+    public $name;
+
+    /** @var Type */
+    public $type;
+
+    /** @var Type type of protoc generated enum */
+    public $pbType;
+
+    /** @var int[] value numbers by value names, including unspecified zero value */
+    public $values = array();
+
+    /** @var string[] Go constants of generated enum by value names */
+    public $constNames = array();
+
+    /** @var string Go zero value of generated enum */
+    public $zeroValue = '""';
+
+    /** @var int[] numbers of removed values by value names */
+    public $reserved = array();
+}
\ No newline at end of file
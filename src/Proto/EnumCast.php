@@ -0,0 +1,115 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\CodeBuilder\PlaceholderString;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Argument;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\TypeCast\CastFunctions;
+
+/**
+ * EnumCast converts values of generated enum to values of protoc generated enum and back.
+ */
+class EnumCast implements CastFunctions
+{
+    /** @var Enum */
+    private $enum;
+
+    /**
+     * EnumCast constructor.
+     * @param Enum $enum
+     */
+    public function __construct(Enum $enum)
+    {
+        $this->enum = $enum;
+    }
+
+    public function getMapTo()
+    {
+        $mapTo = new FuncDef('MapTo', 'MapTo converts ' . $this->enum->type->getName() . ' to protobuf enum.');
+        $mapTo->setSelf(new Argument('base', $this->enum->type));
+        $mapTo->setResult((new Result())->add(null, $this->enum->pbType));
+
+        $cases = '';
+        $binds = array(':unspecified' => $this->pbValue(key($this->enum->values)));
+        foreach (array_values($this->enum->constNames) as $i => $constName) {
+            $cases .= "case {$constName}:\n\treturn :value{$i}:\n";
+        }
+        $binds += $this->pbValues();
+
+        $mapTo->setBody(new Code(new PlaceholderString(<<<GO
+switch base {
+{$cases}}
+
+return :unspecified
+GO
+            , $binds)));
+
+        return $mapTo;
+    }
+
+    public function getLoadFrom()
+    {
+        $loadFrom = new FuncDef('LoadFrom', 'LoadFrom loads ' . $this->enum->type->getName() . ' from protobuf enum.');
+        $loadFrom->setSelf(new Argument('base', new Pointer($this->enum->type)));
+        $loadFrom->setArguments((new Arguments())->add('derived', $this->enum->pbType));
+
+        $cases = '';
+        foreach (array_values($this->enum->constNames) as $i => $constName) {
+            $cases .= "case :value{$i}::\n\t*base = {$constName}\n";
+        }
+
+        $loadFrom->setBody(new Code(new PlaceholderString(<<<GO
+switch derived {
+{$cases}default:
+	*base = {$this->enum->zeroValue}
+}
+GO
+            , $this->pbValues())));
+
+        return $loadFrom;
+    }
+
+    /**
+     * @return Type[] protoc generated values by placeholders
+     */
+    private function pbValues()
+    {
+        $values = array();
+        foreach (array_keys($this->enum->constNames) as $i => $name) {
+            $values[':value' . $i . ':'] = $this->pbValue($name);
+        }
+
+        return $values;
+    }
+
+    /**
+     * @param string $name
+     * @return Type
+     */
+    private function pbValue($name)
+    {
+        return new Type($this->enum->pbType->getName() . '_' . $name, $this->enum->pbType->getImport());
+    }
+
+    /**
+     * @return string
+     */
+    public function getBaseTypeString()
+    {
+        return $this->enum->type->getTypeString();
+    }
+
+    /**
+     * @return string
+     */
+    public function getDerivedTypeString()
+    {
+        return $this->enum->pbType->getTypeString();
+    }
+}
\ No newline at end of file
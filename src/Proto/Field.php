@@ -0,0 +1,120 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Map;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Slice;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+
+/**
+ * Field is a message field mapped to a property of generated structure.
+ */
+class Field
+{
+    const SCALAR = 'scalar';
+    const TIME = 'time';
+    const ENUM = 'enum';
+    const MESSAGE = 'message';
+
+    const OPTIONAL = 'optional';
+    const REPEATED = 'repeated';
+
+    /** @var string */
+    public $name;
+
+    /** @var int */
+    public $number;
+
+    /** @var string field name in protoc generated structure */
+    public $goName;
+
+    /** @var string|null optional or repeated */
+    public $label;
+
+    /** @var string|null key type of map field */
+    public $keyType;
+
+    /** @var string|null name of oneof that contains the field */
+    public $oneof;
+
+    /** @var StructProperty */
+    public $property;
+
+    /** @var string kind of value: scalar, time (google.protobuf.Timestamp), enum or message */
+    public $kind;
+
+    /** @var string proto type of value */
+    public $type;
+
+    /** @var Type Go type of value in generated structure */
+    public $goType;
+
+    /** @var bool whether value in generated structure is a pointer */
+    public $pointer = false;
+
+    /** @var Type Go type of value in protoc generated structure, message values are pointers */
+    public $pbType;
+
+    /** @var Message|null */
+    public $message;
+
+    /** @var Enum|null */
+    public $enum;
+
+    /**
+     * Returns whether value is a message in protoc generated structure, times are well-known Timestamp messages.
+     *
+     * @return bool
+     */
+    public function isMessage()
+    {
+        return $this->kind === self::MESSAGE || $this->kind === self::TIME;
+    }
+
+    /**
+     * Returns Go type of field in protoc generated structure.
+     *
+     * @return AnyType
+     */
+    public function getPbFieldType()
+    {
+        $type = $this->pbType;
+        if ($this->isMessage()) {
+            $type = new Pointer($type);
+        }
+
+        if ($this->label === self::REPEATED) {
+            return new Slice($type);
+        }
+
+        if ($this->keyType !== null) {
+            return new Map(new Type($this->keyType), $type);
+        }
+
+        if ($this->label === self::OPTIONAL) {
+            return new Pointer($type);
+        }
+
+        return $type;
+    }
+
+    /**
+     * @return string
+     */
+    public function render()
+    {
+        $type = $this->type;
+        if ($this->keyType !== null) {
+            $type = 'map<' . $this->keyType . ', ' . $type . '>';
+        }
+
+        if ($this->label !== null) {
+            $type = $this->label . ' ' . $type;
+        }
+
+        return $type . ' ' . $this->name . ' = ' . $this->number . ';';
+    }
+}
\ No newline at end of file
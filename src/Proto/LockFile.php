@@ -0,0 +1,169 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+/**
+ * LockFile keeps numbers of message fields and enum values so that they stay stable between generations.
+ *
+ * Numbers of removed fields and values are kept in lock file and rendered as reserved, new fields get numbers
+ * after the largest known number.
+ */
+class LockFile
+{
+    /** Field numbers reserved for the Protocol Buffers implementation. */
+    const RESERVED_MIN = 19000;
+    const RESERVED_MAX = 19999;
+
+    /** @var int[][] field numbers by message names and field names */
+    private $messages = array();
+
+    /** @var int[][] value numbers by enum names and value names */
+    private $enums = array();
+
+    /**
+     * Loads lock file, missing file is an empty lock.
+     *
+     * @param string $filePath
+     * @return LockFile
+     * @throws Exception
+     */
+    public static function load($filePath)
+    {
+        if (!file_exists($filePath)) {
+            return new self();
+        }
+
+        $data = json_decode(file_get_contents($filePath), true);
+        if (!is_array($data)) {
+            throw new Exception('Invalid lock file ' . $filePath . ': ' . json_last_error_msg());
+        }
+
+        return self::fromData($data);
+    }
+
+    /**
+     * @param array $data decoded lock file
+     * @return LockFile
+     */
+    public static function fromData(array $data)
+    {
+        $lockFile = new self();
+        if (isset($data['messages'])) {
+            $lockFile->messages = $data['messages'];
+        }
+        if (isset($data['enums'])) {
+            $lockFile->enums = $data['enums'];
+        }
+
+        return $lockFile;
+    }
+
+    /**
+     * @return \stdClass
+     */
+    public function toData()
+    {
+        $messages = new \stdClass();
+        foreach ($this->messages as $name => $fields) {
+            $messages->$name = (object)$fields;
+        }
+
+        $enums = new \stdClass();
+        foreach ($this->enums as $name => $values) {
+            $enums->$name = (object)$values;
+        }
+
+        return (object)array('messages' => $messages, 'enums' => $enums);
+    }
+
+    /**
+     * @param string $filePath
+     * @return $this
+     */
+    public function save($filePath)
+    {
+        file_put_contents($filePath, json_encode($this->toData(), JSON_PRETTY_PRINT | JSON_UNESCAPED_SLASHES) . "\n");
+
+        return $this;
+    }
+
+    /**
+     * Returns number of message field, unknown field gets a new number.
+     *
+     * @param string $message
+     * @param string $field
+     * @return int
+     */
+    public function fieldNumber($message, $field)
+    {
+        if (!isset($this->messages[$message][$field])) {
+            $numbers = isset($this->messages[$message]) ? $this->messages[$message] : array();
+            $this->messages[$message][$field] = self::next($numbers);
+        }
+
+        return $this->messages[$message][$field];
+    }
+
+    /**
+     * Returns number of enum value, unknown value gets a new number, 0 is left for unspecified value.
+     *
+     * @param string $enum
+     * @param string $value
+     * @return int
+     */
+    public function valueNumber($enum, $value)
+    {
+        if (!isset($this->enums[$enum][$value])) {
+            $numbers = isset($this->enums[$enum]) ? $this->enums[$enum] : array();
+            $this->enums[$enum][$value] = self::next($numbers);
+        }
+
+        return $this->enums[$enum][$value];
+    }
+
+    /**
+     * Returns numbers of locked fields that are not used anymore.
+     *
+     * @param string $message
+     * @param string[] $fields used field names
+     * @return int[] by field names
+     */
+    public function reservedFields($message, array $fields)
+    {
+        if (!isset($this->messages[$message])) {
+            return array();
+        }
+
+        return array_diff_key($this->messages[$message], array_flip($fields));
+    }
+
+    /**
+     * Returns numbers of locked enum values that are not used anymore.
+     *
+     * @param string $enum
+     * @param string[] $values used value names
+     * @return int[] by value names
+     */
+    public function reservedValues($enum, array $values)
+    {
+        if (!isset($this->enums[$enum])) {
+            return array();
+        }
+
+        return array_diff_key($this->enums[$enum], array_flip($values));
+    }
+
+    /**
+     * @param int[] $numbers
+     * @return int
+     */
+    private static function next(array $numbers)
+    {
+        $number = empty($numbers) ? 1 : max($numbers) + 1;
+        if ($number >= self::RESERVED_MIN && $number <= self::RESERVED_MAX) {
+            $number = self::RESERVED_MAX + 1;
+        }
+
+        return $number;
+    }
+}
\ No newline at end of file
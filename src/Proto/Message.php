@@ -0,0 +1,30 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+
+/**
+ * Message is a generated structure mapped to protobuf message.
+ */
+class Message
+{
+    /** @var string */
+    public $name;
+
+    /** @var string */
+    public $comment = '';
+
+    /** @var StructDef */
+    public $structDef;
+
+    /** @var Type type of protoc generated structure */
+    public $pbType;
+
+    /** @var Field[] by field names */
+    public $fields = array();
+
+    /** @var int[] numbers of removed fields by field names */
+    public $reserved = array();
+}
\ No newline at end of file
@@ -0,0 +1,418 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\CodeBuilder\PlaceholderString;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Argument;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Map;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\TypeCast\CastFunctions;
+use Swaggest\GoCodeBuilder\TypeCast\Registry;
+
+/**
+ * MessageCast converts generated structure to protoc generated structure and back.
+ *
+ * Nested messages and enums are converted with type registry, so registry should have casts of all messages
+ * and enums. Times are converted to and from google.protobuf.Timestamp.
+ */
+class MessageCast implements CastFunctions
+{
+    /** @var Message */
+    private $message;
+
+    /** @var Registry */
+    private $typeRegistry;
+
+    /** @var FuncDef */
+    private $mapTo;
+
+    /** @var FuncDef */
+    private $loadFrom;
+
+    /**
+     * MessageCast constructor.
+     * @param Message $message
+     * @param Registry $registry
+     */
+    public function __construct(Message $message, Registry $registry)
+    {
+        $this->message = $message;
+        $this->typeRegistry = $registry;
+    }
+
+    public function getMapTo()
+    {
+        // Function is cached before body is built to stop recursion of self-referencing messages.
+        if ($this->mapTo !== null) {
+            return $this->mapTo;
+        }
+
+        $baseType = $this->message->structDef->getType();
+        $this->mapTo = new FuncDef('MapTo', 'MapTo converts ' . $baseType->getName() . ' to protobuf message.');
+        $this->mapTo->setSelf(new Argument('base', $baseType));
+        $this->mapTo->setResult((new Result())->add(null, new Pointer($this->message->pbType)));
+
+        $code = $this->makeCode();
+        $code->addSnippet(new PlaceholderString("result := &:message:{}\n", array(':message:' => $this->message->pbType)));
+
+        $oneofs = array();
+        foreach ($this->message->fields as $field) {
+            if ($field->oneof === null) {
+                $code->addSnippet($this->mapToField($field));
+            } elseif (!isset($oneofs[$field->oneof])) {
+                $oneofs[$field->oneof] = true;
+                $code->addSnippet($this->mapToOneof($field->oneof));
+            }
+        }
+
+        $code->addSnippet('return result');
+        $this->mapTo->setBody($code);
+
+        return $this->mapTo;
+    }
+
+    public function getLoadFrom()
+    {
+        if ($this->loadFrom !== null) {
+            return $this->loadFrom;
+        }
+
+        $baseType = $this->message->structDef->getType();
+        $this->loadFrom = new FuncDef('LoadFrom', 'LoadFrom loads ' . $baseType->getName() . ' from protobuf message.');
+        $this->loadFrom->setSelf(new Argument('base', new Pointer($baseType)));
+        $this->loadFrom->setArguments((new Arguments())->add('derived', new Pointer($this->message->pbType)));
+
+        $code = $this->makeCode();
+        $code->addSnippet(new PlaceholderString(<<<GO
+*base = :base:{}
+if derived == nil {
+	return
+}
+
+
+GO
+            , array(':base:' => $baseType)));
+
+        $oneofs = array();
+        foreach ($this->message->fields as $field) {
+            if ($field->oneof === null) {
+                $code->addSnippet($this->loadFromField($field));
+            } elseif (!isset($oneofs[$field->oneof])) {
+                $oneofs[$field->oneof] = true;
+                $code->addSnippet($this->loadFromOneof($field->oneof));
+            }
+        }
+
+        $this->loadFrom->setBody($code);
+
+        return $this->loadFrom;
+    }
+
+    /**
+     * @return Code
+     */
+    private function makeCode()
+    {
+        $code = new Code();
+        foreach ($this->message->fields as $field) {
+            if ($field->kind === Field::TIME) {
+                // Imports are added to every file that renders cached function.
+                $code->imports()->addByName(Builder::TIMESTAMP_GO_PACKAGE);
+                break;
+            }
+        }
+
+        return $code;
+    }
+
+    /**
+     * @param Field $field
+     * @return PlaceholderString
+     */
+    private function mapToField(Field $field)
+    {
+        $to = 'result.' . $field->goName;
+        $from = 'base.' . $field->property->getName();
+        $binds = array(
+            ':fieldType:' => $field->getPbFieldType(),
+            ':valueType:' => $field->pbType,
+        );
+
+        if ($this->isSame($field->property->getType(), $field->getPbFieldType())) {
+            $code = "{$to} = {$from}";
+        } elseif ($field->label === Field::REPEATED || $field->keyType !== null) {
+            $key = $field->label === Field::REPEATED ? 'i' : 'k';
+            if ($field->pointer) {
+                $item = "if v != nil {\n" . $this->indent($this->mapToValue($field, "{$to}[{$key}]", 'v')) . "\n}";
+            } else {
+                $item = $this->mapToValue($field, "{$to}[{$key}]", 'v');
+            }
+
+            $code = <<<GO
+{$to} = make(:fieldType:, len({$from}))
+for {$key}, v := range {$from} {
+{$this->indent($item)}
+}
+GO;
+        } elseif ($field->pointer && $field->isMessage()) {
+            $value = $this->mapToValue($field, $to, $field->kind === Field::TIME ? '*' . $from : $from);
+            $code = "if {$from} != nil {\n" . $this->indent($value) . "\n}";
+        } elseif ($field->pointer) {
+            $value = $this->mapToValue($field, '*' . $to, $field->kind === Field::SCALAR ? '*' . $from : $from);
+            $code = <<<GO
+if {$from} != nil {
+	{$to} = new(:valueType:)
+{$this->indent($value)}
+}
+GO;
+        } else {
+            $code = $this->mapToValue($field, $to, $from);
+        }
+
+        return new PlaceholderString($code . "\n", $binds);
+    }
+
+    /**
+     * @param Field $field
+     * @return PlaceholderString
+     */
+    private function loadFromField(Field $field)
+    {
+        $to = 'base.' . $field->property->getName();
+        $from = 'derived.' . $field->goName;
+        $binds = array(
+            ':fieldType:' => $field->property->getType(),
+            ':valueType:' => $field->goType,
+        );
+
+        if ($this->isSame($field->property->getType(), $field->getPbFieldType())) {
+            $code = "{$to} = {$from}";
+        } elseif ($field->label === Field::REPEATED) {
+            if ($field->pointer) {
+                $item = "if v != nil {\n\t{$to}[i] = new(:valueType:)\n"
+                    . $this->indent($this->loadFromValue($field, "{$to}[i]", 'v')) . "\n}";
+            } else {
+                $item = $this->loadFromValue($field, "{$to}[i]", 'v');
+            }
+
+            $code = <<<GO
+{$to} = make(:fieldType:, len({$from}))
+for i, v := range {$from} {
+{$this->indent($item)}
+}
+GO;
+        } elseif ($field->keyType !== null) {
+            if ($field->pointer) {
+                // Map values are not addressable, so messages are loaded into a variable.
+                $item = "var item :itemType:\nif v != nil {\n\titem = new(:valueType:)\n"
+                    . $this->indent($this->loadFromValue($field, 'item', 'v')) . "\n}\n{$to}[k] = item";
+            } elseif ($field->kind === Field::ENUM || $field->kind === Field::MESSAGE) {
+                $item = "var item :itemType:\n" . $this->loadFromValue($field, 'item', 'v') . "\n{$to}[k] = item";
+            } else {
+                $item = $this->loadFromValue($field, "{$to}[k]", 'v');
+            }
+
+            $type = $field->property->getType();
+            if ($type instanceof Map) {
+                $binds[':itemType:'] = $type->getValueType();
+            }
+
+            $code = <<<GO
+{$to} = make(:fieldType:, len({$from}))
+for k, v := range {$from} {
+{$this->indent($item)}
+}
+GO;
+        } elseif ($field->pointer) {
+            $value = $field->kind === Field::MESSAGE
+                ? $this->loadFromValue($field, $to, $from)
+                : $this->loadFromValue($field, $field->kind === Field::ENUM ? $to : '*' . $to,
+                    $field->kind === Field::TIME ? $from : '*' . $from);
+            $code = <<<GO
+if {$from} != nil {
+	{$to} = new(:valueType:)
+{$this->indent($value)}
+}
+GO;
+        } elseif ($field->kind === Field::TIME) {
+            // Missing timestamp leaves zero time instead of Unix epoch.
+            $code = "if {$from} != nil {\n" . $this->indent($this->loadFromValue($field, $to, $from)) . "\n}";
+        } else {
+            $code = $this->loadFromValue($field, $to, $from);
+        }
+
+        return new PlaceholderString($code . "\n", $binds);
+    }
+
+    /**
+     * @param string $oneof
+     * @return PlaceholderString
+     */
+    private function mapToOneof($oneof)
+    {
+        $cases = '';
+        $binds = array();
+        foreach ($this->oneofFields($oneof) as $i => $field) {
+            $from = 'base.' . $field->property->getName();
+            $value = $this->mapToValue($field, 'item.' . $field->goName,
+                $field->kind === Field::SCALAR || $field->kind === Field::TIME ? '*' . $from : $from);
+            $cases .= <<<GO
+case {$from} != nil:
+	item := &:wrapper{$i}:{}
+{$this->indent($value)}
+	result.{$this->goName($oneof)} = item
+
+GO;
+            $binds[':wrapper' . $i . ':'] = $this->wrapperType($field);
+        }
+
+        return new PlaceholderString("switch {\n{$cases}}\n", $binds);
+    }
+
+    /**
+     * @param string $oneof
+     * @return PlaceholderString
+     */
+    private function loadFromOneof($oneof)
+    {
+        $cases = '';
+        $binds = array();
+        foreach ($this->oneofFields($oneof) as $i => $field) {
+            $to = 'base.' . $field->property->getName();
+            $from = 'v.' . $field->goName;
+            $value = $this->loadFromValue($field,
+                $field->kind === Field::ENUM || $field->kind === Field::MESSAGE ? $to : '*' . $to, $from);
+            $cases .= <<<GO
+case *:wrapper{$i}::
+	{$to} = new(:value{$i}:)
+{$this->indent($value)}
+
+GO;
+            $binds[':wrapper' . $i . ':'] = $this->wrapperType($field);
+            $binds[':value' . $i . ':'] = $field->goType;
+        }
+
+        return new PlaceholderString("switch v := derived.{$this->goName($oneof)}.(type) {\n{$cases}}\n", $binds);
+    }
+
+    /**
+     * Returns code that assigns converted value of generated structure to value of protoc generated structure.
+     *
+     * @param Field $field
+     * @param string $to
+     * @param string $from
+     * @return string
+     */
+    private function mapToValue(Field $field, $to, $from)
+    {
+        if ($field->kind === Field::SCALAR) {
+            return $to . ' = ' . $this->convert($field->pbType, $field->goType, $from);
+        }
+
+        if ($field->kind === Field::TIME) {
+            return $to . ' = timestamppb.New(' . $from . ')';
+        }
+
+        return $this->typeRegistry->process($field->pbType->getTypeString(), $field->goType->getTypeString(),
+            $to, $from, ' = ');
+    }
+
+    /**
+     * Returns code that assigns converted value of protoc generated structure to value of generated structure.
+     *
+     * @param Field $field
+     * @param string $to
+     * @param string $from
+     * @return string
+     */
+    private function loadFromValue(Field $field, $to, $from)
+    {
+        if ($field->kind === Field::SCALAR) {
+            return $to . ' = ' . $this->convert($field->goType, $field->pbType, $from);
+        }
+
+        if ($field->kind === Field::TIME) {
+            return $to . ' = ' . $from . '.AsTime()';
+        }
+
+        return $this->typeRegistry->process($field->goType->getTypeString(), $field->pbType->getTypeString(),
+            $to, $from, ' = ');
+    }
+
+    /**
+     * @param Type $toType
+     * @param Type $fromType
+     * @param string $from
+     * @return string
+     */
+    private function convert(Type $toType, Type $fromType, $from)
+    {
+        if ($this->isSame($toType, $fromType)) {
+            return $from;
+        }
+
+        return $toType->getName() . '(' . $from . ')';
+    }
+
+    private function isSame(AnyType $a, AnyType $b)
+    {
+        return $a->getTypeString() === $b->getTypeString();
+    }
+
+    /**
+     * @param string $oneof
+     * @return Field[]
+     */
+    private function oneofFields($oneof)
+    {
+        $fields = array();
+        foreach ($this->message->fields as $field) {
+            if ($field->oneof === $oneof) {
+                $fields[] = $field;
+            }
+        }
+
+        return $fields;
+    }
+
+    private function goName($oneof)
+    {
+        return Builder::goCamelCase($oneof);
+    }
+
+    /**
+     * @param Field $field
+     * @return Type
+     */
+    private function wrapperType(Field $field)
+    {
+        return new Type($this->message->pbType->getName() . '_' . $field->goName, $this->message->pbType->getImport());
+    }
+
+    private function indent($code)
+    {
+        return "\t" . str_replace("\n", "\n\t", $code);
+    }
+
+    /**
+     * @return string
+     */
+    public function getBaseTypeString()
+    {
+        return $this->message->structDef->getType()->getTypeString();
+    }
+
+    /**
+     * @return string
+     */
+    public function getDerivedTypeString()
+    {
+        return $this->message->pbType->getTypeString();
+    }
+}
\ No newline at end of file
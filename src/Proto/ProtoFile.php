@@ -0,0 +1,133 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Proto;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+/**
+ * ProtoFile renders proto3 definitions of messages and enums mapped by Builder.
+ */
+class ProtoFile extends GoTemplate
+{
+    /** @var Builder */
+    private $builder;
+
+    /**
+     * ProtoFile constructor.
+     * @param Builder $builder
+     */
+    public function __construct(Builder $builder)
+    {
+        $this->builder = $builder;
+    }
+
+    protected function toString()
+    {
+        $result = $this->renderComment();
+        $result .= 'syntax = "proto3";' . "\n\n"
+            . 'package ' . $this->builder->getPackage() . ';' . "\n\n";
+
+        if ($this->hasTimestamps()) {
+            $result .= 'import "' . Builder::TIMESTAMP_PROTO . '";' . "\n\n";
+        }
+
+        $result .= 'option go_package = "' . $this->builder->getGoPackage()->name . '";' . "\n";
+
+        foreach ($this->builder->getMessages() as $message) {
+            $result .= "\n" . $this->renderMessage($message);
+        }
+
+        foreach ($this->builder->getEnums() as $enum) {
+            $result .= "\n" . $this->renderEnum($enum);
+        }
+
+        return $result;
+    }
+
+    /**
+     * @return bool
+     */
+    private function hasTimestamps()
+    {
+        foreach ($this->builder->getMessages() as $message) {
+            foreach ($message->fields as $field) {
+                if ($field->kind === Field::TIME) {
+                    return true;
+                }
+            }
+        }
+
+        return false;
+    }
+
+    private function renderMessage(Message $message)
+    {
+        $body = '';
+        $oneofs = array();
+        foreach ($message->fields as $field) {
+            if ($field->oneof === null) {
+                $body .= '  ' . $field->render() . "\n";
+                continue;
+            }
+
+            // Fields of oneof are rendered together in place of the first one.
+            if (isset($oneofs[$field->oneof])) {
+                continue;
+            }
+            $oneofs[$field->oneof] = true;
+
+            $body .= '  oneof ' . $field->oneof . ' {' . "\n";
+            foreach ($message->fields as $oneofField) {
+                if ($oneofField->oneof === $field->oneof) {
+                    $body .= '    ' . $oneofField->render() . "\n";
+                }
+            }
+            $body .= '  }' . "\n";
+        }
+
+        $body .= $this->renderReserved($message->reserved);
+
+        return $this->renderDescription($message->comment) . 'message ' . $message->name . ' {' . "\n" . $body . '}' . "\n";
+    }
+
+    private function renderEnum(Enum $enum)
+    {
+        $body = '';
+        foreach ($enum->values as $name => $number) {
+            $body .= '  ' . $name . ' = ' . $number . ';' . "\n";
+        }
+
+        $body .= $this->renderReserved($enum->reserved);
+
+        return 'enum ' . $enum->name . ' {' . "\n" . $body . '}' . "\n";
+    }
+
+    /**
+     * @param int[] $reserved numbers by names
+     * @return string
+     */
+    private function renderReserved(array $reserved)
+    {
+        if (empty($reserved)) {
+            return '';
+        }
+
+        asort($reserved);
+        $names = array();
+        foreach (array_keys($reserved) as $name) {
+            $names[] = '"' . $name . '"';
+        }
+
+        return '  reserved ' . implode(', ', $reserved) . ';' . "\n"
+            . '  reserved ' . implode(', ', $names) . ';' . "\n";
+    }
+
+    private function renderDescription($description)
+    {
+        if ($description === '') {
+            return '';
+        }
+
+        return rtrim($this->padLines('// ', $description, false, true)) . "\n";
+    }
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Sample;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder infers JSON Schema from sample JSON documents and builds its types with GoBuilder.
+ *
+ * Properties that are missing in some samples are optional, properties with null values are nullable,
+ * strings with few mostly repeated values in enough samples are enums and numbers are integers unless a fraction
+ * is seen.
+ */
+class Builder
+{
+    const DEFINITIONS = 'definitions';
+
+    /** @var int maximum number of values of enum-like string, 0 disables enums */
+    public $enumMaxValues = 10;
+
+    /** @var int minimum number of string samples to infer enum */
+    public $enumMinSamples = 10;
+
+    /** @var float minimum share of string samples that repeat previously seen values to infer enum */
+    public $enumMinRepeatRatio = 0.5;
+
+    /** @var GoBuilder */
+    public $goBuilder;
+
+    /** @var Node */
+    private $root;
+
+    /**
+     * Builder constructor.
+     * @param GoBuilder|null $goBuilder
+     */
+    public function __construct(GoBuilder $goBuilder = null)
+    {
+        if ($goBuilder === null) {
+            $goBuilder = new GoBuilder();
+            $goBuilder->options->enableXNullable = true;
+        }
+
+        $this->goBuilder = $goBuilder;
+        $this->root = new Node();
+    }
+
+    /**
+     * Adds decoded sample value.
+     *
+     * @param mixed $value
+     * @return $this
+     */
+    public function addValue($value)
+    {
+        $this->root->add($value, $this->enumMaxValues);
+
+        return $this;
+    }
+
+    /**
+     * Adds sample JSON document.
+     *
+     * @param string $json
+     * @return $this
+     * @throws Exception
+     */
+    public function addJson($json)
+    {
+        $value = json_decode($json);
+        if (json_last_error() !== JSON_ERROR_NONE) {
+            throw new Exception('Invalid JSON: ' . json_last_error_msg());
+        }
+
+        return $this->addValue($value);
+    }
+
+    /**
+     * Adds sample JSON documents, one per line, empty lines are skipped.
+     *
+     * @param string $jsonLines
+     * @return $this
+     * @throws Exception
+     */
+    public function addJsonLines($jsonLines)
+    {
+        foreach (explode("\n", $jsonLines) as $i => $line) {
+            $line = trim($line);
+            if ($line === '') {
+                continue;
+            }
+
+            $value = json_decode($line);
+            if (json_last_error() !== JSON_ERROR_NONE) {
+                throw new Exception('Invalid JSON at line ' . ($i + 1) . ': ' . json_last_error_msg());
+            }
+
+            $this->addValue($value);
+        }
+
+        return $this;
+    }
+
+    /**
+     * Returns JSON Schema inferred from samples.
+     *
+     * @return \stdClass
+     * @throws Exception
+     */
+    public function getSchemaData()
+    {
+        if (empty($this->root->types)) {
+            throw new Exception('No samples added');
+        }
+
+        return $this->root->toSchema($this->enumMaxValues, $this->enumMinSamples, $this->enumMinRepeatRatio);
+    }
+
+    /**
+     * Builds type of samples, inferred schema is added to definitions with a name.
+     *
+     * @param string $name
+     * @return AnyType
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function build($name)
+    {
+        $pointer = '#/' . self::DEFINITIONS . '/' . $name;
+        $schema = Schema::import((object)array(
+            Schema::PROP_REF => $pointer,
+            self::DEFINITIONS => (object)array($name => $this->getSchemaData()),
+        ));
+
+        return $this->goBuilder->getType($schema, $pointer);
+    }
+}
\ No newline at end of file
@@ -0,0 +1,148 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Sample;
+
+use Swaggest\GoCodeBuilder\JsonSchema\TypeBuilder;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Node collects JSON types and values seen at a location of sample documents.
+ */
+class Node
+{
+    /** @var int[] number of values by JSON type */
+    public $types = array();
+
+    /** @var int[]|false number of occurrences by string value, false if there are too many distinct values */
+    public $strings = array();
+
+    /** @var Node|null */
+    public $items;
+
+    /** @var Node[] by property name */
+    public $properties = array();
+
+    /** @var int[] number of objects that contain property by property name */
+    public $presence = array();
+
+    /**
+     * Adds sample value.
+     *
+     * @param mixed $value decoded JSON value
+     * @param int $maxStrings maximum number of tracked distinct strings
+     */
+    public function add($value, $maxStrings)
+    {
+        if ($value === null) {
+            $this->count(Schema::NULL);
+        } elseif (is_bool($value)) {
+            $this->count(Schema::BOOLEAN);
+        } elseif (is_int($value)) {
+            $this->count(Schema::INTEGER);
+        } elseif (is_float($value)) {
+            $this->count(Schema::NUMBER);
+        } elseif (is_string($value)) {
+            $this->count(Schema::STRING);
+            if ($this->strings !== false) {
+                $this->strings[$value] = isset($this->strings[$value]) ? $this->strings[$value] + 1 : 1;
+                if (count($this->strings) > $maxStrings) {
+                    $this->strings = false;
+                }
+            }
+        } elseif (is_array($value)) {
+            $this->count(Schema::_ARRAY);
+            if ($this->items === null) {
+                $this->items = new Node();
+            }
+            foreach ($value as $item) {
+                $this->items->add($item, $maxStrings);
+            }
+        } elseif ($value instanceof \stdClass) {
+            $this->count(Schema::OBJECT);
+            foreach ($value as $name => $property) {
+                if (!isset($this->properties[$name])) {
+                    $this->properties[$name] = new Node();
+                    $this->presence[$name] = 0;
+                }
+                $this->presence[$name]++;
+                $this->properties[$name]->add($property, $maxStrings);
+            }
+        }
+    }
+
+    /**
+     * Returns inferred JSON Schema.
+     *
+     * @param int $enumMaxValues maximum number of enum values, 0 disables enums
+     * @param int $enumMinSamples minimum number of string samples to infer enum
+     * @param float $enumMinRepeatRatio minimum share of samples that repeat previously seen values
+     * @return \stdClass
+     */
+    public function toSchema($enumMaxValues, $enumMinSamples = 0, $enumMinRepeatRatio = 0.0)
+    {
+        $schema = new \stdClass();
+
+        $types = $this->types;
+        $nullable = isset($types[Schema::NULL]);
+        unset($types[Schema::NULL]);
+
+        // Integer values are valid numbers, fraction seen once makes all of them numbers.
+        if (isset($types[Schema::NUMBER])) {
+            unset($types[Schema::INTEGER]);
+        }
+
+        if (empty($types)) {
+            return $schema;
+        }
+
+        $typeNames = array_keys($types);
+        $schema->type = count($typeNames) === 1 ? $typeNames[0] : $typeNames;
+
+        // Strings are enum-like if there are enough samples with few distinct values that are mostly repeated,
+        // a single repeated value of an identifier or a name is not enough.
+        $samples = isset($types[Schema::STRING]) ? $types[Schema::STRING] : 0;
+        if ($schema->type === Schema::STRING && $enumMaxValues > 0 && $this->strings !== false
+            && count($this->strings) <= $enumMaxValues && $samples >= $enumMinSamples
+            && $samples > count($this->strings)
+            && ($samples - count($this->strings)) / $samples >= $enumMinRepeatRatio) {
+            $schema->enum = array();
+            foreach ($this->strings as $value => $count) {
+                $schema->enum[] = (string)$value;
+            }
+        }
+
+        if (isset($types[Schema::_ARRAY]) && $this->items !== null && !empty($this->items->types)) {
+            $schema->items = $this->items->toSchema($enumMaxValues, $enumMinSamples, $enumMinRepeatRatio);
+        }
+
+        if (isset($types[Schema::OBJECT])) {
+            $schema->properties = new \stdClass();
+            $required = array();
+            foreach ($this->properties as $name => $property) {
+                $name = (string)$name;
+                $schema->properties->$name = $property->toSchema($enumMaxValues, $enumMinSamples, $enumMinRepeatRatio);
+                if ($this->presence[$name] === $types[Schema::OBJECT]) {
+                    $required[] = $name;
+                }
+            }
+
+            if (!empty($required)) {
+                $schema->required = $required;
+            }
+        }
+
+        if ($nullable) {
+            $schema->{TypeBuilder::X_NULLABLE} = true;
+        }
+
+        return $schema;
+    }
+
+    /**
+     * @param string $type
+     */
+    private function count($type)
+    {
+        $this->types[$type] = isset($this->types[$type]) ? $this->types[$type] + 1 : 1;
+    }
+}
\ No newline at end of file
@@ -0,0 +1,28 @@
+<?php
+
+
+namespace Swaggest\GoCodeBuilder\Style;
+
+
+use Swaggest\GoCodeBuilder\Exception;
+
+class Literal
+{
+    /**
+     * Returns Go string literal, unlike GoTemplate::escapeValue it never falls back to a raw string.
+     *
+     * @param string $value
+     * @return string
+     * @throws Exception if value is not a valid UTF-8 string
+     */
+    public static function quote($value)
+    {
+        $result = json_encode($value, JSON_UNESCAPED_SLASHES | JSON_UNESCAPED_UNICODE);
+        if ($result === false) {
+            throw new Exception('Failed to quote Go string: ' . json_last_error_msg());
+        }
+
+        return $result;
+    }
+
+}
@@ -0,0 +1,296 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Swagger2;
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\Registry;
+use Swaggest\GoCodeBuilder\OpenAPI3\Builder as OpenAPI3Builder;
+use Swaggest\GoCodeBuilder\OpenAPI3\Operation;
+use Swaggest\GoCodeBuilder\OpenAPI3\Parameter;
+use Swaggest\GoCodeBuilder\OpenAPI3\Response;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+use Swaggest\JsonSchema\Schema;
+
+/**
+ * Builder walks operations of Swagger 2 document and builds the same request and response structures
+ * as OpenAPI 3 Builder, so that Client and Server templates can be used.
+ *
+ * Body parameter becomes request body, formData parameters are sent as URL-encoded or multipart form and
+ * collectionFormat of array parameters defines delimiter or repeated values.
+ */
+class Builder extends OpenAPI3Builder
+{
+    /** Document URL for schema references. */
+    const DOCUMENT_URL = 'swagger.json';
+
+    const IN_BODY = 'body';
+
+    /** @var string[] delimiters of array values by collectionFormat, multi sends repeated values */
+    public static $delimiters = array('csv' => ',', 'ssv' => ' ', 'tsv' => "\t", 'pipes' => '|');
+
+    /** @var string[] properties of parameter that do not describe its value */
+    public static $parameterKeywords = array('name', 'in', 'required', 'description', 'collectionFormat', 'allowEmptyValue');
+
+    /** @var AnyType[] types of definitions with discriminator and their subtypes by definition name */
+    private $discriminated = array();
+
+    /**
+     * Builder constructor.
+     * @param \stdClass $document decoded Swagger 2 document
+     * @param GoBuilder|null $goBuilder
+     */
+    public function __construct($document, GoBuilder $goBuilder = null)
+    {
+        parent::__construct($document, $goBuilder);
+        $this->pathToNameHook->prefixes[] = '#/definitions';
+    }
+
+    /**
+     * Builds operations of all paths and definitions with discriminator.
+     *
+     * @return $this
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    public function build()
+    {
+        parent::build();
+        $this->buildDiscriminated();
+
+        return $this;
+    }
+
+    /**
+     * Adds definitions with discriminator and definitions that extend them to registry by definition name,
+     * definition name is a discriminator value in Swagger 2.
+     *
+     * @param Registry $registry
+     * @return $this
+     */
+    public function register(Registry $registry)
+    {
+        foreach ($this->discriminated as $name => $type) {
+            $registry->add($name, $type);
+        }
+
+        return $this;
+    }
+
+    /**
+     * @param string $method
+     * @param string $path
+     * @param \stdClass $data
+     * @param string $pointer
+     * @param array[] $parameters pairs of parameter data and pointer
+     * @return Operation
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\OpenAPI3\Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    protected function buildOperation($method, $path, $data, $pointer, array $parameters)
+    {
+        $operation = $this->makeOperation($method, $path, $data);
+        $consumes = $this->mediaTypes('consumes', $data);
+
+        $fieldNames = array();
+        $body = null;
+        $hasForm = false;
+        $hasFile = false;
+        foreach ($parameters as $item) {
+            if ($item[0]->in === self::IN_BODY) {
+                $body = $item;
+                continue;
+            }
+
+            $parameter = $this->buildParameter($operation, $item[0], $item[1], $fieldNames);
+            $this->addParameter($operation, $parameter);
+            if ($parameter->in === Parameter::IN_FORM_DATA) {
+                $hasForm = true;
+                $hasFile = $hasFile || $parameter->file;
+            }
+        }
+
+        if ($hasForm) {
+            $operation->bodyContentType = $hasFile
+            || (in_array(self::FORM_MULTIPART, $consumes, true) && !in_array(self::FORM_URLENCODED, $consumes, true))
+                ? self::FORM_MULTIPART : self::FORM_URLENCODED;
+        } elseif ($body !== null) {
+            list($bodyData, $bodyPointer) = $body;
+            $mediaType = self::jsonMediaType((object)array_flip($consumes));
+            if ($mediaType !== null && isset($bodyData->schema)) {
+                $schemaPointer = $bodyPointer . '/schema';
+                $this->pathToNameHook->names[$schemaPointer] = $operation->name . 'RequestBody';
+
+                $operation->bodyRequired = !empty($bodyData->required);
+                $operation->bodyContentType = $mediaType;
+                $this->setBody($operation, $this->getType($schemaPointer), $fieldNames);
+            }
+        }
+
+        if (isset($data->responses)) {
+            $mediaType = self::jsonMediaType((object)array_flip($this->mediaTypes('produces', $data)));
+            foreach ($data->responses as $status => $responseData) {
+                $status = (string)$status;
+                list($responseData, $responsePointer) = $this->resolve($responseData, $pointer . '/responses/' . $status);
+
+                $response = new Response();
+                $response->status = $status;
+                $response->fieldName = self::statusName($status);
+                if (isset($responseData->description)) {
+                    $response->description = $responseData->description;
+                }
+
+                if ($mediaType !== null && isset($responseData->schema) && !self::isFile($responseData->schema)) {
+                    $schemaPointer = $responsePointer . '/schema';
+                    $this->pathToNameHook->names[$schemaPointer] = $operation->name . 'Response' . $response->fieldName;
+
+                    $response->contentType = $mediaType;
+                    $response->type = $this->getType($schemaPointer);
+                }
+
+                $this->addResponse($operation, $response);
+            }
+        }
+
+        $this->checkPathParameters($operation);
+
+        return $operation;
+    }
+
+    /**
+     * @param Operation $operation
+     * @param \stdClass $data
+     * @param string $pointer
+     * @param bool[] $fieldNames
+     * @return Parameter
+     * @throws Exception
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildParameter(Operation $operation, $data, $pointer, array &$fieldNames)
+    {
+        $parameter = new Parameter();
+        $parameter->name = $data->name;
+        $parameter->in = $data->in;
+        $parameter->required = $data->in === Parameter::IN_PATH || !empty($data->required);
+        if (isset($data->description)) {
+            $parameter->description = $data->description;
+        }
+
+        $parameter->fieldName = self::uniqueName(
+            $this->goBuilder->codeBuilder->exportableName($data->name),
+            $fieldNames,
+            $this->goBuilder->codeBuilder->exportableName($data->in)
+        );
+
+        if (self::isFile($data)) {
+            // File is streamed from multipart form, nil interface stands for a missing file.
+            $parameter->file = true;
+            $parameter->type = TypeUtil::fromString('io.Reader');
+            return $parameter;
+        }
+
+        // Parameter other than body is described with a subset of schema keywords, items can not have references.
+        $schemaData = new \stdClass();
+        foreach ($data as $key => $value) {
+            if (!in_array($key, static::$parameterKeywords, true)) {
+                $schemaData->$key = $value;
+            }
+        }
+
+        $this->pathToNameHook->names[$pointer] = $operation->name . $parameter->fieldName;
+        $type = $this->goBuilder->getType(Schema::import($schemaData), $pointer);
+        if (!$parameter->required && !self::isNillable($type)) {
+            $type = new Pointer($type);
+        }
+        $parameter->type = $type;
+
+        $collectionFormat = isset($data->collectionFormat) ? $data->collectionFormat : 'csv';
+        $parameter->explode = $collectionFormat === 'multi';
+        if (isset(static::$delimiters[$collectionFormat])) {
+            $parameter->delimiter = static::$delimiters[$collectionFormat];
+        } elseif (!$parameter->explode) {
+            throw new Exception('Unsupported collectionFormat "' . $collectionFormat . '" of parameter "'
+                . $data->name . '" in ' . $operation->method . ' ' . $operation->path);
+        }
+
+        return $parameter;
+    }
+
+    /**
+     * Builds types of definitions with discriminator and definitions that extend them with allOf.
+     *
+     * @throws \Swaggest\GoCodeBuilder\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\Exception
+     * @throws \Swaggest\JsonSchema\InvalidValue
+     */
+    private function buildDiscriminated()
+    {
+        $document = $this->getDocument();
+        if (!isset($document->definitions)) {
+            return;
+        }
+
+        $bases = array();
+        foreach ($document->definitions as $name => $definition) {
+            if (isset($definition->discriminator)) {
+                $bases['#/definitions/' . self::escapePointer($name)] = true;
+            }
+        }
+
+        foreach ($document->definitions as $name => $definition) {
+            $pointer = '#/definitions/' . self::escapePointer($name);
+            $discriminated = isset($bases[$pointer]);
+            if (!$discriminated && isset($definition->allOf)) {
+                foreach ($definition->allOf as $item) {
+                    if (isset($item->{'$ref'}) && isset($bases[$item->{'$ref'}])) {
+                        $discriminated = true;
+                        break;
+                    }
+                }
+            }
+
+            if ($discriminated) {
+                $this->discriminated[$name] = $this->getType($pointer);
+            }
+        }
+    }
+
+    /**
+     * Returns media types of operation or document, JSON is assumed by default.
+     *
+     * @param string $key consumes or produces
+     * @param \stdClass $data operation
+     * @return string[]
+     */
+    private function mediaTypes($key, $data)
+    {
+        if (isset($data->$key)) {
+            return $data->$key;
+        }
+
+        $document = $this->getDocument();
+        if (isset($document->$key)) {
+            return $document->$key;
+        }
+
+        return array('application/json');
+    }
+
+    /**
+     * @param \stdClass $schema parameter or response schema
+     * @return bool
+     */
+    private static function isFile($schema)
+    {
+        return isset($schema->type) && $schema->type === 'file';
+    }
+}
\ No newline at end of file
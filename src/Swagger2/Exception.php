@@ -0,0 +1,9 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Swagger2;
+
+
+class Exception extends \Exception
+{
+
+}
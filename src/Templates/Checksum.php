@@ -0,0 +1,101 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates;
+
+/**
+ * Checksum signs generated code to detect edits made by hand.
+ *
+ * Contents of protected regions and imports that are only used by them are not covered as they may be changed by hand.
+ */
+class Checksum
+{
+    const PREFIX = '// Checksum: sha256:';
+    const GENERATOR_PREFIX = '// Generator: ';
+
+    /**
+     * Adds checksum of body to file header.
+     *
+     * @param string $code
+     * @param int $headerLength length of file header comment including trailing blank line
+     * @param string|null $generatorVersion
+     * @return string
+     */
+    public static function sign($code, $headerLength, $generatorVersion = null)
+    {
+        $header = rtrim(substr($code, 0, $headerLength));
+        $body = (string)substr($code, $headerLength);
+
+        $lines = array();
+        if ($header !== '') {
+            $lines[] = $header;
+        }
+        if ($generatorVersion) {
+            $lines[] = self::GENERATOR_PREFIX . $generatorVersion;
+        }
+        $lines[] = self::PREFIX . self::digest($body);
+
+        return implode("\n", $lines) . "\n\n" . $body;
+    }
+
+    /**
+     * Checks if code was not changed after it was signed.
+     *
+     * @param string $code
+     * @return bool|null null if code has no checksum
+     */
+    public static function verify($code)
+    {
+        $prefix = preg_quote(self::PREFIX, '/');
+        if (!preg_match('/^' . $prefix . '([0-9a-f]{64})\n\n/m', $code, $match, PREG_OFFSET_CAPTURE)) {
+            return null;
+        }
+
+        // Checksum is only valid in leading comments of file.
+        $offset = $match[0][1];
+        if (!preg_match('/\A(?:\/\/[^\n]*\n|\/\*.*?\*\/[^\n]*\n|\n)*\z/s', substr($code, 0, $offset))) {
+            return null;
+        }
+
+        $body = (string)substr($code, $offset + strlen($match[0][0]));
+
+        return $match[1][0] === self::digest($body);
+    }
+
+    /**
+     * @param string $body
+     * @return string
+     */
+    private static function digest($body)
+    {
+        $regionImports = array();
+        foreach (ProtectedRegion::usedImports($body) as $import) {
+            $regionImports[$import->name] = true;
+        }
+        $body = ProtectedRegion::strip($body);
+        $references = ProtectedRegion::references($body);
+
+        $filterImports = function ($match) use ($regionImports, $references) {
+            $specs = '';
+            foreach (explode("\n", $match[1]) as $spec) {
+                if (trim($spec) === '') {
+                    continue;
+                }
+                foreach (ProtectedRegion::parseImports('import ' . trim($spec)) as $import) {
+                    $identifier = $import->alias ? $import->alias : $import->getPackage();
+                    if (isset($regionImports[$import->name]) && !isset($references[$identifier])) {
+                        continue 2;
+                    }
+                }
+                $specs .= $spec . "\n";
+            }
+
+            return $specs === '' ? '' : "import (\n" . $specs . ")\n";
+        };
+        $body = preg_replace_callback('/^import \(\n(.*?)^\)\n/ms', $filterImports, $body);
+
+        // Import declaration may be added or regrouped for contents of protected regions.
+        $body = preg_replace('/\n{3,}/', "\n\n", $body);
+
+        return hash('sha256', $body);
+    }
+}
@@ -71,4 +71,12 @@ class TypeConstBlock extends GoTemplate
         return $this->values;
     }
 
+    /**
+     * @return Type
+     */
+    public function getType()
+    {
+        return $this->type;
+    }
+
 }
\ No newline at end of file
@@ -16,6 +16,17 @@ class Arguments extends GoTemplate
         return $this;
     }
 
+    /**
+     * @return Argument[]
+     */
+    public function getItems()
+    {
+        if ($this->items === null) {
+            return array();
+        }
+        return $this->items;
+    }
+
     public function count()
     {
         return count($this->items);
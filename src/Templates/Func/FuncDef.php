@@ -4,6 +4,7 @@ namespace Swaggest\GoCodeBuilder\Templates\Func;
 
 use Swaggest\GoCodeBuilder\Templates\Code;
 use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeParams;
 
 class FuncDef extends GoTemplate
 {
@@ -19,6 +20,9 @@ class FuncDef extends GoTemplate
     /** @var string */
     private $name;
 
+    /** @var TypeParams|null */
+    private $typeParams;
+
     /**
      * FuncDef constructor.
      * @param string $name
@@ -89,7 +93,7 @@ class FuncDef extends GoTemplate
         if ($this->renderMode === self::RENDER_FUNC) {
             $body = $this->body ? $this->body->render() : '';
             $code = <<<GO
-{$this->renderComment()}func {$this->renderSelf()}{$this->name}({$this->arguments}){$result} {
+{$this->renderComment()}func {$this->renderSelf()}{$this->name}{$this->typeParams}({$this->arguments}){$result} {
 {$this->padLines("\t", $this->tabIndents($this->stripEmptyLines(trim($body))), false)}
 }
 
@@ -111,6 +115,24 @@ GO;
         return '';
     }
 
+    /**
+     * @return TypeParams|null
+     */
+    public function getTypeParams()
+    {
+        return $this->typeParams;
+    }
+
+    /**
+     * @param TypeParams|null $typeParams
+     * @return FuncDef
+     */
+    public function setTypeParams(TypeParams $typeParams = null)
+    {
+        $this->typeParams = $typeParams;
+        return $this;
+    }
+
     /**
      * @param Arguments $arguments
      * @return FuncDef
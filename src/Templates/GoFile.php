@@ -11,6 +11,50 @@ class GoFile extends GoTemplate
     const IMPORTS_KEY = 'imports';
 
     public $fileComment = 'Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.';
+
+    /**
+     * Format rendered code like gofmt does, so that external formatter is not needed.
+     * @var bool
+     */
+    public $gofmt = false;
+
+    /**
+     * Add checksum of code to file comment, so that edits made by hand can be detected.
+     * @var bool
+     * @see Checksum
+     */
+    public $checksum = false;
+
+    /**
+     * Generator version to add to file comment together with checksum.
+     * @var string|null
+     */
+    public $generatorVersion;
+
+    /**
+     * License text to put at the top of file, rendered as line comments unless it is a comment already.
+     * @var string|null
+     */
+    public $licenseHeader;
+
+    /**
+     * Build constraint expression, e.g. "linux && amd64", rendered as //go:build directive.
+     * @var string|null
+     */
+    public $buildConstraint;
+
+    /**
+     * Commands to render as //go:generate directives.
+     * @var string[]
+     */
+    public $generateDirectives = array();
+
+    /**
+     * Linters to disable for the whole file with //nolint directive, e.g. ["lll", "funlen"].
+     * @var string[]
+     */
+    public $nolint = array();
+
     private $package;
     private $importPath;
     /** @var Code */
@@ -22,6 +66,18 @@ class GoFile extends GoTemplate
     /** @var GoFile|null */
     private $transaction;
 
+    /**
+     * Contents of protected regions by name.
+     * @var string[]
+     */
+    private $preservedRegions = array();
+
+    /**
+     * Imports used in contents of protected regions.
+     * @var \Swaggest\GoCodeBuilder\Import[]
+     */
+    private $preservedImports = array();
+
 
     public function startTransaction()
     {
@@ -69,6 +125,20 @@ class GoFile extends GoTemplate
         return $this;
     }
 
+    /**
+     * @param string $path
+     * @return GoFile
+     * @throws Exception
+     */
+    public function setLicenseHeaderFromFile($path)
+    {
+        if (!is_readable($path)) {
+            throw new Exception('Can not read license header file: ' . $path);
+        }
+        $this->licenseHeader = file_get_contents($path);
+        return $this;
+    }
+
     /**
      * @return Imports
      */
@@ -77,6 +147,32 @@ class GoFile extends GoTemplate
         return $this->imports;
     }
 
+    /**
+     * Keeps contents of protected regions of previously generated code, imports used in regions are also kept.
+     *
+     * @param string $previousCode
+     * @return GoFile
+     * @see ProtectedRegion
+     */
+    public function preserveRegions($previousCode)
+    {
+        $this->preservedRegions = ProtectedRegion::parse($previousCode);
+        $this->preservedImports = ProtectedRegion::usedImports($previousCode);
+        return $this;
+    }
+
+    /**
+     * @param string $name
+     * @return string|null
+     */
+    public function getPreservedRegion($name)
+    {
+        if (isset($this->preservedRegions[$name])) {
+            return $this->preservedRegions[$name];
+        }
+        return null;
+    }
+
     /**
      * GoFile constructor.
      * @param string $package
@@ -160,15 +256,61 @@ class GoFile extends GoTemplate
 
     protected function toString()
     {
+        // Imports and dependent code are collected in copies, so that file can be rendered more than once.
+        $imports = $this->imports;
+        $depCodes = $this->depCodes;
+        $this->imports = clone $imports;
+
         $prevGoFile = self::getCurrentGoFile();
         self::setCurrentGoFile($this);
         /** @noinspection PhpUnusedLocalVariableInspection */
-        $_ = new ScopeExit(function () use ($prevGoFile) {
+        $_ = new ScopeExit(function () use ($prevGoFile, $imports, $depCodes) {
             self::setCurrentGoFile($prevGoFile);
+            $this->imports = $imports;
+            $this->depCodes = $depCodes;
         });
 
-        $codeResult = (string)$this->code;
+        foreach ($this->preservedImports as $import) {
+            if (!isset($this->imports->imports[$import->name])) {
+                $this->imports->add($import);
+            }
+        }
+
+        $codeResult = $this->renderCode();
 
+        // Code is rendered again with aliases for conflicting imports.
+        $reserved = $this->localSymbols($codeResult);
+        $reserved[] = $this->renderPackageName();
+        if ($this->imports->resolveAliases($reserved)) {
+            $this->depCodes = $depCodes;
+            $codeResult = $this->renderCode();
+        }
+
+        $fileComment = $this->renderLicenseHeader() . $this->renderFileComment();
+        $result = <<<GO
+{$fileComment}{$this->renderDirectives()}{$this->renderComment()}{$this->renderNolint()}package {$this->renderPackageName()}{$this->renderImportPath()}
+
+{$this->imports->render()}
+
+{$codeResult}
+
+GO;
+
+        if ($this->gofmt) {
+            $result = GoFmt::format($result);
+        }
+
+        if ($this->checksum) {
+            $headerLength = $fileComment !== '' && strpos($result, $fileComment) === 0 ? strlen($fileComment) : 0;
+            $result = Checksum::sign($result, $headerLength, $this->generatorVersion);
+        }
+
+        return $result;
+    }
+
+    private function renderCode()
+    {
+        $codeResult = (string)$this->code;
 
         $depCodesProcessed = array();
         while (!empty($this->depCodes)) {
@@ -182,18 +324,37 @@ class GoFile extends GoTemplate
             }
         }
 
-        $codeResult = rtrim($codeResult);
-
-        $result = <<<GO
-{$this->renderFileComment()}{$this->renderComment()}package {$this->renderPackageName()}{$this->renderImportPath()}
-
-{$this->imports->render()}
+        return rtrim($codeResult);
+    }
 
-{$codeResult}
+    /**
+     * Returns identifiers of top level declarations.
+     *
+     * @param string $code
+     * @return string[]
+     */
+    private function localSymbols($code)
+    {
+        $symbols = array();
+        $inBlock = false;
+        foreach (explode("\n", $code) as $line) {
+            if ($inBlock) {
+                if ($line === ')') {
+                    $inBlock = false;
+                } elseif (preg_match('/^\t([A-Za-z_]\w*)/', $line, $match)) {
+                    $symbols[] = $match[1];
+                }
+                continue;
+            }
 
-GO;
+            if (preg_match('/^(?:type|var|const) \($/', $line)) {
+                $inBlock = true;
+            } elseif (preg_match('/^(?:type|var|const|func) ([A-Za-z_]\w*)/', $line, $match)) {
+                $symbols[] = $match[1];
+            }
+        }
 
-        return $result;
+        return $symbols;
     }
 
     private function renderFileComment()
@@ -204,6 +365,45 @@ GO;
         return '';
     }
 
+    private function renderLicenseHeader()
+    {
+        $license = trim((string)$this->licenseHeader, "\n");
+        if ($license === '') {
+            return '';
+        }
+
+        if (strpos($license, '/*') === 0 || preg_match('/\A(?:\/\/[^\n]*(?:\n|\z))+\z/', $license)) {
+            return rtrim($license) . "\n\n";
+        }
+
+        return rtrim($this->padLines('// ', $license, false, true)) . "\n\n";
+    }
+
+    private function renderDirectives()
+    {
+        $result = '';
+        if ($this->buildConstraint) {
+            $result .= '//go:build ' . $this->buildConstraint . "\n\n";
+        }
+
+        if ($this->generateDirectives) {
+            foreach ($this->generateDirectives as $command) {
+                $result .= '//go:generate ' . $command . "\n";
+            }
+            $result .= "\n";
+        }
+
+        return $result;
+    }
+
+    private function renderNolint()
+    {
+        if ($this->nolint) {
+            return '//nolint:' . implode(',', $this->nolint) . "\n";
+        }
+        return '';
+    }
+
     private function renderImportPath()
     {
         if ($this->importPath && !$this->skipImportComment) {
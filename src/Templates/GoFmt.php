@@ -0,0 +1,807 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates;
+
+/**
+ * GoFmt formats Go source code like gofmt does for code rendered by templates.
+ *
+ * Indentation is normalized to tabs, struct fields, value specs, composite literal values and
+ * trailing comments are aligned in columns, blank lines are collapsed and top level declarations are separated.
+ * Code is not parsed, so only line layout is changed.
+ */
+class GoFmt
+{
+    const VTAB = "\v";
+    const HTAB = "\t";
+
+    const KIND_STRUCT = 'struct';
+    const KIND_IFACE = 'interface';
+    const KIND_SWITCH = 'switch';
+    const KIND_BLOCK = 'block';
+    const KIND_COMPOSITE = 'composite';
+    const KIND_PAREN = 'paren';
+
+    private static $opening = array('{' => true, '(' => true, '[' => true);
+    private static $closing = array('}' => true, ')' => true, ']' => true);
+
+    /**
+     * Containers that drop blank lines after opening bracket.
+     * @var bool[]
+     */
+    private static $noLeadingBlank = array(
+        self::KIND_STRUCT => true,
+        self::KIND_IFACE => true,
+        'const' => true,
+        'var' => true,
+        'type' => true,
+        'import' => true,
+    );
+
+    /**
+     * Containers that drop blank lines before closing bracket.
+     * @var bool[]
+     */
+    private static $noTrailingBlank = array(
+        self::KIND_STRUCT => true,
+        self::KIND_IFACE => true,
+        'const' => true,
+        'var' => true,
+        'type' => true,
+        'import' => true,
+        self::KIND_COMPOSITE => true,
+        self::KIND_PAREN => true,
+    );
+
+    /**
+     * @param string $code
+     * @return string
+     */
+    public static function format($code)
+    {
+        $lines = self::scan(explode("\n", $code));
+        self::indent($lines);
+        $lines = self::collapseBlankLines($lines);
+        $lines = self::separateDeclarations($lines);
+        self::countItems($lines);
+        self::keepTypeColumns($lines);
+
+        $result = array();
+        foreach (self::sections($lines) as $section) {
+            foreach (self::tabWrite($section) as $line) {
+                $result[] = $line;
+            }
+        }
+
+        while (!empty($result) && end($result) === '') {
+            array_pop($result);
+        }
+        while (!empty($result) && $result[0] === '') {
+            array_shift($result);
+        }
+
+        return implode("\n", $result) . "\n";
+    }
+
+    /**
+     * Splits lines into code and comment parts, collects brackets outside of literals and comments.
+     *
+     * @param string[] $textLines
+     * @return \stdClass[]
+     */
+    private static function scan(array $textLines)
+    {
+        $state = null; // Inside raw string "`" or block comment "/*".
+        $lines = array();
+        foreach ($textLines as $text) {
+            $line = new \stdClass();
+            $line->text = $text;
+            $line->verbatim = $state !== null;
+            $line->comment = '';
+            $line->brackets = array();
+
+            $n = strlen($text);
+            $i = 0;
+            while ($i < $n) {
+                $c = $text[$i];
+                if ($state === '`') {
+                    if ($c === '`') {
+                        $state = null;
+                    }
+                    ++$i;
+                    continue;
+                }
+                if ($state === '/*') {
+                    if (substr($text, $i, 2) === '*/') {
+                        $state = null;
+                        $i += 2;
+                        continue;
+                    }
+                    ++$i;
+                    continue;
+                }
+                if ($c === '"' || $c === "'") {
+                    $j = $i + 1;
+                    while ($j < $n && $text[$j] !== $c) {
+                        if ($text[$j] === '\\') {
+                            ++$j;
+                        }
+                        ++$j;
+                    }
+                    $i = $j + 1;
+                    continue;
+                }
+                if ($c === '`') {
+                    $state = '`';
+                    ++$i;
+                    continue;
+                }
+                if (substr($text, $i, 2) === '//') {
+                    $line->comment = rtrim(substr($text, $i));
+                    $n = $i;
+                    break;
+                }
+                if (substr($text, $i, 2) === '/*') {
+                    $state = '/*';
+                    $i += 2;
+                    continue;
+                }
+                if (isset(self::$opening[$c]) || isset(self::$closing[$c])) {
+                    $line->brackets[] = array($c, $i);
+                }
+                ++$i;
+            }
+
+            if ($line->verbatim) {
+                $line->code = substr($text, 0, $n);
+            } else {
+                $line->code = trim(substr($text, 0, $n));
+                $shift = strlen($text) - strlen(ltrim($text));
+                foreach ($line->brackets as &$bracket) {
+                    $bracket[1] -= $shift;
+                }
+                unset($bracket);
+            }
+
+            $lines[] = $line;
+        }
+
+        return $lines;
+    }
+
+    /**
+     * Computes indentation and container of every line.
+     *
+     * @param \stdClass[] $lines
+     */
+    private static function indent(array $lines)
+    {
+        /** @var \stdClass[] $stack */
+        $stack = array();
+        foreach ($lines as $line) {
+            $brackets = $line->brackets;
+            $line->closed = array();
+
+            if (!$line->verbatim) {
+                $lead = 0;
+                $len = strlen($line->code);
+                while ($lead < $len && isset(self::$closing[$line->code[$lead]])) {
+                    ++$lead;
+                }
+                for ($k = 0; $k < $lead; ++$k) {
+                    if (!empty($stack)) {
+                        $line->closed[] = array_pop($stack);
+                    }
+                }
+                $brackets = array_slice($brackets, $lead);
+            }
+
+            $line->container = empty($stack) ? null : end($stack);
+            $indent = empty($stack) ? 0 : end($stack)->level;
+            if (!empty($line->closed)) {
+                // Closing line is aligned with the line that opened the container.
+                $indent = end($line->closed)->level - 1;
+            }
+
+            if (!$line->verbatim && $line->container !== null && $line->container->kind === self::KIND_SWITCH
+                && preg_match('/^(case\b.*|default\s*):$/', $line->code)) {
+                --$indent;
+            }
+            $line->indent = $indent;
+
+            $line->pushed = array();
+            foreach ($brackets as $bracket) {
+                if (isset(self::$opening[$bracket[0]])) {
+                    $container = new \stdClass();
+                    $container->kind = $line->verbatim ? self::KIND_PAREN : self::kind($line->code, $bracket[1]);
+                    $container->level = $indent + 1;
+                    $container->items = 0;
+                    $container->size = 0;
+                    $container->log2Sum = 0.0;
+                    $container->count = 0;
+                    $stack[] = $container;
+                    $line->pushed[] = $container;
+                } elseif (!empty($stack)) {
+                    $container = array_pop($stack);
+                    $index = array_search($container, $line->pushed, true);
+                    if ($index !== false) {
+                        unset($line->pushed[$index]);
+                        $line->pushed = array_values($line->pushed);
+                    }
+                }
+            }
+
+            $line->blank = !$line->verbatim && $line->code === '' && $line->comment === '';
+        }
+    }
+
+    /**
+     * Detects container kind of opening bracket.
+     *
+     * @param string $code
+     * @param int $pos
+     * @return string
+     */
+    private static function kind($code, $pos)
+    {
+        $before = rtrim(substr($code, 0, $pos));
+        if ($code[$pos] === '(') {
+            if (preg_match('/^(const|var|type|import)$/', $before)) {
+                return $before;
+            }
+            return self::KIND_PAREN;
+        }
+        if ($code[$pos] === '[') {
+            return self::KIND_PAREN;
+        }
+        if (preg_match('/\bstruct$/', $before)) {
+            return self::KIND_STRUCT;
+        }
+        if (preg_match('/\binterface$/', $before)) {
+            return self::KIND_IFACE;
+        }
+
+        $statement = preg_replace('/^[})\]]+\s*/', '', $before);
+        if (preg_match('/^(switch|select)\b/', $statement)) {
+            return self::KIND_SWITCH;
+        }
+        if ($before !== '' && (preg_match('/^(if|for|else|go|defer|func)\b/', $statement) || substr($before, -1) === ')')) {
+            return self::KIND_BLOCK;
+        }
+
+        return self::KIND_COMPOSITE;
+    }
+
+    /**
+     * Removes repeated blank lines and blank lines at container boundaries.
+     *
+     * @param \stdClass[] $lines
+     * @return \stdClass[]
+     */
+    private static function collapseBlankLines(array $lines)
+    {
+        $result = array();
+        $count = count($lines);
+        foreach ($lines as $index => $line) {
+            if ($line->blank) {
+                if (empty($result) || end($result)->blank) {
+                    continue;
+                }
+
+                $prev = end($result);
+                if (!empty($prev->pushed) && isset(self::$noLeadingBlank[end($prev->pushed)->kind])) {
+                    continue;
+                }
+
+                $j = $index + 1;
+                while ($j < $count && $lines[$j]->blank) {
+                    ++$j;
+                }
+                if ($j === $count) {
+                    continue;
+                }
+
+                // Blank line after trailing comment is kept.
+                $next = $lines[$j];
+                if (!empty($next->closed) && isset(self::$noTrailingBlank[$next->closed[0]->kind]) && $prev->comment === '') {
+                    continue;
+                }
+            }
+
+            $result[] = $line;
+        }
+
+        return $result;
+    }
+
+    /**
+     * Adds blank lines between top level declarations of different kinds and before documented declarations.
+     *
+     * @param \stdClass[] $lines
+     * @return \stdClass[]
+     */
+    private static function separateDeclarations(array $lines)
+    {
+        $result = array();
+        $prevToken = null;
+        $count = count($lines);
+        for ($i = 0; $i < $count; ++$i) {
+            $line = $lines[$i];
+            if (!self::isTopLevelComment($line) && self::declarationToken($line) === null) {
+                $result[] = $line;
+                continue;
+            }
+
+            $j = $i;
+            while ($j < $count && self::isTopLevelComment($lines[$j])) {
+                ++$j;
+            }
+
+            $token = $j < $count ? self::declarationToken($lines[$j]) : null;
+            if ($token === null) {
+                $result[] = $line;
+                continue;
+            }
+
+            if ($token !== 'package' && !empty($result) && !end($result)->blank
+                && ($prevToken !== $token || $j > $i)) {
+                $blank = new \stdClass();
+                $blank->text = '';
+                $blank->verbatim = false;
+                $blank->code = '';
+                $blank->comment = '';
+                $blank->brackets = array();
+                $blank->closed = array();
+                $blank->pushed = array();
+                $blank->container = null;
+                $blank->indent = 0;
+                $blank->blank = true;
+                $result[] = $blank;
+            }
+            $prevToken = $token;
+
+            for (; $i <= $j; ++$i) {
+                $result[] = $lines[$i];
+            }
+            --$i;
+        }
+
+        return $result;
+    }
+
+    private static function isTopLevelComment(\stdClass $line)
+    {
+        return !$line->verbatim && $line->container === null && empty($line->closed)
+            && $line->code === '' && $line->comment !== '';
+    }
+
+    /**
+     * @param \stdClass $line
+     * @return string|null
+     */
+    private static function declarationToken(\stdClass $line)
+    {
+        if ($line->verbatim || $line->container !== null) {
+            return null;
+        }
+
+        if (preg_match('/^(package|import|const|var|type|func)\b/', $line->code, $match)) {
+            return $match[1];
+        }
+
+        return null;
+    }
+
+    /**
+     * Counts items of containers to detect single item lists that are not aligned.
+     *
+     * @param \stdClass[] $lines
+     */
+    private static function countItems(array $lines)
+    {
+        foreach ($lines as $line) {
+            if ($line->container !== null && !$line->verbatim && empty($line->closed) && $line->code !== '') {
+                ++$line->container->items;
+            }
+        }
+    }
+
+    /**
+     * Parses value specs and marks runs of specs that keep type column, like gofmt does.
+     *
+     * @param \stdClass[] $lines
+     */
+    private static function keepTypeColumns(array $lines)
+    {
+        $groups = array();
+        foreach ($lines as $line) {
+            $line->spec = null;
+            $container = $line->container;
+            if ($container === null || ($container->kind !== 'const' && $container->kind !== 'var')
+                || $line->verbatim || !empty($line->closed) || $line->code === '') {
+                continue;
+            }
+
+            if (preg_match('/^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)(?:\s+([^=\s][^=]*?))?(?:\s*=\s*(.*))?$/',
+                $line->code, $match)) {
+                $line->spec = array(
+                    'names' => $match[1],
+                    'type' => isset($match[2]) && $match[2] !== '' ? $match[2] : null,
+                    'values' => isset($match[3]) ? $match[3] : null,
+                    'keepType' => false,
+                );
+                $groups[spl_object_hash($container)][] = $line;
+            }
+        }
+
+        foreach ($groups as $specs) {
+            $start = -1;
+            $keepType = false;
+            foreach ($specs as $i => $line) {
+                if ($line->spec['values'] !== null) {
+                    if ($start < 0) {
+                        $start = $i;
+                        $keepType = false;
+                    }
+                } elseif ($start >= 0) {
+                    self::populateKeepType($specs, $start, $i, $keepType);
+                    $start = -1;
+                }
+                if ($line->spec['type'] !== null) {
+                    $keepType = true;
+                }
+            }
+            if ($start >= 0) {
+                self::populateKeepType($specs, $start, count($specs), $keepType);
+            }
+        }
+    }
+
+    /**
+     * @param \stdClass[] $specs
+     * @param int $from
+     * @param int $to
+     * @param bool $keepType
+     */
+    private static function populateKeepType(array $specs, $from, $to, $keepType)
+    {
+        if (!$keepType) {
+            return;
+        }
+        for ($i = $from; $i < $to; ++$i) {
+            $specs[$i]->spec['keepType'] = true;
+        }
+    }
+
+    /**
+     * Splits lines into cells and groups them in sections that are aligned independently.
+     *
+     * @param \stdClass[] $lines
+     * @return array[]
+     */
+    private static function sections(array $lines)
+    {
+        $sections = array();
+        $section = array();
+
+        foreach ($lines as $line) {
+            if ($line->verbatim) {
+                $section[] = array(array(), $line->text);
+                if (!empty($line->pushed)) {
+                    $sections[] = $section;
+                    $section = array();
+                }
+                continue;
+            }
+
+            $container = $line->container;
+
+            if ($line->blank) {
+                if ($container !== null && $container->kind === self::KIND_COMPOSITE) {
+                    $container->log2Sum = 0.0;
+                    $container->count = 0;
+                }
+                $section[] = array(array(), '');
+                continue;
+            }
+
+            if (!empty($line->closed)) {
+                $sections[] = $section;
+                $section = array();
+            }
+
+            $tokens = null;
+            $kind = $container === null ? null : $container->kind;
+            if ($line->code !== '' && empty($line->closed)) {
+                if ($kind === self::KIND_STRUCT) {
+                    $tokens = self::fieldTokens($line->code, $line->comment !== '', $container->items > 1);
+                } elseif (($kind === 'const' || $kind === 'var') && $container->items > 1 && $line->spec !== null) {
+                    $tokens = self::specTokens($line->spec, $line->comment !== '');
+                } elseif ($kind === 'type' && $container->items > 1
+                    && preg_match('/^([A-Za-z_]\w*)\s+(.*)$/', $line->code, $match)) {
+                    $tokens = array($match[1], self::VTAB, $match[2]);
+                } elseif ($kind === self::KIND_COMPOSITE) {
+                    if (self::elementBreaksSection($line)) {
+                        $sections[] = $section;
+                        $section = array();
+                    }
+                    if ($container->items > 1 && $container->size > 0
+                        && preg_match('/^("(?:[^"\\\\]|\\\\.)*"|[\w.]+):\s+(.*)$/', $line->code, $match)) {
+                        $tokens = array($match[1] . ':', self::VTAB, $match[2]);
+                    }
+                }
+            }
+
+            if ($tokens === null) {
+                $tokens = $line->code === '' ? array() : array($line->code);
+            }
+
+            if ($line->comment !== '') {
+                if (!empty($tokens) && end($tokens) !== self::VTAB) {
+                    $tokens[] = self::HTAB;
+                }
+                $tokens[] = $line->comment;
+            }
+
+            $cells = array_fill(0, max(0, $line->indent), array('', true));
+            $text = '';
+            foreach ($tokens as $token) {
+                if ($token === self::VTAB || $token === self::HTAB) {
+                    $cells[] = array($text, $token === self::HTAB);
+                    $text = '';
+                } else {
+                    $text .= $token;
+                }
+            }
+            $section[] = array($cells, $text);
+
+            if (!empty($line->pushed) || !empty($line->closed)) {
+                $sections[] = $section;
+                $section = array();
+            }
+        }
+        $sections[] = $section;
+
+        return $sections;
+    }
+
+    /**
+     * Decides if composite literal element starts new alignment section, mimics gofmt key size heuristics.
+     *
+     * @param \stdClass $line
+     * @return bool
+     */
+    private static function elementBreaksSection(\stdClass $line)
+    {
+        $container = $line->container;
+        $prevSize = $container->size;
+
+        if (!empty($line->pushed)) {
+            $size = 0; // Multi-line element.
+        } elseif (preg_match('/^("(?:[^"\\\\]|\\\\.)*"|[\w.]+):\s+/', $line->code, $match)) {
+            $size = self::width($match[1]);
+        } else {
+            $size = self::width(rtrim($line->code, ','));
+        }
+
+        $breaks = true;
+        if ($prevSize > 0 && $size > 0) {
+            if ($container->count === 0 || ($prevSize <= 40 && $size <= 40)) {
+                $breaks = false;
+            } else {
+                $ratio = $size / pow(2, $container->log2Sum / $container->count);
+                $breaks = 2.5 * $ratio <= 1 || 2.5 <= $ratio;
+            }
+        }
+
+        if ($size > 0) {
+            $container->log2Sum += log($size, 2);
+            ++$container->count;
+        }
+        $container->size = $size;
+
+        return $breaks;
+    }
+
+    /**
+     * Makes tokens of struct field.
+     *
+     * @param string $code
+     * @param bool $hasComment
+     * @param bool $aligned
+     * @return string[]
+     */
+    private static function fieldTokens($code, $hasComment, $aligned)
+    {
+        $tag = null;
+        if (preg_match('/^(.*?)\s*(`[^`]*`)$/', $code, $match) && $match[1] !== '') {
+            $code = $match[1];
+            $tag = $match[2];
+        }
+
+        // Closing bracket of multi-line type is followed by tag of named field.
+        $isTypeEnd = isset(self::$closing[$code[0]]);
+        $names = null;
+        $type = $code;
+        $extraTabs = 2;
+        if (!$isTypeEnd && preg_match('/^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s+(\S.*)$/', $code, $match)) {
+            $names = preg_replace('/\s*,\s*/', ', ', $match[1]);
+            $type = $match[2];
+            $extraTabs = 1;
+        }
+
+        if (!$aligned) {
+            $text = $names === null ? $type : $names . ' ' . $type;
+            if ($tag !== null) {
+                $text .= ' ' . $tag;
+            }
+            return array($text);
+        }
+
+        $tokens = $names === null ? array($type) : array($names, self::VTAB, $type);
+        if ($tag !== null) {
+            if ($names !== null || $isTypeEnd) {
+                $tokens[] = self::VTAB;
+            }
+            $tokens[] = self::VTAB;
+            $tokens[] = $tag;
+            $extraTabs = 0;
+        }
+
+        if ($hasComment) {
+            for (; $extraTabs > 0; --$extraTabs) {
+                $tokens[] = self::VTAB;
+            }
+        }
+
+        return $tokens;
+    }
+
+    /**
+     * Makes tokens of value spec in const or var group.
+     *
+     * @param array $spec
+     * @param bool $hasComment
+     * @return string[]
+     */
+    private static function specTokens(array $spec, $hasComment)
+    {
+        $tokens = array(preg_replace('/\s*,\s*/', ', ', $spec['names']));
+        $extraTabs = 3;
+        if ($spec['type'] !== null || $spec['keepType']) {
+            $tokens[] = self::VTAB;
+            $tokens[] = $spec['type'] === null ? '' : trim($spec['type']);
+            --$extraTabs;
+        }
+        if ($spec['values'] !== null) {
+            $tokens[] = self::VTAB;
+            $tokens[] = '= ' . trim($spec['values']);
+            --$extraTabs;
+        }
+
+        if ($hasComment) {
+            for (; $extraTabs > 0; --$extraTabs) {
+                $tokens[] = self::VTAB;
+            }
+        }
+
+        return $tokens;
+    }
+
+    /**
+     * Aligns cells in columns with elastic tabstops, like text/tabwriter with gofmt settings.
+     *
+     * @param array[] $lines list of cells and trailing text
+     * @return string[]
+     */
+    private static function tabWrite(array $lines)
+    {
+        $result = array();
+        $widths = array();
+        self::formatColumns($lines, 0, count($lines), $widths, $result);
+
+        return $result;
+    }
+
+    /**
+     * @param array[] $lines
+     * @param int $line0
+     * @param int $line1
+     * @param int[] $widths
+     * @param string[] $result
+     */
+    private static function formatColumns(array $lines, $line0, $line1, array $widths, array &$result)
+    {
+        $column = count($widths);
+        $row = $line0;
+        while ($row < $line1) {
+            if ($column >= count($lines[$row][0])) {
+                ++$row;
+                continue;
+            }
+
+            self::writeLines($lines, $line0, $row, $widths, $result);
+            $line0 = $row;
+
+            $width = 0;
+            $discardable = true;
+            for (; $row < $line1; ++$row) {
+                $cells = $lines[$row][0];
+                if ($column >= count($cells)) {
+                    break;
+                }
+                list($text, $htab) = $cells[$column];
+                $w = self::width($text) + 1;
+                if ($w > $width) {
+                    $width = $w;
+                }
+                if ($text !== '' || $htab) {
+                    $discardable = false;
+                }
+            }
+
+            // Empty columns of soft tabs are discarded.
+            if ($discardable) {
+                $width = 0;
+            }
+
+            $columnWidths = $widths;
+            $columnWidths[] = $width;
+            self::formatColumns($lines, $line0, $row, $columnWidths, $result);
+            $line0 = $row;
+        }
+
+        self::writeLines($lines, $line0, $line1, $widths, $result);
+    }
+
+    /**
+     * @param array[] $lines
+     * @param int $line0
+     * @param int $line1
+     * @param int[] $widths
+     * @param string[] $result
+     */
+    private static function writeLines(array $lines, $line0, $line1, array $widths, array &$result)
+    {
+        for ($i = $line0; $i < $line1; ++$i) {
+            list($cells, $trailing) = $lines[$i];
+            $s = '';
+            $useTabs = true;
+            foreach ($cells as $j => $cell) {
+                list($text) = $cell;
+                if ($text === '') {
+                    if ($j < count($widths)) {
+                        if ($useTabs) {
+                            // Leading empty cells are indentation.
+                            $s .= str_repeat("\t", (int)ceil($widths[$j] / 8));
+                        } else {
+                            $s .= str_repeat(' ', $widths[$j]);
+                        }
+                    }
+                } else {
+                    $useTabs = false;
+                    $s .= $text;
+                    if ($j < count($widths)) {
+                        $s .= str_repeat(' ', $widths[$j] - self::width($text));
+                    }
+                }
+            }
+
+            if (empty($cells)) {
+                $result[] = $trailing;
+            } else {
+                $result[] = rtrim($s . $trailing);
+            }
+        }
+    }
+
+    /**
+     * Returns width of text in runes.
+     *
+     * @param string $text
+     * @return int
+     */
+    private static function width($text)
+    {
+        return (int)preg_match_all('/./us', $text);
+    }
+}
\ No newline at end of file
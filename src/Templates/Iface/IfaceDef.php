@@ -5,7 +5,8 @@ namespace Swaggest\GoCodeBuilder\Templates\Iface;
 use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
 use Swaggest\GoCodeBuilder\Templates\Func\FuncIface;
 use Swaggest\GoCodeBuilder\Templates\GoTemplate;
-use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\AnyType;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeParams;
 
 class IfaceDef extends GoTemplate
 {
@@ -20,9 +21,60 @@ class IfaceDef extends GoTemplate
     /** @var FuncDef[] */
     private $funcs;
 
-    /** @var Type[] */
+    /** @var AnyType[] */
     private $types;
 
+    /** @var TypeParams|null */
+    private $typeParams;
+
+    /**
+     * @return TypeParams|null
+     */
+    public function getTypeParams()
+    {
+        return $this->typeParams;
+    }
+
+    /**
+     * @param TypeParams|null $typeParams
+     * @return IfaceDef
+     */
+    public function setTypeParams(TypeParams $typeParams = null)
+    {
+        $this->typeParams = $typeParams;
+        return $this;
+    }
+
+    /**
+     * @return string
+     */
+    public function getName()
+    {
+        return $this->name;
+    }
+
+    /**
+     * @return FuncDef[]
+     */
+    public function getFuncs()
+    {
+        if ($this->funcs === null) {
+            return array();
+        }
+        return $this->funcs;
+    }
+
+    /**
+     * @return AnyType[]
+     */
+    public function getTypes()
+    {
+        if ($this->types === null) {
+            return array();
+        }
+        return $this->types;
+    }
+
     public function addFunc(FuncDef $func, $prepend = false)
     {
         if ($prepend) {
@@ -33,7 +85,7 @@ class IfaceDef extends GoTemplate
         return $this;
     }
 
-    public function addType(Type $func, $prepend = false)
+    public function addType(AnyType $func, $prepend = false)
     {
         if ($prepend) {
             array_unshift($this->types, $func);
@@ -71,7 +123,7 @@ class IfaceDef extends GoTemplate
     {
         $separator = !empty($this->types) && !empty($this->funcs) ? "\n" : '';
         $result = <<<GO
-{$this->renderComment()}type {$this->name} interface {
+{$this->renderComment()}type {$this->name}{$this->typeParams} interface {
 {$this->renderTypes()}{$separator}{$this->renderFuncs()}}
 
 
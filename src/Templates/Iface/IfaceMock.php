@@ -0,0 +1,316 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Iface;
+
+use Swaggest\GoCodeBuilder\Exception;
+use Swaggest\GoCodeBuilder\GoCodeBuilder;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\GoFmt;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+/**
+ * IfaceMock renders mock implementation of interface that records calls and delegates to function fields.
+ */
+class IfaceMock extends GoTemplate
+{
+    /** @var IfaceDef */
+    private $iface;
+
+    /** @var string */
+    private $name;
+
+    /** @var bool */
+    private $panicOnNil = true;
+
+    /** @var GoCodeBuilder */
+    private static $codeBuilder;
+
+    /**
+     * IfaceMock constructor.
+     * @param IfaceDef $iface
+     * @param string|null $name mock type name, defaults to interface name with Mock suffix
+     * @throws Exception if interface embeds types, their methods are unknown
+     */
+    public function __construct(IfaceDef $iface, $name = null)
+    {
+        self::checkEmbedded($iface);
+        $this->iface = $iface;
+        if ($name === null) {
+            $name = $iface->getName() . 'Mock';
+        }
+        $this->name = $name;
+    }
+
+    /**
+     * @return string
+     */
+    public function getName()
+    {
+        return $this->name;
+    }
+
+    /**
+     * Panic when method without function is called, otherwise zero values are returned.
+     *
+     * @param bool $panicOnNil
+     * @return IfaceMock
+     */
+    public function setPanicOnNil($panicOnNil)
+    {
+        $this->panicOnNil = $panicOnNil;
+        return $this;
+    }
+
+    /**
+     * Fails for interfaces with embedded types as methods of embedded interfaces can not be implemented.
+     *
+     * @param IfaceDef $iface
+     * @throws Exception
+     */
+    public static function checkEmbedded(IfaceDef $iface)
+    {
+        $types = $iface->getTypes();
+        if (!empty($types)) {
+            throw new Exception('Interface ' . $iface->getName() . ' embeds ' . $types[0]->render()
+                . ', add methods of embedded interfaces with addFunc instead');
+        }
+    }
+
+    protected function toString()
+    {
+        $methods = array();
+        foreach ($this->iface->getFuncs() as $func) {
+            $methods[] = self::method($func);
+        }
+
+        $ifaceName = $this->iface->getName();
+        $typeParams = '';
+        $mockType = $this->name;
+        if ($this->iface->getTypeParams() !== null && !$this->iface->getTypeParams()->isEmpty()) {
+            $typeParams = $this->iface->getTypeParams()->render();
+            $mockType .= $this->iface->getTypeParams()->renderNames();
+        }
+
+        $result = '';
+        if ($typeParams === '') {
+            $result .= <<<GO
+// Ensure that {$this->name} does implement {$ifaceName}.
+var _ {$ifaceName} = &{$this->name}{}
+
+
+GO;
+        }
+
+        $fields = '';
+        $calls = '';
+        $locks = '';
+        $funcs = '';
+        foreach ($methods as $m) {
+            $fields .= <<<GO
+	// {$m['name']}Func mocks the {$m['name']} method.
+	{$m['name']}Func func({$m['params']}){$m['results']}
+
+
+GO;
+            $calls .= <<<GO
+
+		// {$m['name']} holds details about calls to the {$m['name']} method.
+		{$m['name']} []{$this->callStruct($m['args'])}
+
+GO;
+            $locks .= <<<GO
+	lock{$m['name']} sync.RWMutex
+
+GO;
+            $funcs .= $this->renderMethod($m, $mockType, $ifaceName) . $this->renderCalls($m, $mockType);
+        }
+
+        if ($calls !== '') {
+            $calls = ltrim($calls, "\n");
+            $fields .= <<<GO
+	// calls tracks calls to the methods.
+	calls struct {
+{$calls}	}
+{$locks}
+GO;
+        }
+
+        $result .= <<<GO
+// {$this->name} is a mock implementation of {$ifaceName}.
+type {$this->name}{$typeParams} struct {
+{$fields}}
+
+{$funcs}
+GO;
+
+        $code = new Code(GoFmt::format($result) . "\n");
+        if (!empty($methods)) {
+            $code->imports()->addByName('sync');
+        }
+
+        return $code;
+    }
+
+    private function renderMethod(array $m, $mockType, $ifaceName)
+    {
+        $name = $m['name'];
+
+        $panic = '';
+        if ($this->panicOnNil) {
+            $panic = <<<GO
+	if mock.{$name}Func == nil {
+		panic("{$this->name}.{$name}Func: method is nil but {$ifaceName}.{$name} was just called")
+	}
+
+GO;
+        }
+
+        $callInfo = 'struct{}{}';
+        if (!empty($m['args'])) {
+            $values = '';
+            foreach ($m['args'] as $arg) {
+                $values .= "\t\t{$arg['field']}: {$arg['name']},\n";
+            }
+            $callInfo = $this->callStruct($m['args']) . "{\n" . $values . "\t}";
+        }
+
+        $zero = '';
+        if (!$this->panicOnNil) {
+            $zeroReturn = self::zeroReturn($m['resultTypes'], "\t\t");
+            $zero = <<<GO
+	if mock.{$name}Func == nil {
+{$zeroReturn}	}
+
+GO;
+        }
+
+        $return = empty($m['resultTypes']) ? '' : 'return ';
+
+        return <<<GO
+// {$name} calls {$name}Func.
+func (mock *{$mockType}) {$name}({$m['params']}){$m['results']} {
+{$panic}	callInfo := {$callInfo}
+	mock.lock{$name}.Lock()
+	mock.calls.{$name} = append(mock.calls.{$name}, callInfo)
+	mock.lock{$name}.Unlock()
+{$zero}	{$return}mock.{$name}Func({$m['callArgs']})
+}
+
+
+GO;
+    }
+
+    private function renderCalls(array $m, $mockType)
+    {
+        $name = $m['name'];
+
+        return <<<GO
+// {$name}Calls gets all the calls that were made to {$name}.
+func (mock *{$mockType}) {$name}Calls() []{$this->callStruct($m['args'])} {
+	var calls []{$this->callStruct($m['args'])}
+	mock.lock{$name}.RLock()
+	calls = mock.calls.{$name}
+	mock.lock{$name}.RUnlock()
+	return calls
+}
+
+
+GO;
+    }
+
+    /**
+     * Collects rendered parts of method signature.
+     *
+     * @param FuncDef $func
+     * @return array
+     */
+    public static function method(FuncDef $func)
+    {
+        if (self::$codeBuilder === null) {
+            self::$codeBuilder = new GoCodeBuilder();
+        }
+
+        $args = array();
+        $params = array();
+        $callArgs = array();
+        if ($func->getArguments() !== null) {
+            foreach ($func->getArguments()->getItems() as $i => $argument) {
+                $name = $argument->name !== null ? $argument->name : 'in' . ($i + 1);
+                $type = $argument->type->render();
+                $params[] = $name . ' ' . ($argument->isVariadic ? '...' : '') . $type;
+                $callArgs[] = $name . ($argument->isVariadic ? '...' : '');
+                $args[] = array(
+                    'name' => $name,
+                    'field' => self::$codeBuilder->exportableName($name),
+                    'type' => ($argument->isVariadic ? '[]' : '') . $type,
+                );
+            }
+        }
+
+        $resultTypes = array();
+        if ($func->getResult() !== null) {
+            foreach ($func->getResult()->getItems() as $argument) {
+                $resultTypes[] = $argument->type->render();
+            }
+        }
+        $results = (string)$func->getResult();
+
+        return array(
+            'name' => $func->getName(),
+            'args' => $args,
+            'params' => implode(', ', $params),
+            'callArgs' => implode(', ', $callArgs),
+            'results' => $results !== '' ? ' ' . $results : '',
+            'resultTypes' => $resultTypes,
+        );
+    }
+
+    /**
+     * Renders zero values return.
+     *
+     * @param string[] $resultTypes
+     * @param string $indent
+     * @param string|null $errorMessage message of returned error for error results
+     * @return string
+     */
+    public static function zeroReturn(array $resultTypes, $indent, $errorMessage = null)
+    {
+        if (empty($resultTypes)) {
+            return $indent . "return\n";
+        }
+
+        $result = '';
+        $values = array();
+        foreach ($resultTypes as $i => $type) {
+            if ($errorMessage !== null && $type === 'error') {
+                $values[] = 'errors.New(' . json_encode($errorMessage) . ')';
+                continue;
+            }
+            $result .= $indent . 'var r' . $i . ' ' . $type . "\n";
+            $values[] = 'r' . $i;
+        }
+
+        return $result . $indent . 'return ' . implode(', ', $values) . "\n";
+    }
+
+    /**
+     * Renders anonymous structure with argument fields, fields are aligned by GoFmt.
+     *
+     * @param array $args
+     * @return string
+     */
+    private function callStruct(array $args)
+    {
+        if (empty($args)) {
+            return 'struct{}';
+        }
+
+        $fields = '';
+        foreach ($args as $arg) {
+            $fields .= "{$arg['field']} {$arg['type']}\n";
+        }
+
+        return "struct {\n" . $fields . '}';
+    }
+}
@@ -0,0 +1,85 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Iface;
+
+use Swaggest\GoCodeBuilder\Exception;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+/**
+ * IfaceUnimplemented renders embeddable structure with no-op implementation of interface methods.
+ */
+class IfaceUnimplemented extends GoTemplate
+{
+    /** @var IfaceDef */
+    private $iface;
+
+    /** @var string */
+    private $name;
+
+    /**
+     * IfaceUnimplemented constructor.
+     * @param IfaceDef $iface
+     * @param string|null $name structure name, defaults to interface name with Unimplemented prefix
+     * @throws Exception if interface embeds types, their methods are unknown
+     */
+    public function __construct(IfaceDef $iface, $name = null)
+    {
+        IfaceMock::checkEmbedded($iface);
+        $this->iface = $iface;
+        if ($name === null) {
+            $name = 'Unimplemented' . $iface->getName();
+        }
+        $this->name = $name;
+    }
+
+    /**
+     * @return string
+     */
+    public function getName()
+    {
+        return $this->name;
+    }
+
+    protected function toString()
+    {
+        $ifaceName = $this->iface->getName();
+        $typeParams = '';
+        $receiverType = $this->name;
+        if ($this->iface->getTypeParams() !== null && !$this->iface->getTypeParams()->isEmpty()) {
+            $typeParams = $this->iface->getTypeParams()->render();
+            $receiverType .= $this->iface->getTypeParams()->renderNames();
+        }
+
+        $result = <<<GO
+// {$this->name} can be embedded to have forward compatible implementation of {$ifaceName}.
+type {$this->name}{$typeParams} struct{}
+
+
+GO;
+
+        $code = new Code();
+        foreach ($this->iface->getFuncs() as $func) {
+            $m = IfaceMock::method($func);
+            $return = '';
+            if (!empty($m['resultTypes'])) {
+                $return = IfaceMock::zeroReturn($m['resultTypes'], "\t",
+                    $ifaceName . '.' . $m['name'] . ' is not implemented');
+            }
+            $result .= <<<GO
+// {$m['name']} implements {$ifaceName}.
+func ({$receiverType}) {$m['name']}({$m['params']}){$m['results']} {
+{$return}}
+
+
+GO;
+            if (in_array('error', $m['resultTypes'], true)) {
+                $code->imports()->addByName('errors');
+            }
+        }
+
+        $code->addSnippet($result);
+
+        return $code;
+    }
+}
\ No newline at end of file
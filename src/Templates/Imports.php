@@ -11,6 +11,24 @@ class Imports extends GoTemplate
      */
     public $imports = array();
 
+    /**
+     * Imports that are used in code literally, their identifiers can not be changed.
+     * @var bool[]
+     */
+    private $literal = array();
+
+    /**
+     * Imports that are used with type references.
+     * @var bool[]
+     */
+    private $referenced = array();
+
+    /**
+     * Resolved identifiers by import path.
+     * @var string[]
+     */
+    private $aliases = array();
+
     /**
      * @param Import $import
      * @return $this
@@ -18,9 +36,124 @@ class Imports extends GoTemplate
     public function add(Import $import)
     {
         $this->imports[$import->name] = $import;
+        $this->literal[$import->name] = true;
         return $this;
     }
 
+    /**
+     * Adds import of referenced type and returns prefix to refer to package symbols.
+     *
+     * @param Import $import
+     * @return string
+     */
+    public function reference(Import $import)
+    {
+        if (!isset($this->imports[$import->name])) {
+            $this->imports[$import->name] = $import;
+        }
+        $this->referenced[$import->name] = true;
+
+        if (isset($this->aliases[$import->name])) {
+            return $this->aliases[$import->name] . '.';
+        }
+
+        return $import->getReferencePrefix();
+    }
+
+    /**
+     * Assigns deterministic aliases to referenced imports with conflicting identifiers.
+     *
+     * @param string[] $reserved identifiers of local symbols
+     * @return bool true if any alias was changed
+     */
+    public function resolveAliases(array $reserved)
+    {
+        $taken = array();
+        foreach ($reserved as $identifier) {
+            $taken[$identifier] = true;
+        }
+
+        $paths = array_keys($this->imports);
+        sort($paths);
+
+        $candidates = array();
+        foreach ($paths as $path) {
+            $import = $this->imports[$path];
+            if ($import->alias === '_' || $import->alias === '.') {
+                continue;
+            }
+
+            if ($import->alias || isset($this->literal[$path]) || !isset($this->referenced[$path])) {
+                $taken[$this->identifier($import)] = true;
+            } else {
+                $candidates[] = $import;
+            }
+        }
+
+        $aliases = array();
+        foreach ($candidates as $import) {
+            $identifier = $this->identifier($import);
+
+            if (isset($taken[$identifier])) {
+                $identifier = $this->makeAlias($import, $taken);
+                $aliases[$import->name] = $identifier;
+            }
+
+            $taken[$identifier] = true;
+        }
+
+        $changed = $aliases !== $this->aliases;
+        $this->aliases = $aliases;
+
+        return $changed;
+    }
+
+    /**
+     * @param Import $import
+     * @return string
+     */
+    private function identifier(Import $import)
+    {
+        if ($import->alias) {
+            return $import->alias;
+        }
+
+        return $import->getPackage();
+    }
+
+    /**
+     * Makes alias from package name prefixed with parent path segments, e.g. "b.com/orders" becomes "bcomorders".
+     *
+     * @param Import $import
+     * @param bool[] $taken
+     * @return string
+     */
+    private function makeAlias(Import $import, array $taken)
+    {
+        $package = preg_replace('/[^a-z0-9_]/', '', strtolower($import->getPackage()));
+        $segments = explode('/', $import->name);
+        array_pop($segments);
+
+        $alias = $package;
+        while (!empty($segments)) {
+            $alias = preg_replace('/[^a-z0-9]/', '', strtolower(array_pop($segments))) . $alias;
+            if (!isset($taken[$alias]) && preg_match('/^[a-z_]/', $alias)) {
+                return $alias;
+            }
+        }
+
+        if (!preg_match('/^[a-z_]/', $alias)) {
+            $alias = 'pkg' . $alias;
+        }
+
+        $i = 2;
+        while (isset($taken[$alias . $i])) {
+            ++$i;
+        }
+
+        return $alias . $i;
+    }
+
     public function addByName($name, $alias = null)
     {
         $this->add(new Import($name, $alias));
@@ -57,14 +190,14 @@ class Imports extends GoTemplate
         asort($external);
 
         foreach ($builtin as $import) {
-            $result .= "\t" . ($import->alias ? $import->alias . ' ' : '') . '"' . $import->name . '"' . "\n";
+            $result .= $this->renderImport($import);
         }
 
         if ($result && $external) {
             $result .= "\n";
         }
         foreach ($external as $import) {
-            $result .= "\t" . ($import->alias ? $import->alias . ' ' : '') . '"' . $import->name . '"' . "\n";
+            $result .= $this->renderImport($import);
         }
 
         if ($result) {
@@ -76,4 +209,14 @@ GO;
 
         return $result;
     }
+
+    private function renderImport(Import $import)
+    {
+        $alias = $import->alias;
+        if (isset($this->aliases[$import->name])) {
+            $alias = $this->aliases[$import->name];
+        }
+
+        return "\t" . ($alias ? $alias . ' ' : '') . '"' . $import->name . '"' . "\n";
+    }
 }
\ No newline at end of file
@@ -0,0 +1,231 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates;
+
+use Swaggest\GoCodeBuilder\Import;
+
+/**
+ * ProtectedRegion renders marker comments around hand-written code that is kept across regeneration.
+ */
+class ProtectedRegion extends GoTemplate
+{
+    const BEGIN = '// protected region %s begin';
+    const END = '// protected region %s end';
+
+    const MARKER_REGEX = '/^([ \t]*)\/\/ protected region (\S+) begin[ \t]*\n(.*?)^[ \t]*\/\/ protected region \2 end[ \t]*$/ms';
+
+    private $name;
+    private $defaultContent;
+
+    /**
+     * ProtectedRegion constructor.
+     * @param string $name unique name of region within file
+     * @param string $defaultContent content to render when there is no preserved one
+     */
+    public function __construct($name, $defaultContent = '')
+    {
+        $this->name = $name;
+        $this->defaultContent = $defaultContent;
+    }
+
+    /**
+     * @return string
+     */
+    public function getName()
+    {
+        return $this->name;
+    }
+
+    protected function toString()
+    {
+        $content = $this->defaultContent;
+        if ($goFile = GoFile::getCurrentGoFile()) {
+            $preserved = $goFile->getPreservedRegion($this->name);
+            if ($preserved !== null) {
+                $content = $preserved;
+            }
+        }
+
+        return self::wrap($this->name, $content, '');
+    }
+
+    /**
+     * @param string $name
+     * @param string $content
+     * @param string $indent
+     * @return string
+     */
+    private static function wrap($name, $content, $indent)
+    {
+        $result = $indent . sprintf(self::BEGIN, $name) . "\n";
+        $content = rtrim($content, "\n");
+        if ($content !== '') {
+            foreach (explode("\n", $content) as $line) {
+                $result .= ($line === '' ? '' : $indent . $line) . "\n";
+            }
+        }
+        $result .= $indent . sprintf(self::END, $name);
+
+        return $result;
+    }
+
+    /**
+     * Reads contents of protected regions from previously generated code.
+     *
+     * Contents are unindented by the indentation of begin marker.
+     *
+     * @param string $code
+     * @return string[] contents by region name
+     */
+    public static function parse($code)
+    {
+        $regions = array();
+        if (!preg_match_all(self::MARKER_REGEX, $code, $matches, PREG_SET_ORDER)) {
+            return $regions;
+        }
+
+        foreach ($matches as $match) {
+            $indent = $match[1];
+            $lines = explode("\n", rtrim($match[3], "\n"));
+            foreach ($lines as &$line) {
+                if ($indent !== '' && strpos($line, $indent) === 0) {
+                    $line = substr($line, strlen($indent));
+                }
+            }
+            unset($line);
+            $regions[$match[2]] = implode("\n", $lines);
+        }
+
+        return $regions;
+    }
+
+    /**
+     * Removes contents of protected regions, markers are kept.
+     *
+     * @param string $code
+     * @return string
+     */
+    public static function strip($code)
+    {
+        return preg_replace_callback(self::MARKER_REGEX, function ($match) {
+            return self::wrap($match[2], '', $match[1]);
+        }, $code);
+    }
+
+    /**
+     * Reads import declarations of code.
+     *
+     * @param string $code
+     * @return Import[]
+     */
+    public static function parseImports($code)
+    {
+        $specs = array();
+        if (preg_match('/^import \(\n(.*?)^\)/ms', $code, $match)) {
+            $specs = explode("\n", $match[1]);
+        } elseif (preg_match_all('/^import (.+)$/m', $code, $matches)) {
+            $specs = $matches[1];
+        }
+
+        $imports = array();
+        foreach ($specs as $spec) {
+            if (preg_match('/^\s*(?:([A-Za-z_]\w*|\.)\s+)?"([^"]+)"/', $spec, $match)) {
+                $imports[] = new Import($match[2], $match[1] !== '' ? $match[1] : null);
+            }
+        }
+
+        return $imports;
+    }
+
+    /**
+     * Finds imports of previously generated code that are used in contents of protected regions.
+     *
+     * @param string $code
+     * @return Import[]
+     */
+    public static function usedImports($code)
+    {
+        $regions = self::parse($code);
+        if (empty($regions)) {
+            return array();
+        }
+
+        $used = self::references(implode("\n", $regions));
+
+        $imports = array();
+        foreach (self::parseImports($code) as $import) {
+            if ($import->alias === '_' || $import->alias === '.') {
+                continue;
+            }
+            $identifier = $import->alias ? $import->alias : $import->getPackage();
+            if (isset($used[$identifier])) {
+                $imports[] = $import;
+            }
+        }
+
+        return $imports;
+    }
+
+    /**
+     * Finds identifiers that are used as package qualifiers in code.
+     *
+     * @param string $code
+     * @return bool[] map of identifiers
+     */
+    public static function references($code)
+    {
+        // String literals and comments are not references to packages.
+        $code = preg_replace('/"(?:[^"\\\\\n]|\\\\.)*"|`[^`]*`|\/\/[^\n]*/', '', $code);
+        preg_match_all('/(?<![\w.])([A-Za-z_]\w*)\./', $code, $matches);
+
+        return array_fill_keys($matches[1], true);
+    }
+
+    /**
+     * Re-inserts contents of protected regions from previous code into freshly generated code.
+     *
+     * Imports used in preserved contents are merged into import declarations of generated code.
+     *
+     * @param string $code freshly generated code
+     * @param string $previousCode code that was on disk before regeneration
+     * @return string
+     */
+    public static function restore($code, $previousCode)
+    {
+        $regions = self::parse($previousCode);
+        if (empty($regions)) {
+            return $code;
+        }
+
+        $result = preg_replace_callback(self::MARKER_REGEX, function ($match) use ($regions) {
+            if (!isset($regions[$match[2]])) {
+                return $match[0];
+            }
+            return self::wrap($match[2], $regions[$match[2]], $match[1]);
+        }, $code);
+
+        $imports = new Imports();
+        foreach (self::parseImports($result) as $import) {
+            $imports->add($import);
+        }
+        $count = count($imports->imports);
+        foreach (self::usedImports($previousCode) as $import) {
+            if (!isset($imports->imports[$import->name])) {
+                $imports->add($import);
+            }
+        }
+        if (count($imports->imports) === $count) {
+            return $result;
+        }
+
+        $rendered = (string)$imports;
+        if (preg_match('/^import \(\n.*?^\)/ms', $result)) {
+            return preg_replace('/^import \(\n.*?^\)/ms', addcslashes($rendered, '\\$'), $result, 1);
+        }
+        if (preg_match('/^import .+$/m', $result)) {
+            $result = preg_replace('/^import .+\n/m', '', $result);
+        }
+
+        return preg_replace('/^(package [^\n]*\n)\n*/m', '$1' . "\n" . addcslashes($rendered, '\\$') . "\n\n", $result, 1);
+    }
+}
@@ -30,6 +30,10 @@ class FluentSetter
     public static function makeEnsurer(StructDef $structDef, StructProperty $goProperty) {
         $type = $goProperty->getType();
 
+        if ($type instanceof StructType && self::isOmitZero($goProperty)) {
+            return self::makeValueEnsurer($structDef, $goProperty);
+        }
+
         if (!$type instanceof Pointer || !$type->getType() instanceof StructType) {
             return null;
         }
@@ -56,6 +60,47 @@ GO
         return $ensurer;
     }
 
+    /**
+     * Optional structure stored by value with `omitzero` keeps ensurer to return pointer to the field.
+     *
+     * @param StructDef $structDef
+     * @param StructProperty $goProperty
+     * @return FuncDef
+     */
+    private static function makeValueEnsurer(StructDef $structDef, StructProperty $goProperty)
+    {
+        $receiver = strtolower($structDef->getType()->getName()[0]);
+
+        $ensurer = new FuncDef(
+            $goProperty->getName() . 'Ens',
+            $goProperty->getName() . 'Ens returns pointer to ' . $goProperty->getName() . ' value.'
+        );
+        $ensurer->setSelf(new Argument($receiver, new Pointer($structDef->getType())));
+
+        $ensurer->setResult((new Result())->add(null, new Pointer($goProperty->getType())));
+
+        $ensurer->setBody(new Code(<<<GO
+return &{$receiver}.{$goProperty->getName()}
+GO
+        ));
+
+        return $ensurer;
+    }
+
+    /**
+     * @param StructProperty $goProperty
+     * @return bool
+     */
+    private static function isOmitZero(StructProperty $goProperty)
+    {
+        $json = $goProperty->getTags()->getTag('json');
+        if ($json === null) {
+            return false;
+        }
+
+        return in_array('omitzero', explode(',', $json), true);
+    }
+
     public static function make(StructDef $structDef, StructProperty $goProperty)
     {
         $receiver = strtolower($structDef->getType()->getName()[0]);
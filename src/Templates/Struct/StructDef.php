@@ -8,6 +8,7 @@ use Swaggest\GoCodeBuilder\Templates\Func;
 use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
 use Swaggest\GoCodeBuilder\Templates\GoTemplate;
 use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeParams;
 
 class StructDef extends GoTemplate
 {
@@ -24,6 +25,9 @@ class StructDef extends GoTemplate
     /** @var Import|null */
     private $import;
 
+    /** @var TypeParams|null */
+    private $typeParams;
+
     /**
      * StructDef constructor.
      * @param string $name
@@ -89,6 +93,24 @@ class StructDef extends GoTemplate
         return $this;
     }
 
+    /**
+     * @return TypeParams|null
+     */
+    public function getTypeParams()
+    {
+        return $this->typeParams;
+    }
+
+    /**
+     * @param TypeParams|null $typeParams
+     * @return StructDef
+     */
+    public function setTypeParams(TypeParams $typeParams = null)
+    {
+        $this->typeParams = $typeParams;
+        return $this;
+    }
+
     /**
      * @return StructProperty[]
      */
@@ -22,7 +22,7 @@ class StructFields extends GoTemplate
     protected function toString()
     {
         return <<<GO
-type {$this->struct->getName()} struct {
+type {$this->struct->getName()}{$this->struct->getTypeParams()} struct {
 {$this->renderProperties()}
 }
 
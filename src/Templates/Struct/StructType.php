@@ -32,14 +32,27 @@ class StructType extends GoTemplate implements NamedType
         return new Type($this->structDef->getName(), $this->structDef->getImport());
     }
 
+    /**
+     * Renders type parameters of generic struct to refer to it in methods, e.g. Page[T].
+     *
+     * @return string
+     */
+    private function renderTypeParams()
+    {
+        if ($typeParams = $this->structDef->getTypeParams()) {
+            return $typeParams->renderNames();
+        }
+        return '';
+    }
+
     protected function toString()
     {
-        return $this->getType()->toString();
+        return $this->getType()->toString() . $this->renderTypeParams();
     }
 
     public function getTypeString()
     {
-        return $this->getType()->getTypeString();
+        return $this->getType()->getTypeString() . $this->renderTypeParams();
     }
 
 }
\ No newline at end of file
@@ -14,6 +14,24 @@ class Tags extends GoTemplate
         return $this;
     }
 
+    public function unsetTag($key)
+    {
+        unset($this->items[$key]);
+        return $this;
+    }
+
+    /**
+     * @param string $key
+     * @return string|null
+     */
+    public function getTag($key)
+    {
+        if (isset($this->items[$key])) {
+            return $this->items[$key];
+        }
+        return null;
+    }
+
     protected function toString()
     {
         $result = '';
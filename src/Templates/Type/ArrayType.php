@@ -0,0 +1,51 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Type;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+class ArrayType extends GoTemplate implements AnyType
+{
+    /** @var string */
+    private $length;
+
+    /** @var AnyType */
+    private $type;
+
+    /**
+     * ArrayType constructor.
+     * @param int|string $length number of elements or name of constant
+     * @param AnyType $type
+     */
+    public function __construct($length, AnyType $type)
+    {
+        $this->length = (string)$length;
+        $this->type = $type;
+    }
+
+    protected function toString()
+    {
+        return '[' . $this->length . ']' . $this->type->render();
+    }
+
+    /**
+     * @return string
+     */
+    public function getLength()
+    {
+        return $this->length;
+    }
+
+    /**
+     * @return AnyType
+     */
+    public function getType()
+    {
+        return $this->type;
+    }
+
+    public function getTypeString()
+    {
+        return '[' . $this->length . ']' . $this->type->getTypeString();
+    }
+}
\ No newline at end of file
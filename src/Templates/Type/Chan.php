@@ -0,0 +1,71 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Type;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+class Chan extends GoTemplate implements AnyType
+{
+    const BOTH = 'chan';
+    const RECEIVE = '<-chan';
+    const SEND = 'chan<-';
+
+    /** @var AnyType */
+    private $type;
+
+    /** @var string */
+    private $direction;
+
+    /**
+     * Chan constructor.
+     * @param AnyType $type
+     * @param string $direction
+     */
+    public function __construct(AnyType $type, $direction = self::BOTH)
+    {
+        $this->type = $type;
+        $this->direction = $direction;
+    }
+
+    protected function toString()
+    {
+        return $this->direction . ' ' . $this->wrap($this->type->render());
+    }
+
+    /**
+     * @return AnyType
+     */
+    public function getType()
+    {
+        return $this->type;
+    }
+
+    /**
+     * @return string
+     */
+    public function getDirection()
+    {
+        return $this->direction;
+    }
+
+    public function getTypeString()
+    {
+        return $this->direction . ' ' . $this->wrap($this->type->getTypeString());
+    }
+
+    /**
+     * Element of bidirectional channel needs parentheses if it is a receive-only channel,
+     * "chan <-chan int" would be read as "chan<- chan int".
+     *
+     * @param string $element
+     * @return string
+     */
+    private function wrap($element)
+    {
+        if ($this->direction === self::BOTH && $this->type instanceof Chan
+            && $this->type->direction === self::RECEIVE) {
+            return '(' . $element . ')';
+        }
+        return $element;
+    }
+}
\ No newline at end of file
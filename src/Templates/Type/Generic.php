@@ -0,0 +1,67 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Type;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+/**
+ * Generic is an instantiation of generic type with type arguments, e.g. Page[T] or Result[Order, error].
+ */
+class Generic extends GoTemplate implements NamedType
+{
+    /** @var NamedType */
+    private $base;
+
+    /** @var AnyType[] */
+    private $arguments;
+
+    /**
+     * Generic constructor.
+     * @param NamedType $base
+     * @param AnyType[] $arguments
+     */
+    public function __construct(NamedType $base, array $arguments)
+    {
+        $this->base = $base;
+        $this->arguments = $arguments;
+    }
+
+    public function getName()
+    {
+        return $this->base->getName();
+    }
+
+    /**
+     * @return NamedType
+     */
+    public function getBase()
+    {
+        return $this->base;
+    }
+
+    /**
+     * @return AnyType[]
+     */
+    public function getArguments()
+    {
+        return $this->arguments;
+    }
+
+    protected function toString()
+    {
+        $arguments = array();
+        foreach ($this->arguments as $argument) {
+            $arguments[] = $argument->render();
+        }
+        return $this->base->render() . '[' . implode(', ', $arguments) . ']';
+    }
+
+    public function getTypeString()
+    {
+        $arguments = array();
+        foreach ($this->arguments as $argument) {
+            $arguments[] = $argument->getTypeString();
+        }
+        return $this->base->getTypeString() . '[' . implode(', ', $arguments) . ']';
+    }
+}
\ No newline at end of file
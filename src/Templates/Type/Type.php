@@ -39,8 +39,7 @@ class Type extends GoTemplate implements NamedType
         if ($this->import) {
             if ($goFile = GoFile::getCurrentGoFile()) {
                 if ($goFile->getImportPath() !== $this->import->name) {
-                    $goFile->getImports()->add($this->import);
-                    $prefix = $this->import->getReferencePrefix();
+                    $prefix = $goFile->getImports()->reference($this->import);
                 }
             }
         }
@@ -0,0 +1,57 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Type;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+class TypeParam extends GoTemplate
+{
+    /** @var string */
+    private $name;
+
+    /** @var AnyType */
+    private $constraint;
+
+    /**
+     * TypeParam constructor.
+     * @param string $name
+     * @param AnyType|null $constraint defaults to any
+     */
+    public function __construct($name, AnyType $constraint = null)
+    {
+        $this->name = $name;
+        if ($constraint === null) {
+            $constraint = new Type('any');
+        }
+        $this->constraint = $constraint;
+    }
+
+    /**
+     * @return string
+     */
+    public function getName()
+    {
+        return $this->name;
+    }
+
+    /**
+     * @return AnyType
+     */
+    public function getConstraint()
+    {
+        return $this->constraint;
+    }
+
+    /**
+     * @return Type type to refer to parameter
+     */
+    public function getType()
+    {
+        return new Type($this->name);
+    }
+
+    protected function toString()
+    {
+        return $this->name . ' ' . $this->constraint->render();
+    }
+}
\ No newline at end of file
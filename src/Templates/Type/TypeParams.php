@@ -0,0 +1,63 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Type;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+class TypeParams extends GoTemplate
+{
+    /** @var TypeParam[] */
+    private $params = array();
+
+    /**
+     * @param TypeParam $param
+     * @return $this
+     */
+    public function add(TypeParam $param)
+    {
+        $this->params[$param->getName()] = $param;
+        return $this;
+    }
+
+    /**
+     * @return TypeParam[]
+     */
+    public function getParams()
+    {
+        return $this->params;
+    }
+
+    /**
+     * @return bool
+     */
+    public function isEmpty()
+    {
+        return empty($this->params);
+    }
+
+    /**
+     * Renders parameter names to instantiate type with its own parameters, e.g. [K, V].
+     *
+     * @return string
+     */
+    public function renderNames()
+    {
+        if (empty($this->params)) {
+            return '';
+        }
+        return '[' . implode(', ', array_keys($this->params)) . ']';
+    }
+
+    protected function toString()
+    {
+        if (empty($this->params)) {
+            return '';
+        }
+
+        $params = array();
+        foreach ($this->params as $param) {
+            $params[] = $param->render();
+        }
+        return '[' . implode(', ', $params) . ']';
+    }
+}
\ No newline at end of file
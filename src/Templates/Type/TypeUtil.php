@@ -12,19 +12,69 @@ class TypeUtil
      */
     public static function fromString($typeString)
     {
+        $typeString = trim($typeString);
+
+        $terms = self::splitTopLevel($typeString, '|');
+        if (count($terms) > 1 || '~' === $typeString[0]) {
+            $union = new Union();
+            foreach ($terms as $term) {
+                if ('~' === $term[0]) {
+                    $union->addTerm(self::fromString(substr($term, 1)), true);
+                } else {
+                    $union->addTerm(self::fromString($term));
+                }
+            }
+            return $union;
+        }
+
+        if ('(' === $typeString[0] && self::closingBracket($typeString, 0) === strlen($typeString) - 1) {
+            return self::fromString(substr($typeString, 1, -1));
+        }
+
         if ('*' === $typeString[0]) {
             return new Pointer(self::fromString(substr($typeString, 1)));
         }
 
         if ('map[' === substr($typeString, 0, 4)) {
-            list($key, $val) = explode(']', substr($typeString, 4), 2);
-            return new Map(self::fromString($key), self::fromString($val));
+            $close = self::closingBracket($typeString, 3);
+            return new Map(self::fromString(substr($typeString, 4, $close - 4)),
+                self::fromString(substr($typeString, $close + 1)));
         }
 
         if ('[]' === substr($typeString, 0, 2)) {
             return new Slice(self::fromString(substr($typeString, 2)));
         }
 
+        if ('[' === $typeString[0]) {
+            $close = self::closingBracket($typeString, 0);
+            return new ArrayType(trim(substr($typeString, 1, $close - 1)),
+                self::fromString(substr($typeString, $close + 1)));
+        }
+
+        if ('<-chan ' === substr($typeString, 0, 7)) {
+            return new Chan(self::fromString(substr($typeString, 7)), Chan::RECEIVE);
+        }
+
+        if ('chan<-' === substr($typeString, 0, 6)) {
+            return new Chan(self::fromString(substr($typeString, 6)), Chan::SEND);
+        }
+
+        if ('chan ' === substr($typeString, 0, 5)) {
+            return new Chan(self::fromString(substr($typeString, 5)));
+        }
+
+        $open = strpos($typeString, '[');
+        if ($open !== false && ']' === substr($typeString, -1)) {
+            // generic instantiation, e.g. "example.com/paging.Page[example.com/orders.Order]"
+            $arguments = array();
+            foreach (self::splitTopLevel(substr($typeString, $open + 1, -1), ',') as $argument) {
+                $arguments[] = self::fromString($argument);
+            }
+            /** @var Type $base */
+            $base = self::fromString(substr($typeString, 0, $open));
+            return new Generic($base, $arguments);
+        }
+
         $parts = explode('::', $typeString);
         $import = null;
 
@@ -72,9 +122,151 @@ class TypeUtil
         if ($one instanceof Slice && $two instanceof Slice) {
             return self::equals($one->getType(), $two->getType());
         }
+        if ($one instanceof ArrayType && $two instanceof ArrayType) {
+            return $one->getLength() === $two->getLength()
+            && self::equals($one->getType(), $two->getType());
+        }
+        if ($one instanceof Chan && $two instanceof Chan) {
+            return $one->getDirection() === $two->getDirection()
+            && self::equals($one->getType(), $two->getType());
+        }
+        if ($one instanceof Generic && $two instanceof Generic) {
+            return self::equals($one->getBase(), $two->getBase())
+            && self::equalsList($one->getArguments(), $two->getArguments());
+        }
+        if ($one instanceof Union && $two instanceof Union) {
+            if (!self::equalsList($one->getTerms(), $two->getTerms())) {
+                return false;
+            }
+            foreach ($one->getTerms() as $index => $term) {
+                if ($one->isApproximate($index) !== $two->isApproximate($index)) {
+                    return false;
+                }
+            }
+            return true;
+        }
         return false;
     }
 
+    /**
+     * @param AnyType[] $one
+     * @param AnyType[] $two
+     * @return bool
+     */
+    private static function equalsList(array $one, array $two)
+    {
+        if (count($one) !== count($two)) {
+            return false;
+        }
+        $two = array_values($two);
+        foreach (array_values($one) as $index => $type) {
+            if (!self::equals($type, $two[$index])) {
+                return false;
+            }
+        }
+        return true;
+    }
+
+    /**
+     * Returns position of bracket that closes the one at given position.
+     *
+     * @param string $typeString
+     * @param int $open
+     * @return int
+     */
+    private static function closingBracket($typeString, $open)
+    {
+        $depth = 0;
+        $length = strlen($typeString);
+        for ($i = $open; $i < $length; ++$i) {
+            $char = $typeString[$i];
+            if ('[' === $char || '(' === $char) {
+                ++$depth;
+            } elseif (']' === $char || ')' === $char) {
+                --$depth;
+                if (0 === $depth) {
+                    return $i;
+                }
+            }
+        }
+        return $length - 1;
+    }
+
+    /**
+     * Splits type string by separator that is not enclosed in brackets.
+     *
+     * @param string $typeString
+     * @param string $separator
+     * @return string[]
+     */
+    private static function splitTopLevel($typeString, $separator)
+    {
+        $parts = array();
+        $depth = 0;
+        $start = 0;
+        $length = strlen($typeString);
+        for ($i = 0; $i < $length; ++$i) {
+            $char = $typeString[$i];
+            if ('[' === $char || '(' === $char) {
+                ++$depth;
+            } elseif (']' === $char || ')' === $char) {
+                --$depth;
+            } elseif ($separator === $char && 0 === $depth) {
+                $parts[] = trim(substr($typeString, $start, $i - $start));
+                $start = $i + 1;
+            }
+        }
+        $parts[] = trim(substr($typeString, $start));
+        return $parts;
+    }
+
+
+    /**
+     * Returns imports of named types used in type.
+     *
+     * @param AnyType $type
+     * @return Import[]
+     */
+    public static function imports(AnyType $type)
+    {
+        if ($type instanceof Type) {
+            return $type->getImport() !== null ? array($type->getImport()) : array();
+        }
+        if ($type instanceof Pointer || $type instanceof Slice || $type instanceof ArrayType || $type instanceof Chan) {
+            return self::imports($type->getType());
+        }
+        if ($type instanceof Map) {
+            return array_merge(self::imports($type->getKeyType()), self::imports($type->getValueType()));
+        }
+
+        $types = array();
+        if ($type instanceof Generic) {
+            $types = array_merge(array($type->getBase()), $type->getArguments());
+        } elseif ($type instanceof Union) {
+            $types = $type->getTerms();
+        }
+
+        $imports = array();
+        foreach ($types as $item) {
+            $imports = array_merge($imports, self::imports($item));
+        }
+        return $imports;
+    }
+
+    /**
+     * Checks if type is an empty interface, either `interface{}` or `any`.
+     *
+     * @param AnyType $type
+     * @return bool
+     */
+    public static function isAny(AnyType $type)
+    {
+        if (!$type instanceof Type || $type->getImport() !== null) {
+            return false;
+        }
+
+        return $type->getName() === 'interface{}' || $type->getName() === 'any';
+    }
 
     public static function getBasicType(AnyType $type)
     {
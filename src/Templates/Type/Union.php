@@ -0,0 +1,64 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Templates\Type;
+
+use Swaggest\GoCodeBuilder\Templates\GoTemplate;
+
+/**
+ * Union is a type set of constraint, e.g. ~int | ~string.
+ */
+class Union extends GoTemplate implements AnyType
+{
+    /** @var AnyType[] */
+    private $terms = array();
+
+    /** @var bool[] */
+    private $approximate = array();
+
+    /**
+     * @param AnyType $type
+     * @param bool $approximate true to include types with underlying type, e.g. ~int
+     * @return $this
+     */
+    public function addTerm(AnyType $type, $approximate = false)
+    {
+        $this->terms[] = $type;
+        $this->approximate[] = $approximate;
+        return $this;
+    }
+
+    /**
+     * @return AnyType[]
+     */
+    public function getTerms()
+    {
+        return $this->terms;
+    }
+
+    /**
+     * @param int $index
+     * @return bool
+     */
+    public function isApproximate($index)
+    {
+        return $this->approximate[$index];
+    }
+
+    protected function toString()
+    {
+        $terms = array();
+        foreach ($this->terms as $index => $term) {
+            $terms[] = ($this->approximate[$index] ? '~' : '') . $term->render();
+        }
+        return implode(' | ', $terms);
+    }
+
+    public function getTypeString()
+    {
+        $terms = array();
+        foreach ($this->terms as $index => $term) {
+            $terms[] = ($this->approximate[$index] ? '~' : '') . $term->getTypeString();
+        }
+        return implode(' | ', $terms);
+    }
+}
\ No newline at end of file
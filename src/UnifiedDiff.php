@@ -0,0 +1,288 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder;
+
+/**
+ * UnifiedDiff renders line differences of two texts in unified format.
+ */
+class UnifiedDiff
+{
+    /**
+     * Maximum number of edits to search for, larger changes are rendered as full replacement.
+     */
+    const MAX_EDITS = 1000;
+
+    const NO_NEWLINE = '\\ No newline at end of file';
+
+    private $context;
+
+    /**
+     * UnifiedDiff constructor.
+     * @param int $context number of unchanged lines around changes
+     */
+    public function __construct($context = 3)
+    {
+        $this->context = $context;
+    }
+
+    /**
+     * @param string $old
+     * @param string $new
+     * @param string $oldName
+     * @param string $newName
+     * @return string empty string if texts are equal
+     */
+    public function diff($old, $new, $oldName = 'a', $newName = 'b')
+    {
+        if ($old === $new) {
+            return '';
+        }
+
+        $ops = $this->edits($this->lines($old), $this->lines($new));
+
+        $result = "--- {$oldName}\n+++ {$newName}\n";
+        foreach ($this->hunks($ops) as $hunk) {
+            $result .= $hunk;
+        }
+
+        return $result;
+    }
+
+    /**
+     * Splits text into lines, each line keeps its line feed so that a missing one at the end is a difference.
+     *
+     * @param string $text
+     * @return string[]
+     */
+    private function lines($text)
+    {
+        return preg_split('/(?<=\n)/', $text, -1, PREG_SPLIT_NO_EMPTY);
+    }
+
+    /**
+     * Builds edit script, each item is a pair of operation (' ', '-' or '+') and line.
+     *
+     * @param string[] $a
+     * @param string[] $b
+     * @return array[]
+     */
+    private function edits(array $a, array $b)
+    {
+        $n = count($a);
+        $m = count($b);
+
+        $prefix = 0;
+        while ($prefix < $n && $prefix < $m && $a[$prefix] === $b[$prefix]) {
+            ++$prefix;
+        }
+        $suffix = 0;
+        while ($suffix < $n - $prefix && $suffix < $m - $prefix
+            && $a[$n - 1 - $suffix] === $b[$m - 1 - $suffix]) {
+            ++$suffix;
+        }
+
+        $ops = array();
+        for ($i = 0; $i < $prefix; ++$i) {
+            $ops[] = array(' ', $a[$i]);
+        }
+
+        $a1 = array_slice($a, $prefix, $n - $prefix - $suffix);
+        $b1 = array_slice($b, $prefix, $m - $prefix - $suffix);
+        foreach ($this->myersEdits($a1, $b1) as $op) {
+            $ops[] = $op;
+        }
+
+        for ($i = $n - $suffix; $i < $n; ++$i) {
+            $ops[] = array(' ', $a[$i]);
+        }
+
+        return $ops;
+    }
+
+    /**
+     * Finds shortest edit script with Myers algorithm, lines are compared by identifiers.
+     *
+     * @param string[] $a
+     * @param string[] $b
+     * @return array[]
+     */
+    private function myersEdits(array $a, array $b)
+    {
+        $ids = array();
+        $x = $this->lineIds($a, $ids);
+        $y = $this->lineIds($b, $ids);
+
+        $n = count($x);
+        $m = count($y);
+        $max = min($n + $m, self::MAX_EDITS);
+
+        // Furthest reaching x by diagonal k for each number of edits.
+        $v = array(1 => 0);
+        $trace = array();
+        for ($d = 0; $d <= $max; ++$d) {
+            $trace[] = $v;
+            for ($k = -$d; $k <= $d; $k += 2) {
+                if ($k === -$d || ($k !== $d && $v[$k - 1] < $v[$k + 1])) {
+                    $i = $v[$k + 1];
+                } else {
+                    $i = $v[$k - 1] + 1;
+                }
+                $j = $i - $k;
+                while ($i < $n && $j < $m && $x[$i] === $y[$j]) {
+                    ++$i;
+                    ++$j;
+                }
+                $v[$k] = $i;
+                if ($i >= $n && $j >= $m) {
+                    return $this->backtrack($trace, $a, $b);
+                }
+            }
+        }
+
+        $ops = array();
+        foreach ($a as $line) {
+            $ops[] = array('-', $line);
+        }
+        foreach ($b as $line) {
+            $ops[] = array('+', $line);
+        }
+        return $ops;
+    }
+
+    /**
+     * @param string[] $lines
+     * @param int[] $ids identifiers by line, new lines are added
+     * @return int[]
+     */
+    private function lineIds(array $lines, array &$ids)
+    {
+        $result = array();
+        foreach ($lines as $line) {
+            if (!isset($ids[$line])) {
+                $ids[$line] = count($ids);
+            }
+            $result[] = $ids[$line];
+        }
+
+        return $result;
+    }
+
+    /**
+     * @param array[] $trace furthest reaching x by diagonal before each number of edits
+     * @param string[] $a
+     * @param string[] $b
+     * @return array[]
+     */
+    private function backtrack(array $trace, array $a, array $b)
+    {
+        $ops = array();
+        $i = count($a);
+        $j = count($b);
+        for ($d = count($trace) - 1; $d >= 0; --$d) {
+            $v = $trace[$d];
+            $k = $i - $j;
+            if ($k === -$d || ($k !== $d && $v[$k - 1] < $v[$k + 1])) {
+                $prevK = $k + 1;
+            } else {
+                $prevK = $k - 1;
+            }
+            $prevI = $v[$prevK];
+            $prevJ = $prevI - $prevK;
+
+            while ($i > $prevI && $j > $prevJ) {
+                --$i;
+                --$j;
+                $ops[] = array(' ', $a[$i]);
+            }
+            if ($d > 0) {
+                if ($i === $prevI) {
+                    $ops[] = array('+', $b[$prevJ]);
+                } else {
+                    $ops[] = array('-', $a[$prevI]);
+                }
+            }
+            $i = $prevI;
+            $j = $prevJ;
+        }
+
+        return array_reverse($ops);
+    }
+
+    /**
+     * @param array[] $ops
+     * @return string[]
+     */
+    private function hunks(array $ops)
+    {
+        $count = count($ops);
+        $changed = array();
+        foreach ($ops as $index => $op) {
+            if ($op[0] !== ' ') {
+                $changed[] = $index;
+            }
+        }
+
+        // Ranges of operations covered by hunks.
+        $ranges = array();
+        foreach ($changed as $index) {
+            $start = max(0, $index - $this->context);
+            $end = min($count - 1, $index + $this->context);
+            if ($ranges && $start <= $ranges[count($ranges) - 1][1] + 1) {
+                $ranges[count($ranges) - 1][1] = $end;
+            } else {
+                $ranges[] = array($start, $end);
+            }
+        }
+
+        // Line numbers before each operation.
+        $oldLine = array();
+        $newLine = array();
+        $o = 1;
+        $n = 1;
+        foreach ($ops as $index => $op) {
+            $oldLine[$index] = $o;
+            $newLine[$index] = $n;
+            if ($op[0] !== '+') {
+                ++$o;
+            }
+            if ($op[0] !== '-') {
+                ++$n;
+            }
+        }
+
+        $hunks = array();
+        foreach ($ranges as $range) {
+            $body = '';
+            $oldCount = 0;
+            $newCount = 0;
+            for ($index = $range[0]; $index <= $range[1]; ++$index) {
+                $op = $ops[$index];
+                $body .= $op[0] . $op[1];
+                if (substr($op[1], -1) !== "\n") {
+                    $body .= "\n" . self::NO_NEWLINE . "\n";
+                }
+                if ($op[0] !== '+') {
+                    ++$oldCount;
+                }
+                if ($op[0] !== '-') {
+                    ++$newCount;
+                }
+            }
+
+            $oldStart = $oldCount ? $oldLine[$range[0]] : $oldLine[$range[0]] - 1;
+            $newStart = $newCount ? $newLine[$range[0]] : $newLine[$range[0]] - 1;
+            $hunks[] = '@@ -' . $this->range($oldStart, $oldCount) . ' +' . $this->range($newStart, $newCount) . " @@\n"
+                . $body;
+        }
+
+        return $hunks;
+    }
+
+    private function range($start, $count)
+    {
+        if ($count === 1) {
+            return (string)$start;
+        }
+        return $start . ',' . $count;
+    }
+}
\ No newline at end of file
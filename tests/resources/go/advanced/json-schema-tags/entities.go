@@ -0,0 +1,11 @@
+// Package entities contains generated structures.
+package entities
+
+
+
+// Account structure is generated from "#".
+type Account struct {
+	Name  string  `json:"name" required:"true" minLength:"3" maxLength:"20"` // Required.
+	Age   int64   `json:"age,omitempty" minimum:"18" maximum:"130"`
+	Score float64 `json:"score,omitempty" multipleOf:"0.5" default:"1"`
+}
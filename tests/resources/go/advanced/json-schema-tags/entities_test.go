@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestAccount_JSONSchema(t *testing.T) {
+	type schema struct {
+		Required   []string                          `json:"required"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+
+	var (
+		reflector        = jsonschema.Reflector{}
+		expected, actual schema
+	)
+
+	s, err := reflector.Reflect(Account{})
+	require.NoError(t, err)
+
+	j, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(j, &actual))
+	require.NoError(t, json.Unmarshal([]byte(`{"required":["name"],"properties":{"name":{"minLength":3,"maxLength":20},"age":{"minimum":18,"maximum":130},"score":{"multipleOf":0.5,"default":1}}}`), &expected))
+
+	assert.ElementsMatch(t, expected.Required, actual.Required)
+
+	for name, keywords := range expected.Properties {
+		for keyword, value := range keywords {
+			assert.Equal(t, value, actual.Properties[name][keyword], name+"."+keyword)
+		}
+	}
+}
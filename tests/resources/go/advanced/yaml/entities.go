@@ -0,0 +1,266 @@
+// Package entities contains generated structures.
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config structure is generated from "#".
+type Config struct {
+	Name                 string            `json:"name,omitempty"`
+	Kind                 ConfigKind        `json:"kind,omitempty"`
+	Count                int64             `json:"count,omitempty"`
+	AdditionalProperties map[string]string `json:"-"`               // All unmatched properties.
+}
+
+type marshalConfig Config
+
+var knownKeysConfig = []string{
+	"name",
+	"kind",
+	"count",
+}
+
+// UnmarshalJSON decodes JSON.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var err error
+
+	mc := marshalConfig(*c)
+
+	err = json.Unmarshal(data, &mc)
+	if err != nil {
+		return err
+	}
+
+	var rawMap map[string]json.RawMessage
+
+	err = json.Unmarshal(data, &rawMap)
+	if err != nil {
+		rawMap = nil
+	}
+
+	for _, key := range knownKeysConfig {
+		delete(rawMap, key)
+	}
+
+	for key, rawValue := range rawMap {
+		if mc.AdditionalProperties == nil {
+			mc.AdditionalProperties = make(map[string]string, 1)
+		}
+
+		var val string
+
+		err = json.Unmarshal(rawValue, &val)
+		if err != nil {
+			return err
+		}
+
+		mc.AdditionalProperties[key] = val
+	}
+
+	*c = Config(mc)
+
+	return nil
+}
+
+// MarshalJSON encodes JSON.
+func (c Config) MarshalJSON() ([]byte, error) {
+	if len(c.AdditionalProperties) == 0 {
+		return json.Marshal(marshalConfig(c))
+	}
+
+	return marshalUnion(marshalConfig(c), c.AdditionalProperties)
+}
+
+// UnmarshalYAML decodes YAML node with JSON semantics.
+func (c *Config) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, c)
+}
+
+// MarshalYAML encodes YAML node with JSON semantics.
+func (c Config) MarshalYAML() (interface{}, error) {
+	return marshalYAML(c)
+}
+
+// unmarshalYAML decodes YAML node into JSON value and unmarshals it with JSON rules.
+func unmarshalYAML(node *yaml.Node, v interface{}) error {
+	var raw interface{}
+
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(yamlToJSONValue(raw))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// yamlToJSONValue converts YAML maps with non-string keys into JSON objects.
+func yamlToJSONValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = yamlToJSONValue(item)
+		}
+
+		return val
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res[fmt.Sprintf("%v", k)] = yamlToJSONValue(item)
+		}
+
+		return res
+	case []interface{}:
+		for i, item := range val {
+			val[i] = yamlToJSONValue(item)
+		}
+
+		return val
+	default:
+		return v
+	}
+}
+
+// marshalYAML encodes value with JSON rules and converts result to YAML node.
+func marshalYAML(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+
+	blockYAMLStyle(&node)
+
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		return node.Content[0], nil
+	}
+
+	return &node, nil
+}
+
+// blockYAMLStyle replaces JSON flow and quoted styles with default YAML styles.
+func blockYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, n := range node.Content {
+		blockYAMLStyle(n)
+	}
+}
+
+// ConfigKind is an enum type.
+type ConfigKind string
+
+// ConfigKind values enumeration.
+const (
+	ConfigKindAlpha = ConfigKind("alpha")
+	ConfigKindBeta = ConfigKind("beta")
+)
+
+// MarshalJSON encodes JSON.
+func (i ConfigKind) MarshalJSON() ([]byte, error) {
+	switch i {
+	case ConfigKindAlpha:
+	case ConfigKindBeta:
+
+	default:
+		return nil, fmt.Errorf("unexpected ConfigKind value: %v", i)
+	}
+
+	return json.Marshal(string(i))
+}
+
+// UnmarshalJSON decodes JSON.
+func (i *ConfigKind) UnmarshalJSON(data []byte) error {
+	var ii string
+
+	err := json.Unmarshal(data, &ii)
+	if err != nil {
+		return err
+	}
+
+	v := ConfigKind(ii)
+
+	switch v {
+	case ConfigKindAlpha:
+	case ConfigKindBeta:
+
+	default:
+		return fmt.Errorf("unexpected ConfigKind value: %v", v)
+	}
+
+	*i = v
+
+	return nil
+}
+
+// UnmarshalYAML decodes YAML node with JSON semantics.
+func (c *ConfigKind) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, c)
+}
+
+// MarshalYAML encodes YAML node with JSON semantics.
+func (c ConfigKind) MarshalYAML() (interface{}, error) {
+	return marshalYAML(c)
+}
+
+func marshalUnion(maps ...interface{}) ([]byte, error) {
+	result := []byte("{")
+	isObject := true
+
+	for _, m := range maps {
+		j, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(j) == "{}" {
+			continue
+		}
+
+		if string(j) == "null" {
+			continue
+		}
+
+		if j[0] != '{' {
+			if len(result) == 1 && (isObject || bytes.Equal(result, j)) {
+				result = j
+				isObject = false
+
+				continue
+			}
+
+			return nil, errors.New("failed to union map: object expected, " + string(j) + " received")
+		}
+
+		if !isObject {
+			return nil, errors.New("failed to union " + string(result) + " and " + string(j))
+		}
+
+		if len(result) > 1 {
+			result[len(result)-1] = ','
+		}
+
+		result = append(result, j[1:]...)
+	}
+
+	// Close empty result.
+	if isObject && len(result) == 1 {
+		result = append(result, '}')
+	}
+
+	return result, nil
+}
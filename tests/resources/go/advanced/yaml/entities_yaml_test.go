@@ -0,0 +1,56 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	entities "test/advanced/yaml"
+)
+
+func TestConfig_YAML_roundtrip(t *testing.T) {
+	var c entities.Config
+
+	require.NoError(t, yaml.Unmarshal([]byte("name: foo\nkind: beta\nregion: eu\n"), &c))
+	assert.Equal(t, "foo", c.Name)
+	assert.Equal(t, entities.ConfigKindBeta, c.Kind)
+	assert.Equal(t, map[string]string{"region": "eu"}, c.AdditionalProperties)
+
+	data, err := yaml.Marshal(c)
+	require.NoError(t, err)
+
+	var v map[string]interface{}
+
+	require.NoError(t, yaml.Unmarshal(data, &v))
+	assert.Equal(t, map[string]interface{}{"name": "foo", "kind": "beta", "region": "eu"}, v)
+}
+
+func TestConfig_YAML_omitEmpty(t *testing.T) {
+	data, err := yaml.Marshal(entities.Config{Name: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "name: foo\n", string(data))
+}
+
+func TestConfig_YAML_enum(t *testing.T) {
+	var c entities.Config
+
+	err := yaml.Unmarshal([]byte("kind: gamma\n"), &c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected ConfigKind value: gamma")
+
+	_, err = yaml.Marshal(entities.Config{Kind: "gamma"})
+	require.Error(t, err)
+}
+
+func TestConfigKind_YAML(t *testing.T) {
+	data, err := yaml.Marshal(map[string]entities.ConfigKind{"kind": entities.ConfigKindAlpha})
+	require.NoError(t, err)
+	assert.Equal(t, "kind: alpha\n", string(data))
+
+	var k entities.ConfigKind
+
+	require.NoError(t, yaml.Unmarshal([]byte("beta"), &k))
+	assert.Equal(t, entities.ConfigKindBeta, k)
+	require.Error(t, yaml.Unmarshal([]byte("gamma"), &k))
+}
@@ -0,0 +1,214 @@
+package bindings
+
+import (
+	"reflect"
+)
+
+// UserKey structure is generated from "#/components/schemas/UserKey".
+type UserKey struct {
+	UserID string `json:"userId,omitempty"`
+}
+
+// TaskQuery structure is generated from "#/components/schemas/TaskQuery".
+type TaskQuery struct {
+	Priority int64 `json:"priority,omitempty"`
+}
+
+// ChannelBindings is a protocol-specific configuration of channel, nil for protocols without binding.
+type ChannelBindings struct {
+	AMQP  *AMQPChannelBinding
+	Kafka *KafkaChannelBinding
+}
+
+// OperationBindings is a protocol-specific configuration of operation, nil for protocols without binding.
+type OperationBindings struct {
+	AMQP  *AMQPOperationBinding
+	Kafka *KafkaOperationBinding
+	MQTT  *MQTTOperationBinding
+	HTTP  *HTTPOperationBinding
+}
+
+// MessageBindings is a protocol-specific configuration of message, nil for protocols without binding.
+type MessageBindings struct {
+	AMQP  *AMQPMessageBinding
+	Kafka *KafkaMessageBinding
+	HTTP  *HTTPMessageBinding
+}
+
+// AMQPChannelBinding describes AMQP 0-9-1 exchange or queue of channel.
+type AMQPChannelBinding struct {
+	// Is is a kind of channel, "routingKey" (default) or "queue".
+	Is string
+
+	// Exchange is declared when channel is a routing key.
+	Exchange *AMQPExchange
+
+	// Queue is declared when channel is a queue.
+	Queue *AMQPQueue
+}
+
+// AMQPExchange describes AMQP exchange declaration.
+type AMQPExchange struct {
+	Name string
+
+	// Type is one of "topic", "direct", "fanout", "default" or "headers".
+	Type string
+
+	Durable    bool
+	AutoDelete bool
+	VHost      string
+}
+
+// AMQPQueue describes AMQP queue declaration.
+type AMQPQueue struct {
+	Name       string
+	Durable    bool
+	Exclusive  bool
+	AutoDelete bool
+	VHost      string
+}
+
+// AMQPOperationBinding describes AMQP properties of operation messages.
+type AMQPOperationBinding struct {
+	// Expiration is a TTL of message in milliseconds.
+	Expiration int64
+
+	UserID string
+	CC     []string
+
+	Priority int64
+
+	// DeliveryMode is 1 for transient and 2 for persistent messages.
+	DeliveryMode int64
+
+	Mandatory bool
+	BCC       []string
+	ReplyTo   string
+	Timestamp bool
+	Ack       bool
+}
+
+// AMQPMessageBinding describes AMQP properties of message.
+type AMQPMessageBinding struct {
+	ContentEncoding string
+	MessageType     string
+}
+
+// KafkaChannelBinding describes Kafka topic of channel.
+type KafkaChannelBinding struct {
+	Topic      string
+	Partitions int64
+	Replicas   int64
+}
+
+// KafkaOperationBinding describes Kafka consumer of operation.
+type KafkaOperationBinding struct {
+	GroupID  string
+	ClientID string
+}
+
+// KafkaMessageBinding describes Kafka message.
+type KafkaMessageBinding struct {
+	// Key is a type of message key.
+	Key reflect.Type
+}
+
+// MQTTOperationBinding describes MQTT delivery of operation messages.
+type MQTTOperationBinding struct {
+	// QoS is a quality of service level: 0, 1 or 2.
+	QoS int64
+
+	Retain bool
+}
+
+// HTTPOperationBinding describes HTTP request of operation.
+type HTTPOperationBinding struct {
+	// Type is "request" or "response".
+	Type string
+
+	Method string
+
+	// Query is a type of query parameters.
+	Query reflect.Type
+}
+
+// HTTPMessageBinding describes HTTP message.
+type HTTPMessageBinding struct {
+	// Headers is a type of HTTP headers.
+	Headers reflect.Type
+}
+
+// UserSignedupChannelBindings is a protocol configuration of "user/signedup" channel.
+var UserSignedupChannelBindings = ChannelBindings{
+	AMQP: &AMQPChannelBinding{
+		Is: "routingKey",
+		Exchange: &AMQPExchange{
+			Name:       "users",
+			Type:       "topic",
+			Durable:    true,
+			AutoDelete: false,
+			VHost:      "/",
+		},
+	},
+	Kafka: &KafkaChannelBinding{
+		Topic:      "user-signedup",
+		Partitions: 20,
+		Replicas:   3,
+	},
+}
+
+// OnUserSignedUpOperationBindings is a protocol configuration of onUserSignedUp operation.
+var OnUserSignedUpOperationBindings = OperationBindings{
+	AMQP: &AMQPOperationBinding{
+		Expiration:   100000,
+		CC:           []string{"user.logs"},
+		Priority:     10,
+		DeliveryMode: 2,
+		Ack:          false,
+	},
+	Kafka: &KafkaOperationBinding{
+		GroupID:  "myGroupId",
+		ClientID: "myClientId",
+	},
+	MQTT: &MQTTOperationBinding{
+		QoS:    2,
+		Retain: true,
+	},
+}
+
+// TasksChannelBindings is a protocol configuration of "tasks" channel.
+var TasksChannelBindings = ChannelBindings{
+	AMQP: &AMQPChannelBinding{
+		Is: "queue",
+		Queue: &AMQPQueue{
+			Name:       "tasks",
+			Durable:    true,
+			Exclusive:  false,
+			AutoDelete: false,
+		},
+	},
+}
+
+// SubmitTaskOperationBindings is a protocol configuration of submitTask operation.
+var SubmitTaskOperationBindings = OperationBindings{
+	HTTP: &HTTPOperationBinding{
+		Type:   "request",
+		Method: "POST",
+		Query:  reflect.TypeOf((*TaskQuery)(nil)).Elem(),
+	},
+}
+
+// UserSignedUpMessageBindings is a protocol configuration of userSignedUp message.
+var UserSignedUpMessageBindings = MessageBindings{
+	Kafka: &KafkaMessageBinding{
+		Key: reflect.TypeOf((*UserKey)(nil)).Elem(),
+	},
+}
+
+// TaskMessageBindings is a protocol configuration of task message.
+var TaskMessageBindings = MessageBindings{
+	AMQP: &AMQPMessageBinding{
+		ContentEncoding: "gzip",
+		MessageType:     "task.created",
+	},
+}
@@ -0,0 +1,60 @@
+package bindings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChannelBindings(t *testing.T) {
+	amqp := UserSignedupChannelBindings.AMQP
+	if amqp == nil || amqp.Is != "routingKey" || amqp.Exchange == nil || amqp.Queue != nil {
+		t.Fatalf("unexpected AMQP binding: %+v", amqp)
+	}
+
+	if amqp.Exchange.Name != "users" || amqp.Exchange.Type != "topic" || !amqp.Exchange.Durable || amqp.Exchange.VHost != "/" {
+		t.Fatalf("unexpected exchange: %+v", amqp.Exchange)
+	}
+
+	kafka := UserSignedupChannelBindings.Kafka
+	if kafka == nil || kafka.Topic != "user-signedup" || kafka.Partitions != 20 || kafka.Replicas != 3 {
+		t.Fatalf("unexpected Kafka binding: %+v", kafka)
+	}
+
+	queue := TasksChannelBindings.AMQP.Queue
+	if TasksChannelBindings.Kafka != nil || queue == nil || queue.Name != "tasks" || !queue.Durable {
+		t.Fatalf("unexpected tasks binding: %+v", TasksChannelBindings)
+	}
+}
+
+func TestOperationBindings(t *testing.T) {
+	b := OnUserSignedUpOperationBindings
+	if b.HTTP != nil || b.AMQP.DeliveryMode != 2 || !reflect.DeepEqual(b.AMQP.CC, []string{"user.logs"}) {
+		t.Fatalf("unexpected AMQP binding: %+v", b.AMQP)
+	}
+
+	if b.Kafka.GroupID != "myGroupId" || b.Kafka.ClientID != "myClientId" {
+		t.Fatalf("unexpected Kafka binding: %+v", b.Kafka)
+	}
+
+	if b.MQTT.QoS != 2 || !b.MQTT.Retain {
+		t.Fatalf("unexpected MQTT binding: %+v", b.MQTT)
+	}
+
+	h := SubmitTaskOperationBindings.HTTP
+	if h.Method != "POST" || h.Query != reflect.TypeOf(TaskQuery{}) {
+		t.Fatalf("unexpected HTTP binding: %+v", h)
+	}
+
+	q := reflect.New(h.Query).Interface().(*TaskQuery)
+	q.Priority = 1
+}
+
+func TestMessageBindings(t *testing.T) {
+	if UserSignedUpMessageBindings.Kafka.Key != reflect.TypeOf(UserKey{}) {
+		t.Fatalf("unexpected key type: %v", UserSignedUpMessageBindings.Kafka.Key)
+	}
+
+	if TaskMessageBindings.AMQP.ContentEncoding != "gzip" || TaskMessageBindings.AMQP.MessageType != "task.created" {
+		t.Fatalf("unexpected AMQP binding: %+v", TaskMessageBindings.AMQP)
+	}
+}
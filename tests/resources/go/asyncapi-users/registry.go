@@ -0,0 +1,30 @@
+package users
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Registry maps identifiers to factories of generated types.
+var Registry = map[string]func() interface{}{
+	"userSignedUp": func() interface{} { return new(User) },
+	"scoreUpdated": func() interface{} { return new(Score) },
+	"userDeleted":  func() interface{} { return new(UserRef) },
+}
+
+// Decode decodes JSON data into a new instance of type registered with id.
+//
+// Result is a pointer to registered type.
+func Decode(id string, data []byte) (interface{}, error) {
+	factory, ok := Registry[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown type id %q", id)
+	}
+
+	v := factory()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,22 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	v, err := Decode("userSignedUp", []byte(`{"id":"u1","email":"u1@example.com"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &User{ID: "u1", Email: "u1@example.com"}, v)
+
+	_, err = Decode("userDeleted", []byte(`{"id":1}`))
+	assert.Error(t, err)
+
+	_, err = Decode("unknown", []byte(`{}`))
+	assert.EqualError(t, err, `unknown type id "unknown"`)
+
+	assert.NotSame(t, Registry["scoreUpdated"](), Registry["scoreUpdated"]())
+}
@@ -0,0 +1,402 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// CommonHeaders structure is generated from "#/components/schemas/CommonHeaders".
+type CommonHeaders struct {
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// User structure is generated from "#/components/schemas/User".
+type User struct {
+	ID    string `json:"id"`              // Required.
+	Email string `json:"email,omitempty"`
+}
+
+// Score structure is generated from "#/components/schemas/Score".
+type Score struct {
+	Points int64 `json:"points"` // Required.
+}
+
+// UserRef structure is generated from "#/components/schemas/UserRef".
+type UserRef struct {
+	ID string `json:"id,omitempty"`
+}
+
+// UserSignupChannel is a name template of "user/{userId}/signup" channel.
+//
+// User signup events.
+const UserSignupChannel = "user/{userId}/signup"
+
+// UserSignupChannelName returns name of "user/{userId}/signup" channel with parameters.
+func UserSignupChannelName(userID string) string {
+	return "user/" + userID + "/signup"
+}
+
+// UserScoreChannel is a name template of "user/{userId}/score/{season}" channel.
+const UserScoreChannel = "user/{userId}/score/{season}"
+
+// UserScoreChannelName returns name of "user/{userId}/score/{season}" channel with parameters.
+func UserScoreChannelName(userID string, season int64) string {
+	return "user/" + userID + "/score/" + fmt.Sprint(season)
+}
+
+// AuditChannel is a name of "audit" channel.
+const AuditChannel = "audit"
+
+// UserSignedUpMessage is a "User signed up" message.
+type UserSignedUpMessage struct {
+	Headers CommonHeaders
+	Payload User
+}
+
+// ScoreUpdatedMessage is a "scoreUpdated" message.
+type ScoreUpdatedMessage struct {
+	Payload Score
+}
+
+// UserDeletedMessage is a "userDeleted" message.
+type UserDeletedMessage struct {
+	Payload UserRef
+}
+
+// Message is a transport-agnostic envelope of channel message.
+type Message struct {
+	// Channel is a name of channel with parameters.
+	Channel string
+
+	// Name identifies message, e.g. in Registry.
+	Name string
+
+	// ContentType is a media type of payload.
+	ContentType string
+
+	// Headers is a JSON object of message headers.
+	Headers []byte
+
+	// Payload is an encoded message payload.
+	Payload []byte
+}
+
+// MessageHandler handles received message.
+type MessageHandler func(ctx context.Context, msg Message) error
+
+// Broker delivers messages to channel subscribers.
+type Broker interface {
+	// Publish sends message to its channel.
+	Publish(ctx context.Context, msg Message) error
+
+	// Subscribe registers handler of channel messages.
+	Subscribe(ctx context.Context, channel string, handle MessageHandler) error
+}
+
+// MemoryBroker is an in-memory implementation of Broker for tests, messages are delivered synchronously.
+type MemoryBroker struct {
+	mu       sync.Mutex
+	handlers map[string][]MessageHandler
+}
+
+// Publish calls handlers of message channel.
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	handlers := b.handlers[msg.Channel]
+	b.mu.Unlock()
+
+	for _, handle := range handlers {
+		if err := handle(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribe adds handler of channel messages.
+func (b *MemoryBroker) Subscribe(_ context.Context, channel string, handle MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.handlers == nil {
+		b.handlers = make(map[string][]MessageHandler)
+	}
+
+	b.handlers[channel] = append(b.handlers[channel], handle)
+
+	return nil
+}
+
+// ToMessage encodes UserSignedUpMessage into envelope of channel.
+func (m UserSignedUpMessage) ToMessage(channel string) (Message, error) {
+	msg := Message{Channel: channel, Name: "userSignedUp", ContentType: "application/json"}
+
+	payload, err := json.Marshal(m.Payload)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.Payload = payload
+
+	headers, err := json.Marshal(m.Headers)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.Headers = headers
+
+	return msg, nil
+}
+
+// FromMessage decodes UserSignedUpMessage from envelope.
+func (m *UserSignedUpMessage) FromMessage(msg Message) error {
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &m.Payload); err != nil {
+			return err
+		}
+	}
+
+	if len(msg.Headers) > 0 {
+		if err := json.Unmarshal(msg.Headers, &m.Headers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ToMessage encodes ScoreUpdatedMessage into envelope of channel.
+func (m ScoreUpdatedMessage) ToMessage(channel string) (Message, error) {
+	msg := Message{Channel: channel, Name: "scoreUpdated", ContentType: "application/json"}
+
+	payload, err := json.Marshal(m.Payload)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.Payload = payload
+
+	return msg, nil
+}
+
+// FromMessage decodes ScoreUpdatedMessage from envelope.
+func (m *ScoreUpdatedMessage) FromMessage(msg Message) error {
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &m.Payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ToMessage encodes UserDeletedMessage into envelope of channel.
+func (m UserDeletedMessage) ToMessage(channel string) (Message, error) {
+	msg := Message{Channel: channel, Name: "userDeleted", ContentType: "application/vnd.users+json"}
+
+	payload, err := json.Marshal(m.Payload)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.Payload = payload
+
+	return msg, nil
+}
+
+// FromMessage decodes UserDeletedMessage from envelope.
+func (m *UserDeletedMessage) FromMessage(msg Message) error {
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &m.Payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnUserSignupPublisher publishes messages of onUserSignup operation.
+type OnUserSignupPublisher interface {
+	// PublishOnUserSignup publishes UserSignedUpMessage to "user/{userId}/signup" channel.
+	//
+	// User signed up.
+	PublishOnUserSignup(ctx context.Context, userID string, msg UserSignedUpMessage) error
+}
+
+// OnUserSignupSubscriber receives messages of onUserSignup operation.
+type OnUserSignupSubscriber interface {
+	// SubscribeOnUserSignup subscribes to messages of "user/{userId}/signup" channel.
+	//
+	// User signed up.
+	SubscribeOnUserSignup(ctx context.Context, userID string, handle func(context.Context, UserSignedUpMessage) error) error
+}
+
+// UpdateScorePublisher publishes messages of updateScore operation.
+type UpdateScorePublisher interface {
+	// PublishUpdateScore publishes ScoreUpdatedMessage to "user/{userId}/score/{season}" channel.
+	PublishUpdateScore(ctx context.Context, userID string, season int64, msg ScoreUpdatedMessage) error
+}
+
+// UpdateScoreSubscriber receives messages of updateScore operation.
+type UpdateScoreSubscriber interface {
+	// SubscribeUpdateScore subscribes to messages of "user/{userId}/score/{season}" channel.
+	SubscribeUpdateScore(ctx context.Context, userID string, season int64, handle func(context.Context, ScoreUpdatedMessage) error) error
+}
+
+// AuditPublisher publishes messages of audit operation.
+type AuditPublisher interface {
+	// PublishAuditUserSignedUp publishes UserSignedUpMessage to "audit" channel.
+	PublishAuditUserSignedUp(ctx context.Context, msg UserSignedUpMessage) error
+
+	// PublishAuditUserDeleted publishes UserDeletedMessage to "audit" channel.
+	PublishAuditUserDeleted(ctx context.Context, msg UserDeletedMessage) error
+}
+
+// AuditSubscriber receives messages of audit operation.
+type AuditSubscriber interface {
+	// SubscribeAudit subscribes to messages of "audit" channel.
+	//
+	// Handler receives envelopes of UserSignedUpMessage, UserDeletedMessage. Use AuditHandlers.Handle to receive typed messages.
+	SubscribeAudit(ctx context.Context, handle MessageHandler) error
+}
+
+// BrokerClient implements publishers and subscribers of operations with Broker.
+type BrokerClient struct {
+	Broker Broker
+}
+
+// PublishOnUserSignup publishes UserSignedUpMessage to "user/{userId}/signup" channel.
+//
+// User signed up.
+func (c BrokerClient) PublishOnUserSignup(ctx context.Context, userID string, msg UserSignedUpMessage) error {
+	m, err := msg.ToMessage(UserSignupChannelName(userID))
+	if err != nil {
+		return err
+	}
+
+	return c.Broker.Publish(ctx, m)
+}
+
+// SubscribeOnUserSignup subscribes to messages of "user/{userId}/signup" channel.
+//
+// User signed up.
+func (c BrokerClient) SubscribeOnUserSignup(ctx context.Context, userID string, handle func(context.Context, UserSignedUpMessage) error) error {
+	return c.Broker.Subscribe(ctx, UserSignupChannelName(userID), func(ctx context.Context, m Message) error {
+		var msg UserSignedUpMessage
+		if err := msg.FromMessage(m); err != nil {
+			return err
+		}
+
+		return handle(ctx, msg)
+	})
+}
+
+// PublishUpdateScore publishes ScoreUpdatedMessage to "user/{userId}/score/{season}" channel.
+func (c BrokerClient) PublishUpdateScore(ctx context.Context, userID string, season int64, msg ScoreUpdatedMessage) error {
+	m, err := msg.ToMessage(UserScoreChannelName(userID, season))
+	if err != nil {
+		return err
+	}
+
+	return c.Broker.Publish(ctx, m)
+}
+
+// SubscribeUpdateScore subscribes to messages of "user/{userId}/score/{season}" channel.
+func (c BrokerClient) SubscribeUpdateScore(ctx context.Context, userID string, season int64, handle func(context.Context, ScoreUpdatedMessage) error) error {
+	return c.Broker.Subscribe(ctx, UserScoreChannelName(userID, season), func(ctx context.Context, m Message) error {
+		var msg ScoreUpdatedMessage
+		if err := msg.FromMessage(m); err != nil {
+			return err
+		}
+
+		return handle(ctx, msg)
+	})
+}
+
+// PublishAuditUserSignedUp publishes UserSignedUpMessage to "audit" channel.
+func (c BrokerClient) PublishAuditUserSignedUp(ctx context.Context, msg UserSignedUpMessage) error {
+	m, err := msg.ToMessage(AuditChannel)
+	if err != nil {
+		return err
+	}
+
+	return c.Broker.Publish(ctx, m)
+}
+
+// PublishAuditUserDeleted publishes UserDeletedMessage to "audit" channel.
+func (c BrokerClient) PublishAuditUserDeleted(ctx context.Context, msg UserDeletedMessage) error {
+	m, err := msg.ToMessage(AuditChannel)
+	if err != nil {
+		return err
+	}
+
+	return c.Broker.Publish(ctx, m)
+}
+
+// SubscribeAudit subscribes to messages of "audit" channel.
+//
+// Handler receives envelopes of UserSignedUpMessage, UserDeletedMessage. Use AuditHandlers.Handle to receive typed messages.
+func (c BrokerClient) SubscribeAudit(ctx context.Context, handle MessageHandler) error {
+	return c.Broker.Subscribe(ctx, AuditChannel, handle)
+}
+
+// AuditHandlers dispatches envelopes of "audit" channel to handlers of typed messages by message name.
+//
+// Messages without handler are skipped, payloads are decoded with Decode.
+type AuditHandlers struct {
+	UserSignedUp func(ctx context.Context, msg UserSignedUpMessage) error
+	UserDeleted  func(ctx context.Context, msg UserDeletedMessage) error
+}
+
+// Handle implements MessageHandler.
+func (h AuditHandlers) Handle(ctx context.Context, msg Message) error {
+	switch msg.Name {
+	case "userSignedUp":
+		if h.UserSignedUp == nil {
+			return nil
+		}
+
+		payload, err := Decode(msg.Name, msg.Payload)
+		if err != nil {
+			return err
+		}
+
+		p, ok := payload.(*User)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T for %s", payload, msg.Name)
+		}
+
+		m := UserSignedUpMessage{Payload: *p}
+
+		if len(msg.Headers) > 0 {
+			if err := json.Unmarshal(msg.Headers, &m.Headers); err != nil {
+				return err
+			}
+		}
+
+		return h.UserSignedUp(ctx, m)
+	case "userDeleted":
+		if h.UserDeleted == nil {
+			return nil
+		}
+
+		payload, err := Decode(msg.Name, msg.Payload)
+		if err != nil {
+			return err
+		}
+
+		p, ok := payload.(*UserRef)
+		if !ok {
+			return fmt.Errorf("unexpected payload %T for %s", payload, msg.Name)
+		}
+
+		m := UserDeletedMessage{Payload: *p}
+
+		return h.UserDeleted(ctx, m)
+	}
+
+	return fmt.Errorf("unexpected message %q on %s channel", msg.Name, msg.Channel)
+}
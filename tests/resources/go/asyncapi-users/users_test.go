@@ -0,0 +1,127 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelName(t *testing.T) {
+	assert.Equal(t, "user/123/signup", UserSignupChannelName("123"))
+	assert.Equal(t, "user/123/score/2024", UserScoreChannelName("123", 2024))
+	assert.Equal(t, "user/{userId}/score/{season}", UserScoreChannel)
+}
+
+func TestBrokerClient(t *testing.T) {
+	ctx := context.Background()
+	c := BrokerClient{Broker: &MemoryBroker{}}
+
+	var (
+		pub OnUserSignupPublisher  = c
+		sub OnUserSignupSubscriber = c
+	)
+
+	var received []UserSignedUpMessage
+
+	require.NoError(t, sub.SubscribeOnUserSignup(ctx, "123", func(_ context.Context, msg UserSignedUpMessage) error {
+		received = append(received, msg)
+
+		return nil
+	}))
+
+	msg := UserSignedUpMessage{
+		Headers: CommonHeaders{CorrelationID: "c1"},
+		Payload: User{ID: "123", Email: "user@example.com"},
+	}
+
+	require.NoError(t, pub.PublishOnUserSignup(ctx, "123", msg))
+	require.NoError(t, pub.PublishOnUserSignup(ctx, "456", msg))
+	assert.Equal(t, []UserSignedUpMessage{msg}, received)
+
+	var scores []int64
+
+	require.NoError(t, c.SubscribeUpdateScore(ctx, "123", 2024, func(_ context.Context, msg ScoreUpdatedMessage) error {
+		scores = append(scores, msg.Payload.Points)
+
+		return errors.New("failed")
+	}))
+	assert.EqualError(t, c.PublishUpdateScore(ctx, "123", 2024, ScoreUpdatedMessage{Payload: Score{Points: 10}}), "failed")
+	assert.Equal(t, []int64{10}, scores)
+}
+
+func TestBrokerClient_envelopes(t *testing.T) {
+	ctx := context.Background()
+	c := BrokerClient{Broker: &MemoryBroker{}}
+
+	var envelopes []Message
+
+	require.NoError(t, c.SubscribeAudit(ctx, func(_ context.Context, msg Message) error {
+		envelopes = append(envelopes, msg)
+
+		return nil
+	}))
+
+	require.NoError(t, c.PublishAuditUserSignedUp(ctx, UserSignedUpMessage{Payload: User{ID: "1"}}))
+	require.NoError(t, c.PublishAuditUserDeleted(ctx, UserDeletedMessage{Payload: UserRef{ID: "1"}}))
+	require.Len(t, envelopes, 2)
+
+	assert.Equal(t, AuditChannel, envelopes[0].Channel)
+	assert.Equal(t, "userSignedUp", envelopes[0].Name)
+	assert.Equal(t, "application/json", envelopes[0].ContentType)
+	assert.Equal(t, `{}`, string(envelopes[0].Headers))
+	assert.Equal(t, `{"id":"1"}`, string(envelopes[0].Payload))
+
+	assert.Equal(t, "userDeleted", envelopes[1].Name)
+	assert.Equal(t, "application/vnd.users+json", envelopes[1].ContentType)
+	assert.Nil(t, envelopes[1].Headers)
+
+	var deleted UserDeletedMessage
+
+	require.NoError(t, deleted.FromMessage(envelopes[1]))
+	assert.Equal(t, "1", deleted.Payload.ID)
+}
+
+func TestAuditHandlers(t *testing.T) {
+	ctx := context.Background()
+	c := BrokerClient{Broker: &MemoryBroker{}}
+
+	var signedUp []UserSignedUpMessage
+
+	require.NoError(t, c.SubscribeAudit(ctx, AuditHandlers{
+		UserSignedUp: func(_ context.Context, msg UserSignedUpMessage) error {
+			signedUp = append(signedUp, msg)
+
+			return nil
+		},
+	}.Handle))
+
+	msg := UserSignedUpMessage{
+		Headers: CommonHeaders{CorrelationID: "c1"},
+		Payload: User{ID: "1", Email: "user@example.com"},
+	}
+
+	require.NoError(t, c.PublishAuditUserSignedUp(ctx, msg))
+	require.NoError(t, c.PublishAuditUserDeleted(ctx, UserDeletedMessage{Payload: UserRef{ID: "1"}}))
+	assert.Equal(t, []UserSignedUpMessage{msg}, signedUp)
+
+	h := AuditHandlers{UserDeleted: func(context.Context, UserDeletedMessage) error { return nil }}
+
+	assert.EqualError(t, h.Handle(ctx, Message{Channel: AuditChannel, Name: "scoreUpdated"}),
+		`unexpected message "scoreUpdated" on audit channel`)
+	assert.Error(t, h.Handle(ctx, Message{Channel: AuditChannel, Name: "userDeleted", Payload: []byte(`{"id":1}`)}))
+}
+
+func TestAuditHandlers_payloadType(t *testing.T) {
+	factory := Registry["userDeleted"]
+	Registry["userDeleted"] = func() interface{} { return new(User) }
+
+	defer func() { Registry["userDeleted"] = factory }()
+
+	h := AuditHandlers{UserDeleted: func(context.Context, UserDeletedMessage) error { return nil }}
+
+	assert.EqualError(t, h.Handle(context.Background(), Message{Name: "userDeleted", Payload: []byte(`{"id":"1"}`)}),
+		"unexpected payload *users.User for userDeleted")
+}
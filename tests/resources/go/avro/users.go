@@ -0,0 +1,76 @@
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// User structure is generated from "#/definitions/com.example.users.User".
+//
+// User account.
+type User struct {
+	ID         string           `json:"id"`         // Required.
+	Age        int32            `json:"age"`        // Required.
+	Score      float32          `json:"score"`      // Required.
+	Status     Status           `json:"status"`     // Required.
+	Tags       []string         `json:"tags"`       // Required.
+	Limits     map[string]int64 `json:"limits"`     // Required.
+	Address    Address          `json:"address"`    // Required.
+	Nickname   *string          `json:"nickname"`
+	CreatedAt  *int64           `json:"createdAt"`
+	Balance    *string          `json:"balance"`
+	ExternalID *string          `json:"externalId"` // Format: uuid.
+	Birthday   *int32           `json:"birthday"`
+}
+
+// Address structure is generated from "#/definitions/com.example.users.Address".
+type Address struct {
+	City string  `json:"city"` // Required.
+	Zip  *string `json:"zip"`
+}
+
+// Status is an enum type.
+type Status string
+
+// Status values enumeration.
+const (
+	StatusActive = Status("active")
+	StatusLocked = Status("locked")
+)
+
+// MarshalJSON encodes JSON.
+func (i Status) MarshalJSON() ([]byte, error) {
+	switch i {
+	case StatusActive:
+	case StatusLocked:
+
+	default:
+		return nil, fmt.Errorf("unexpected Status value: %v", i)
+	}
+
+	return json.Marshal(string(i))
+}
+
+// UnmarshalJSON decodes JSON.
+func (i *Status) UnmarshalJSON(data []byte) error {
+	var ii string
+
+	err := json.Unmarshal(data, &ii)
+	if err != nil {
+		return err
+	}
+
+	v := Status(ii)
+
+	switch v {
+	case StatusActive:
+	case StatusLocked:
+
+	default:
+		return fmt.Errorf("unexpected Status value: %v", v)
+	}
+
+	*i = v
+
+	return nil
+}
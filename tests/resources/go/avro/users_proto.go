@@ -0,0 +1,87 @@
+package avro
+
+import (
+	"test/avro/pb"
+)
+
+// MapTo converts User to protobuf message.
+func (base User) MapTo() *pb.User {
+	result := &pb.User{}
+	result.Id = base.ID
+	result.Age = base.Age
+	result.Score = base.Score
+	result.Status = base.Status.MapTo()
+	result.Tags = base.Tags
+	result.Limits = base.Limits
+	result.Address = base.Address.MapTo()
+	result.Nickname = base.Nickname
+	result.CreatedAt = base.CreatedAt
+	result.Balance = base.Balance
+	result.ExternalId = base.ExternalID
+	result.Birthday = base.Birthday
+	return result
+}
+
+// LoadFrom loads User from protobuf message.
+func (base *User) LoadFrom(derived *pb.User) {
+	*base = User{}
+	if derived == nil {
+		return
+	}
+
+	base.ID = derived.Id
+	base.Age = derived.Age
+	base.Score = derived.Score
+	base.Status.LoadFrom(derived.Status)
+	base.Tags = derived.Tags
+	base.Limits = derived.Limits
+	base.Address.LoadFrom(derived.Address)
+	base.Nickname = derived.Nickname
+	base.CreatedAt = derived.CreatedAt
+	base.Balance = derived.Balance
+	base.ExternalID = derived.ExternalId
+	base.Birthday = derived.Birthday
+}
+
+// MapTo converts Address to protobuf message.
+func (base Address) MapTo() *pb.Address {
+	result := &pb.Address{}
+	result.City = base.City
+	result.Zip = base.Zip
+	return result
+}
+
+// LoadFrom loads Address from protobuf message.
+func (base *Address) LoadFrom(derived *pb.Address) {
+	*base = Address{}
+	if derived == nil {
+		return
+	}
+
+	base.City = derived.City
+	base.Zip = derived.Zip
+}
+
+// MapTo converts Status to protobuf enum.
+func (base Status) MapTo() pb.Status {
+	switch base {
+	case StatusActive:
+		return pb.Status_STATUS_ACTIVE
+	case StatusLocked:
+		return pb.Status_STATUS_LOCKED
+	}
+
+	return pb.Status_STATUS_UNSPECIFIED
+}
+
+// LoadFrom loads Status from protobuf enum.
+func (base *Status) LoadFrom(derived pb.Status) {
+	switch derived {
+	case pb.Status_STATUS_ACTIVE:
+		*base = StatusActive
+	case pb.Status_STATUS_LOCKED:
+		*base = StatusLocked
+	default:
+		*base = ""
+	}
+}
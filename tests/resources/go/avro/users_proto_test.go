@@ -0,0 +1,47 @@
+package avro
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"test/avro/pb"
+)
+
+func TestUser_MapTo(t *testing.T) {
+	var u User
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"1","age":30,"score":1.5,"status":"locked","tags":["a"],`+
+		`"limits":{"x":1},"address":{"city":"Berlin","zip":null},"nickname":"jo",`+
+		`"createdAt":1577934245000,"balance":"10.25","birthday":10958}`), &u))
+
+	m := u.MapTo()
+	assert.Equal(t, "1", m.Id)
+	assert.Equal(t, pb.Status_STATUS_LOCKED, m.Status)
+	assert.Equal(t, "Berlin", m.Address.City)
+	assert.Nil(t, m.Address.Zip)
+	assert.Equal(t, "jo", *m.Nickname)
+	assert.Equal(t, int64(1577934245000), *m.CreatedAt)
+	assert.Nil(t, m.ExternalId)
+
+	// Message is encoded and decoded with protobuf runtime to check protoc-gen-go compatibility.
+	data, err := proto.Marshal(m)
+	require.NoError(t, err)
+
+	decoded := &pb.User{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+
+	var loaded User
+	loaded.LoadFrom(decoded)
+	assert.Equal(t, u, loaded)
+
+	loaded.LoadFrom(nil)
+	assert.Equal(t, User{}, loaded)
+
+	var s Status
+	s.LoadFrom(pb.Status_STATUS_UNSPECIFIED)
+	assert.Equal(t, Status(""), s)
+	assert.Equal(t, pb.Status_STATUS_UNSPECIFIED, s.MapTo())
+}
@@ -0,0 +1,32 @@
+package avro
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser(t *testing.T) {
+	var u User
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"1","age":30,"score":1.5,"status":"locked","tags":["a"],`+
+		`"limits":{"x":1},"address":{"city":"Berlin","zip":null},"nickname":null,`+
+		`"createdAt":1577934245000,"balance":"10.25","birthday":10958}`), &u))
+	assert.Equal(t, int32(30), u.Age)
+	assert.Equal(t, StatusLocked, u.Status)
+	assert.Equal(t, "Berlin", u.Address.City)
+	assert.Nil(t, u.Address.Zip)
+	assert.Equal(t, "10.25", *u.Balance)
+	assert.Equal(t, int64(1577934245000), *u.CreatedAt)
+	assert.Equal(t, int32(10958), *u.Birthday)
+
+	data, err := json.Marshal(u)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","age":30,"score":1.5,"status":"locked","tags":["a"],"limits":{"x":1},`+
+		`"address":{"city":"Berlin","zip":null},"nickname":null,"createdAt":1577934245000,`+
+		`"balance":"10.25","externalId":null,"birthday":10958}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"status":"deleted"}`), &u))
+}
@@ -3,17 +3,20 @@ module test
 go 1.17
 
 require (
-	github.com/stretchr/testify v1.7.1
-	github.com/swaggest/assertjson v1.6.8
+	github.com/stretchr/testify v1.8.2
+	github.com/swaggest/assertjson v1.9.0
+	github.com/swaggest/jsonschema-go v0.3.70
 	github.com/yudai/gojsondiff v1.0.0
+	google.golang.org/protobuf v1.28.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/bool64/shared v0.1.4 // indirect
+	github.com/bool64/shared v0.1.5 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/iancoleman/orderedmap v0.2.0 // indirect
+	github.com/iancoleman/orderedmap v0.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/sergi/go-diff v1.2.0 // indirect
+	github.com/sergi/go-diff v1.3.1 // indirect
+	github.com/swaggest/refl v1.3.0 // indirect
 	github.com/yudai/golcs v0.0.0-20170316035057-ecda9a501e82 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
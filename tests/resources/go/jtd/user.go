@@ -0,0 +1,75 @@
+package jtd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// User structure is generated from "#/definitions/user".
+//
+// User account.
+type User struct {
+	ID        string           `json:"id"`                  // Required.
+	Age       uint8            `json:"age"`                 // Required.
+	Score     float32          `json:"score"`               // Required.
+	Status    UserStatus       `json:"status"`              // Required.
+	Tags      []string         `json:"tags"`                // Required.
+	Labels    map[string]int32 `json:"labels"`              // Required.
+	Home      Address          `json:"home"`                // Required.
+	Nickname  *string          `json:"nickname"`            // Display name.
+	Rating    int16            `json:"rating,omitempty"`    // Position in leaderboard.
+	CreatedAt *time.Time       `json:"createdAt,omitempty"` // Format: date-time.
+}
+
+// Address structure is generated from "#/definitions/address".
+type Address struct {
+	City string `json:"city"`          // Required.
+	Zip  string `json:"zip,omitempty"` // Postal code.
+}
+
+// UserStatus is an enum type.
+type UserStatus string
+
+// UserStatus values enumeration.
+const (
+	UserStatusActive = UserStatus("active")
+	UserStatusBanned = UserStatus("banned")
+)
+
+// MarshalJSON encodes JSON.
+func (i UserStatus) MarshalJSON() ([]byte, error) {
+	switch i {
+	case UserStatusActive:
+	case UserStatusBanned:
+
+	default:
+		return nil, fmt.Errorf("unexpected UserStatus value: %v", i)
+	}
+
+	return json.Marshal(string(i))
+}
+
+// UnmarshalJSON decodes JSON.
+func (i *UserStatus) UnmarshalJSON(data []byte) error {
+	var ii string
+
+	err := json.Unmarshal(data, &ii)
+	if err != nil {
+		return err
+	}
+
+	v := UserStatus(ii)
+
+	switch v {
+	case UserStatusActive:
+	case UserStatusBanned:
+
+	default:
+		return fmt.Errorf("unexpected UserStatus value: %v", v)
+	}
+
+	*i = v
+
+	return nil
+}
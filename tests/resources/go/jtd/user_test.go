@@ -0,0 +1,30 @@
+package jtd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser(t *testing.T) {
+	var u User
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"1","age":30,"score":1.5,"status":"active","tags":["a"],`+
+		`"labels":{"x":-1},"home":{"city":"Berlin"},"nickname":null,"createdAt":"2020-01-02T03:04:05Z"}`), &u))
+	assert.Equal(t, uint8(30), u.Age)
+	assert.Equal(t, UserStatusActive, u.Status)
+	assert.Equal(t, map[string]int32{"x": -1}, u.Labels)
+	assert.Equal(t, "Berlin", u.Home.City)
+	assert.Nil(t, u.Nickname)
+	assert.Equal(t, 2020, u.CreatedAt.Year())
+
+	data, err := json.Marshal(u)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","age":30,"score":1.5,"status":"active","tags":["a"],"labels":{"x":-1},`+
+		`"home":{"city":"Berlin"},"nickname":null,"createdAt":"2020-01-02T03:04:05Z"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"status":"deleted"}`), &u))
+	assert.Error(t, json.Unmarshal([]byte(`{"age":256}`), &u))
+}
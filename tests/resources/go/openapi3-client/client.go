@@ -0,0 +1,269 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Order structure is generated from "#/components/schemas/Order".
+type Order struct {
+	ID     string  `json:"id"`               // Required.
+	Amount float64 `json:"amount,omitempty"`
+	Status string  `json:"status,omitempty"`
+}
+
+// Error structure is generated from "#/components/schemas/Error".
+type Error struct {
+	Message string `json:"message,omitempty"`
+}
+
+// ListOrdersRequest is a request of GET /orders.
+type ListOrdersRequest struct {
+	Limit      *int64   `query:"limit"`
+	Status     []string `query:"status"`
+	Codes      []int64  `query:"codes"`
+	XRequestID *string  `header:"X-Request-ID"`
+}
+
+// ListOrdersResponse is a response of GET /orders.
+type ListOrdersResponse struct {
+	StatusCode int
+	OK         []Order
+	Default    *Error
+}
+
+// CreateOrderRequest is a request of POST /orders.
+type CreateOrderRequest struct {
+	IdempotencyKey string `header:"Idempotency-Key"`
+	Body           Order
+}
+
+// CreateOrderResponse is a response of POST /orders.
+type CreateOrderResponse struct {
+	StatusCode int
+	Created    *Order
+	BadRequest *Error
+}
+
+// GetOrderRequest is a request of GET /orders/{orderId}.
+type GetOrderRequest struct {
+	OrderID string  `path:"orderId"`
+	Session *string `cookie:"session"`
+}
+
+// GetOrderResponse is a response of GET /orders/{orderId}.
+type GetOrderResponse struct {
+	StatusCode int
+	OK         *Order
+	NotFound   *Error
+}
+
+// DeleteOrderRequest is a request of DELETE /orders/{orderId}.
+type DeleteOrderRequest struct {
+	OrderID string `path:"orderId"`
+}
+
+// DeleteOrderResponse is a response of DELETE /orders/{orderId}.
+type DeleteOrderResponse struct {
+	StatusCode int
+}
+
+// Client is a client of Orders API.
+type Client interface {
+	// ListOrders performs GET /orders.
+	//
+	// List orders.
+	ListOrders(ctx context.Context, req ListOrdersRequest) (ListOrdersResponse, error)
+
+	// CreateOrder performs POST /orders.
+	CreateOrder(ctx context.Context, req CreateOrderRequest) (CreateOrderResponse, error)
+
+	// GetOrder performs GET /orders/{orderId}.
+	GetOrder(ctx context.Context, req GetOrderRequest) (GetOrderResponse, error)
+
+	// DeleteOrder performs DELETE /orders/{orderId}.
+	DeleteOrder(ctx context.Context, req DeleteOrderRequest) (DeleteOrderResponse, error)
+}
+
+// HTTPClient implements Client with net/http.
+type HTTPClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewHTTPClient creates HTTPClient for API server at base URL.
+func NewHTTPClient(baseURL string) *HTTPClient {
+	return &HTTPClient{
+		BaseURL: baseURL,
+		Client:  http.DefaultClient,
+	}
+}
+
+// ListOrders performs GET /orders.
+//
+// List orders.
+func (c *HTTPClient) ListOrders(ctx context.Context, req ListOrdersRequest) (ListOrdersResponse, error) {
+	var resp ListOrdersResponse
+
+	query := make(url.Values)
+	if req.Limit != nil {
+		query.Set("limit", fmt.Sprint(*req.Limit))
+	}
+	for _, v := range req.Status {
+		query.Add("status", v)
+	}
+	if len(req.Codes) > 0 {
+		values := make([]string, 0, len(req.Codes))
+		for _, v := range req.Codes {
+			values = append(values, fmt.Sprint(v))
+		}
+		query.Set("codes", strings.Join(values, ","))
+	}
+
+	u := c.BaseURL + "/orders"
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	if req.XRequestID != nil {
+		r.Header.Set("X-Request-ID", *req.XRequestID)
+	}
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+		err = json.NewDecoder(res.Body).Decode(&resp.OK)
+	default:
+		resp.Default = new(Error)
+		err = json.NewDecoder(res.Body).Decode(resp.Default)
+	}
+
+	return resp, err
+}
+
+// CreateOrder performs POST /orders.
+func (c *HTTPClient) CreateOrder(ctx context.Context, req CreateOrderRequest) (CreateOrderResponse, error) {
+	var resp CreateOrderResponse
+
+	u := c.BaseURL + "/orders"
+
+	body, err := json.Marshal(req.Body)
+	if err != nil {
+		return resp, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Idempotency-Key", req.IdempotencyKey)
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusCreated:
+		resp.Created = new(Order)
+		err = json.NewDecoder(res.Body).Decode(resp.Created)
+	case res.StatusCode == http.StatusBadRequest:
+		resp.BadRequest = new(Error)
+		err = json.NewDecoder(res.Body).Decode(resp.BadRequest)
+	default:
+		err = fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return resp, err
+}
+
+// GetOrder performs GET /orders/{orderId}.
+func (c *HTTPClient) GetOrder(ctx context.Context, req GetOrderRequest) (GetOrderResponse, error) {
+	var resp GetOrderResponse
+
+	u := c.BaseURL + "/orders/" + url.PathEscape(req.OrderID)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	if req.Session != nil {
+		r.AddCookie(&http.Cookie{Name: "session", Value: *req.Session})
+	}
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+		resp.OK = new(Order)
+		err = json.NewDecoder(res.Body).Decode(resp.OK)
+	case res.StatusCode == http.StatusNotFound:
+		resp.NotFound = new(Error)
+		err = json.NewDecoder(res.Body).Decode(resp.NotFound)
+	default:
+		err = fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return resp, err
+}
+
+// DeleteOrder performs DELETE /orders/{orderId}.
+func (c *HTTPClient) DeleteOrder(ctx context.Context, req DeleteOrderRequest) (DeleteOrderResponse, error) {
+	var resp DeleteOrderResponse
+
+	u := c.BaseURL + "/orders/" + url.PathEscape(req.OrderID)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusNoContent:
+	default:
+		err = fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient(t *testing.T) {
+	var rawQuery, requestIDHeader string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /orders":
+			rawQuery = r.URL.RawQuery
+			requestIDHeader = r.Header.Get("X-Request-ID")
+			_, _ = w.Write([]byte(`[{"id":"1","amount":12.5},{"id":"2"}]`))
+		case "POST /orders":
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "key", r.Header.Get("Idempotency-Key"))
+
+			var o Order
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&o))
+
+			if o.Amount < 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"negative amount"}`))
+
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			assert.NoError(t, json.NewEncoder(w).Encode(o))
+		case "GET /orders/a/b":
+			c, err := r.Cookie("session")
+			assert.NoError(t, err)
+			assert.Equal(t, "s1", c.Value)
+			_, _ = w.Write([]byte(`{"id":"a/b"}`))
+		case "DELETE /orders/a/b":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"unexpected"}`))
+		}
+	}))
+	defer srv.Close()
+
+	var c Client = NewHTTPClient(srv.URL)
+	ctx := context.Background()
+
+	limit := int64(10)
+	requestID := "abc"
+	list, err := c.ListOrders(ctx, ListOrdersRequest{
+		Limit:      &limit,
+		Status:     []string{"new", "paid"},
+		Codes:      []int64{1, 2},
+		XRequestID: &requestID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, list.StatusCode)
+	assert.Equal(t, []Order{{ID: "1", Amount: 12.5}, {ID: "2"}}, list.OK)
+	assert.Equal(t, "codes=1%2C2&limit=10&status=new&status=paid", rawQuery)
+	assert.Equal(t, "abc", requestIDHeader)
+
+	created, err := c.CreateOrder(ctx, CreateOrderRequest{IdempotencyKey: "key", Body: Order{ID: "3", Amount: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, created.StatusCode)
+	assert.Equal(t, &Order{ID: "3", Amount: 1}, created.Created)
+
+	created, err = c.CreateOrder(ctx, CreateOrderRequest{IdempotencyKey: "key", Body: Order{ID: "4", Amount: -1}})
+	require.NoError(t, err)
+	assert.Nil(t, created.Created)
+	assert.Equal(t, &Error{Message: "negative amount"}, created.BadRequest)
+
+	session := "s1"
+	order, err := c.GetOrder(ctx, GetOrderRequest{OrderID: "a/b", Session: &session})
+	require.NoError(t, err)
+	assert.Equal(t, &Order{ID: "a/b"}, order.OK)
+
+	deleted, err := c.DeleteOrder(ctx, DeleteOrderRequest{OrderID: "a/b"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, deleted.StatusCode)
+
+	_, err = c.DeleteOrder(ctx, DeleteOrderRequest{OrderID: "c"})
+	assert.EqualError(t, err, "unexpected response status: 500 Internal Server Error")
+
+	_, err = c.ListOrders(ctx, ListOrdersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "", rawQuery)
+	assert.Equal(t, "", requestIDHeader)
+}
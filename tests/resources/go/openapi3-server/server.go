@@ -0,0 +1,424 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Order structure is generated from "#/components/schemas/Order".
+type Order struct {
+	ID     string  `json:"id"`               // Required.
+	Amount float64 `json:"amount,omitempty"`
+	Status string  `json:"status,omitempty"`
+}
+
+// Error structure is generated from "#/components/schemas/Error".
+type Error struct {
+	Message string `json:"message,omitempty"`
+}
+
+// ListOrdersRequest is a request of GET /orders.
+type ListOrdersRequest struct {
+	Limit      *int64   `query:"limit"`
+	Status     []string `query:"status"`
+	Codes      []int64  `query:"codes"`
+	XRequestID *string  `header:"X-Request-ID"`
+}
+
+// ListOrdersResponse is a response of GET /orders.
+type ListOrdersResponse struct {
+	StatusCode int
+	OK         []Order
+	Default    *Error
+}
+
+// CreateOrderRequest is a request of POST /orders.
+type CreateOrderRequest struct {
+	IdempotencyKey string `header:"Idempotency-Key"`
+	Body           Order
+}
+
+// CreateOrderResponse is a response of POST /orders.
+type CreateOrderResponse struct {
+	StatusCode int
+	Created    *Order
+	BadRequest *Error
+}
+
+// GetOrderRequest is a request of GET /orders/{orderId}.
+type GetOrderRequest struct {
+	OrderID string  `path:"orderId"`
+	Session *string `cookie:"session"`
+}
+
+// GetOrderResponse is a response of GET /orders/{orderId}.
+type GetOrderResponse struct {
+	StatusCode int
+	OK         *Order
+	NotFound   *Error
+}
+
+// DeleteOrderRequest is a request of DELETE /orders/{orderId}.
+type DeleteOrderRequest struct {
+	OrderID string `path:"orderId"`
+}
+
+// DeleteOrderResponse is a response of DELETE /orders/{orderId}.
+type DeleteOrderResponse struct {
+	StatusCode int
+}
+
+// OrdersServer handles orders operations of Orders API.
+type OrdersServer interface {
+	// ListOrders performs GET /orders.
+	//
+	// List orders.
+	ListOrders(ctx context.Context, req ListOrdersRequest) (ListOrdersResponse, error)
+
+	// CreateOrder performs POST /orders.
+	CreateOrder(ctx context.Context, req CreateOrderRequest) (CreateOrderResponse, error)
+
+	// GetOrder performs GET /orders/{orderId}.
+	GetOrder(ctx context.Context, req GetOrderRequest) (GetOrderResponse, error)
+
+	// DeleteOrder performs DELETE /orders/{orderId}.
+	DeleteOrder(ctx context.Context, req DeleteOrderRequest) (DeleteOrderResponse, error)
+}
+
+// UnimplementedOrdersServer can be embedded to have forward compatible implementation of OrdersServer.
+type UnimplementedOrdersServer struct{}
+
+// ListOrders implements OrdersServer.
+func (UnimplementedOrdersServer) ListOrders(ctx context.Context, req ListOrdersRequest) (ListOrdersResponse, error) {
+	var r0 ListOrdersResponse
+	return r0, errors.New("OrdersServer.ListOrders is not implemented")
+}
+
+// CreateOrder implements OrdersServer.
+func (UnimplementedOrdersServer) CreateOrder(ctx context.Context, req CreateOrderRequest) (CreateOrderResponse, error) {
+	var r0 CreateOrderResponse
+	return r0, errors.New("OrdersServer.CreateOrder is not implemented")
+}
+
+// GetOrder implements OrdersServer.
+func (UnimplementedOrdersServer) GetOrder(ctx context.Context, req GetOrderRequest) (GetOrderResponse, error) {
+	var r0 GetOrderResponse
+	return r0, errors.New("OrdersServer.GetOrder is not implemented")
+}
+
+// DeleteOrder implements OrdersServer.
+func (UnimplementedOrdersServer) DeleteOrder(ctx context.Context, req DeleteOrderRequest) (DeleteOrderResponse, error) {
+	var r0 DeleteOrderResponse
+	return r0, errors.New("OrdersServer.DeleteOrder is not implemented")
+}
+
+// Router dispatches HTTP requests to server operations by method and path.
+type Router struct {
+	// OnError writes error response, http.Error is used if nil.
+	OnError func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+	// Validate checks decoded request structure, error is written with http.StatusBadRequest.
+	// Schema constraints of parameters and body (e.g. enum, minimum, pattern) are not checked otherwise.
+	Validate func(r *http.Request, req interface{}) error
+
+	routes []route
+}
+
+type routeHandler func(w http.ResponseWriter, r *http.Request, path map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handle   routeHandler
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		handle           routeHandler
+		params           map[string]string
+		methodNotAllowed bool
+	)
+
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+
+	for _, rr := range rt.routes {
+		path, ok := matchPath(rr.segments, segments)
+		if !ok {
+			continue
+		}
+
+		if rr.method != r.Method {
+			methodNotAllowed = true
+
+			continue
+		}
+
+		// Route with less parameters is more specific.
+		if handle == nil || len(path) < len(params) {
+			handle = rr.handle
+			params = path
+		}
+	}
+
+	switch {
+	case handle != nil:
+		handle(w, r, params)
+	case methodNotAllowed:
+		rt.fail(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	default:
+		rt.fail(w, r, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (rt *Router) add(method, pattern string, handle routeHandler) {
+	rt.routes = append(rt.routes, route{method: method, segments: strings.Split(pattern, "/"), handle: handle})
+}
+
+func (rt *Router) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if rt.OnError != nil {
+		rt.OnError(w, r, status, err)
+
+		return
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+func matchPath(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	path := make(map[string]string)
+
+	for i, p := range pattern {
+		if len(p) > 2 && p[0] == '{' && p[len(p)-1] == '}' {
+			v, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+
+			path[p[1:len(p)-1]] = v
+
+			continue
+		}
+
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+
+	return path, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// RegisterOrdersServer adds routes of OrdersServer operations.
+func (rt *Router) RegisterOrdersServer(srv OrdersServer) {
+	rt.add(http.MethodGet, "/orders", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveListOrders(srv, w, r, path)
+	})
+	rt.add(http.MethodPost, "/orders", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveCreateOrder(srv, w, r, path)
+	})
+	rt.add(http.MethodGet, "/orders/{orderId}", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveGetOrder(srv, w, r, path)
+	})
+	rt.add(http.MethodDelete, "/orders/{orderId}", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveDeleteOrder(srv, w, r, path)
+	})
+}
+
+func (rt *Router) serveListOrders(srv OrdersServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req ListOrdersRequest
+
+	query := r.URL.Query()
+
+	if v := query.Get("limit"); v != "" {
+		x, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid query parameter limit: %w", err))
+			return
+		}
+
+		req.Limit = &x
+	}
+
+	for _, v := range query["status"] {
+		req.Status = append(req.Status, v)
+	}
+
+	if v := query.Get("codes"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			x, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid query parameter codes: %w", err))
+				return
+			}
+
+			req.Codes = append(req.Codes, x)
+		}
+	}
+
+	if v := r.Header.Get("X-Request-ID"); v != "" {
+		req.XRequestID = &v
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.ListOrders(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		writeJSON(w, resp.StatusCode, "application/json", resp.OK)
+	default:
+		writeJSON(w, resp.StatusCode, "application/json", resp.Default)
+	}
+}
+
+func (rt *Router) serveCreateOrder(srv OrdersServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req CreateOrderRequest
+
+	if v := r.Header.Get("Idempotency-Key"); v != "" {
+		req.IdempotencyKey = v
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing header parameter Idempotency-Key"))
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.CreateOrder(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusCreated
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+		writeJSON(w, resp.StatusCode, "application/json", resp.Created)
+	case resp.StatusCode == http.StatusBadRequest:
+		writeJSON(w, resp.StatusCode, "application/json", resp.BadRequest)
+	default:
+		w.WriteHeader(resp.StatusCode)
+	}
+}
+
+func (rt *Router) serveGetOrder(srv OrdersServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req GetOrderRequest
+
+	if v := path["orderId"]; v != "" {
+		req.OrderID = v
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing path parameter orderId"))
+		return
+	}
+
+	if v := cookieValue(r, "session"); v != "" {
+		req.Session = &v
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.GetOrder(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		writeJSON(w, resp.StatusCode, "application/json", resp.OK)
+	case resp.StatusCode == http.StatusNotFound:
+		writeJSON(w, resp.StatusCode, "application/json", resp.NotFound)
+	default:
+		w.WriteHeader(resp.StatusCode)
+	}
+}
+
+func (rt *Router) serveDeleteOrder(srv OrdersServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req DeleteOrderRequest
+
+	if v := path["orderId"]; v != "" {
+		req.OrderID = v
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing path parameter orderId"))
+		return
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.DeleteOrder(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusNoContent
+	}
+
+	w.WriteHeader(resp.StatusCode)
+}
+
+func cookieValue(r *http.Request, name string) string {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return c.Value
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ordersServer struct {
+	UnimplementedOrdersServer
+
+	listReq   ListOrdersRequest
+	createReq CreateOrderRequest
+	getReq    GetOrderRequest
+}
+
+func (s *ordersServer) ListOrders(_ context.Context, req ListOrdersRequest) (ListOrdersResponse, error) {
+	s.listReq = req
+
+	return ListOrdersResponse{OK: []Order{{ID: "1", Amount: 10}}}, nil
+}
+
+func (s *ordersServer) CreateOrder(_ context.Context, req CreateOrderRequest) (CreateOrderResponse, error) {
+	s.createReq = req
+
+	if req.Body.ID == "" {
+		return CreateOrderResponse{StatusCode: http.StatusBadRequest, BadRequest: &Error{Message: "empty id"}}, nil
+	}
+
+	return CreateOrderResponse{Created: &req.Body}, nil
+}
+
+func (s *ordersServer) GetOrder(_ context.Context, req GetOrderRequest) (GetOrderResponse, error) {
+	s.getReq = req
+
+	if req.OrderID != "o/1" {
+		return GetOrderResponse{StatusCode: http.StatusNotFound, NotFound: &Error{Message: "not found"}}, nil
+	}
+
+	return GetOrderResponse{OK: &Order{ID: req.OrderID}}, nil
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+
+	return rw
+}
+
+func TestRouter(t *testing.T) {
+	srv := &ordersServer{}
+	rt := &Router{}
+	rt.RegisterOrdersServer(srv)
+
+	r := httptest.NewRequest(http.MethodGet, "/orders?limit=2&status=new&status=paid&codes=1,2", nil)
+	r.Header.Set("X-Request-ID", "abc")
+	rw := serve(rt, r)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assert.Equal(t, `[{"id":"1","amount":10}]`+"\n", rw.Body.String())
+	require.NotNil(t, srv.listReq.Limit)
+	assert.Equal(t, int64(2), *srv.listReq.Limit)
+	assert.Equal(t, []string{"new", "paid"}, srv.listReq.Status)
+	assert.Equal(t, []int64{1, 2}, srv.listReq.Codes)
+	require.NotNil(t, srv.listReq.XRequestID)
+	assert.Equal(t, "abc", *srv.listReq.XRequestID)
+
+	rw = serve(rt, httptest.NewRequest(http.MethodGet, "/orders?limit=two", nil))
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), "invalid query parameter limit")
+
+	rw = serve(rt, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":"2"}`)))
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "missing header parameter Idempotency-Key\n", rw.Body.String())
+
+	r = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":"2","amount":5}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	rw = serve(rt, r)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, `{"id":"2","amount":5}`+"\n", rw.Body.String())
+	assert.Equal(t, "k1", srv.createReq.IdempotencyKey)
+
+	r = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k2")
+	rw = serve(rt, r)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, `{"message":"empty id"}`+"\n", rw.Body.String())
+
+	r = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{`))
+	r.Header.Set("Idempotency-Key", "k3")
+	rw = serve(rt, r)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), "invalid request body")
+
+	r = httptest.NewRequest(http.MethodGet, "/orders/o%2F1", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	rw = serve(rt, r)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `{"id":"o/1"}`+"\n", rw.Body.String())
+	require.NotNil(t, srv.getReq.Session)
+	assert.Equal(t, "s1", *srv.getReq.Session)
+
+	rw = serve(rt, httptest.NewRequest(http.MethodGet, "/orders/2", nil))
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Equal(t, `{"message":"not found"}`+"\n", rw.Body.String())
+
+	rw = serve(rt, httptest.NewRequest(http.MethodDelete, "/orders/2", nil))
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, "OrdersServer.DeleteOrder is not implemented\n", rw.Body.String())
+
+	rw = serve(rt, httptest.NewRequest(http.MethodPut, "/orders", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+
+	rw = serve(rt, httptest.NewRequest(http.MethodGet, "/customers", nil))
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestRouter_OnError(t *testing.T) {
+	rt := &Router{}
+	rt.RegisterOrdersServer(UnimplementedOrdersServer{})
+
+	rt.OnError = func(w http.ResponseWriter, r *http.Request, status int, err error) {
+		writeJSON(w, status, "application/json", Error{Message: err.Error()})
+	}
+
+	rw := serve(rt, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, `{"message":"OrdersServer.ListOrders is not implemented"}`+"\n", rw.Body.String())
+}
+
+func TestRouter_Validate(t *testing.T) {
+	srv := &ordersServer{}
+	rt := &Router{}
+	rt.RegisterOrdersServer(srv)
+
+	rt.Validate = func(r *http.Request, req interface{}) error {
+		if req, ok := req.(CreateOrderRequest); ok && req.Body.Amount < 0 {
+			return errors.New("negative amount")
+		}
+
+		return nil
+	}
+
+	// Constraints are not in the API document, so Router only rejects the request with Validate.
+	r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":"3","amount":-1}`))
+	r.Header.Set("Idempotency-Key", "k4")
+	rw := serve(rt, r)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "negative amount\n", rw.Body.String())
+	assert.Empty(t, srv.createReq.IdempotencyKey)
+
+	r = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":"3","amount":1}`))
+	r.Header.Set("Idempotency-Key", "k5")
+	rw = serve(rt, r)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "k5", srv.createReq.IdempotencyKey)
+}
@@ -0,0 +1,74 @@
+package sample
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Order structure is generated from "#/definitions/order".
+type Order struct {
+	ID       int64             `json:"id"`                 // Required.
+	Status   OrderStatus       `json:"status"`             // Required.
+	Total    float64           `json:"total"`              // Required.
+	Items    []OrderItemsItems `json:"items"`              // Required.
+	Coupon   *string           `json:"coupon"`
+	Customer *OrderCustomer    `json:"customer,omitempty"`
+}
+
+// OrderItemsItems structure is generated from "#/definitions/order->items->items".
+type OrderItemsItems struct {
+	Sku  string `json:"sku"`            // Required.
+	Qty  int64  `json:"qty"`            // Required.
+	Note string `json:"note,omitempty"`
+}
+
+// OrderCustomer structure is generated from "#/definitions/order->customer".
+type OrderCustomer struct {
+	Name string `json:"name"` // Required.
+}
+
+// OrderStatus is an enum type.
+type OrderStatus string
+
+// OrderStatus values enumeration.
+const (
+	OrderStatusPaid = OrderStatus("paid")
+	OrderStatusSent = OrderStatus("sent")
+)
+
+// MarshalJSON encodes JSON.
+func (i OrderStatus) MarshalJSON() ([]byte, error) {
+	switch i {
+	case OrderStatusPaid:
+	case OrderStatusSent:
+
+	default:
+		return nil, fmt.Errorf("unexpected OrderStatus value: %v", i)
+	}
+
+	return json.Marshal(string(i))
+}
+
+// UnmarshalJSON decodes JSON.
+func (i *OrderStatus) UnmarshalJSON(data []byte) error {
+	var ii string
+
+	err := json.Unmarshal(data, &ii)
+	if err != nil {
+		return err
+	}
+
+	v := OrderStatus(ii)
+
+	switch v {
+	case OrderStatusPaid:
+	case OrderStatusSent:
+
+	default:
+		return fmt.Errorf("unexpected OrderStatus value: %v", v)
+	}
+
+	*i = v
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package sample
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder(t *testing.T) {
+	f, err := os.Open("../../sample-orders.jsonl")
+	require.NoError(t, err)
+	defer f.Close()
+
+	var orders []Order
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+
+		var o Order
+		require.NoError(t, json.Unmarshal(s.Bytes(), &o))
+		orders = append(orders, o)
+	}
+	require.NoError(t, s.Err())
+	require.Len(t, orders, 10)
+
+	assert.Equal(t, OrderStatusSent, orders[1].Status)
+	assert.Equal(t, "gift", orders[1].Items[0].Note)
+	assert.Equal(t, "SALE", *orders[1].Coupon)
+	assert.Equal(t, "Ann", orders[2].Customer.Name)
+
+	data, err := json.Marshal(orders[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"status":"paid","total":10.5,"items":[{"sku":"a-1","qty":1}],"coupon":null}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"status":"lost"}`), &orders[0]))
+
+	// Sku is not an enum, unseen values are decoded.
+	var item OrderItemsItems
+
+	require.NoError(t, json.Unmarshal([]byte(`{"sku":"z-26","qty":1}`), &item))
+	assert.Equal(t, "z-26", item.Sku)
+}
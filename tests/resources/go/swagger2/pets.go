@@ -0,0 +1,711 @@
+package swagger2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pet structure is generated from "#/definitions/Pet".
+type Pet struct {
+	Name    string `json:"name"`    // Required.
+	PetType string `json:"petType"` // Required.
+}
+
+// Error structure is generated from "#/definitions/Error".
+type Error struct {
+	Message string `json:"message,omitempty"`
+}
+
+// Cat structure is generated from "#/definitions/Cat".
+type Cat struct {
+	Pet
+	CatAllOf1
+}
+
+// CatAllOf1 structure is generated from "#/definitions/Cat/allOf/1".
+type CatAllOf1 struct {
+	HuntingSkill string `json:"huntingSkill,omitempty"`
+}
+
+// FindPetsRequest is a request of GET /pets.
+type FindPetsRequest struct {
+	Tags    []string `query:"tags"`
+	Codes   []int64  `query:"codes"`
+	Limit   *int64   `query:"limit"`
+	XFields []string `header:"X-Fields"`
+}
+
+// FindPetsResponse is a response of GET /pets.
+type FindPetsResponse struct {
+	StatusCode int
+	OK         []Pet
+	Default    *Error
+}
+
+// AddPetRequest is a request of POST /pets.
+type AddPetRequest struct {
+	Body Pet
+}
+
+// AddPetResponse is a response of POST /pets.
+type AddPetResponse struct {
+	StatusCode int
+	Created    *Pet
+}
+
+// RenamePetRequest is a request of PATCH /pets/{petId}.
+type RenamePetRequest struct {
+	PetID   int64    `path:"petId"`
+	Name    string   `formData:"name"`
+	Aliases []string `formData:"aliases"`
+}
+
+// RenamePetResponse is a response of PATCH /pets/{petId}.
+type RenamePetResponse struct {
+	StatusCode int
+	Default    *Error
+}
+
+// UploadPhotoRequest is a request of POST /pets/{petId}/photo.
+type UploadPhotoRequest struct {
+	PetID   int64     `path:"petId"`
+	Caption *string   `formData:"caption"`
+	Photo   io.Reader `formData:"photo"`
+}
+
+// UploadPhotoResponse is a response of POST /pets/{petId}/photo.
+type UploadPhotoResponse struct {
+	StatusCode int
+}
+
+// Client is a client of Pets API.
+type Client interface {
+	// FindPets performs GET /pets.
+	//
+	// Find pets.
+	FindPets(ctx context.Context, req FindPetsRequest) (FindPetsResponse, error)
+
+	// AddPet performs POST /pets.
+	AddPet(ctx context.Context, req AddPetRequest) (AddPetResponse, error)
+
+	// RenamePet performs PATCH /pets/{petId}.
+	RenamePet(ctx context.Context, req RenamePetRequest) (RenamePetResponse, error)
+
+	// UploadPhoto performs POST /pets/{petId}/photo.
+	UploadPhoto(ctx context.Context, req UploadPhotoRequest) (UploadPhotoResponse, error)
+}
+
+// HTTPClient implements Client with net/http.
+type HTTPClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewHTTPClient creates HTTPClient for API server at base URL.
+func NewHTTPClient(baseURL string) *HTTPClient {
+	return &HTTPClient{
+		BaseURL: baseURL,
+		Client:  http.DefaultClient,
+	}
+}
+
+// FindPets performs GET /pets.
+//
+// Find pets.
+func (c *HTTPClient) FindPets(ctx context.Context, req FindPetsRequest) (FindPetsResponse, error) {
+	var resp FindPetsResponse
+
+	query := make(url.Values)
+	for _, v := range req.Tags {
+		query.Add("tags", v)
+	}
+	if len(req.Codes) > 0 {
+		values := make([]string, 0, len(req.Codes))
+		for _, v := range req.Codes {
+			values = append(values, fmt.Sprint(v))
+		}
+		query.Set("codes", strings.Join(values, "|"))
+	}
+	if req.Limit != nil {
+		query.Set("limit", fmt.Sprint(*req.Limit))
+	}
+
+	u := c.BaseURL + "/pets"
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	if len(req.XFields) > 0 {
+		r.Header.Set("X-Fields", strings.Join(req.XFields, ","))
+	}
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+		err = json.NewDecoder(res.Body).Decode(&resp.OK)
+	default:
+		resp.Default = new(Error)
+		err = json.NewDecoder(res.Body).Decode(resp.Default)
+	}
+
+	return resp, err
+}
+
+// AddPet performs POST /pets.
+func (c *HTTPClient) AddPet(ctx context.Context, req AddPetRequest) (AddPetResponse, error) {
+	var resp AddPetResponse
+
+	u := c.BaseURL + "/pets"
+
+	body, err := json.Marshal(req.Body)
+	if err != nil {
+		return resp, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusCreated:
+		resp.Created = new(Pet)
+		err = json.NewDecoder(res.Body).Decode(resp.Created)
+	default:
+		err = fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return resp, err
+}
+
+// RenamePet performs PATCH /pets/{petId}.
+func (c *HTTPClient) RenamePet(ctx context.Context, req RenamePetRequest) (RenamePetResponse, error) {
+	var resp RenamePetResponse
+
+	u := c.BaseURL + "/pets/" + url.PathEscape(fmt.Sprint(req.PetID))
+
+	form := make(url.Values)
+	form.Set("name", req.Name)
+	for _, v := range req.Aliases {
+		form.Add("aliases", v)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPatch, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return resp, err
+	}
+
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusNoContent:
+	default:
+		resp.Default = new(Error)
+		err = json.NewDecoder(res.Body).Decode(resp.Default)
+	}
+
+	return resp, err
+}
+
+// UploadPhoto performs POST /pets/{petId}/photo.
+func (c *HTTPClient) UploadPhoto(ctx context.Context, req UploadPhotoRequest) (UploadPhotoResponse, error) {
+	var resp UploadPhotoResponse
+
+	u := c.BaseURL + "/pets/" + url.PathEscape(fmt.Sprint(req.PetID)) + "/photo"
+
+	form := make(url.Values)
+	if req.Caption != nil {
+		form.Set("caption", *req.Caption)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, values := range form {
+		for _, v := range values {
+			if err := mw.WriteField(name, v); err != nil {
+				return resp, err
+			}
+		}
+	}
+
+	partPhoto, err := mw.CreateFormFile("photo", "photo")
+	if err != nil {
+		return resp, err
+	}
+
+	if _, err := io.Copy(partPhoto, req.Photo); err != nil {
+		return resp, err
+	}
+
+	if err := mw.Close(); err != nil {
+		return resp, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u, &body)
+	if err != nil {
+		return resp, err
+	}
+
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	res, err := c.Client.Do(r)
+	if err != nil {
+		return resp, err
+	}
+
+	defer res.Body.Close()
+
+	resp.StatusCode = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusNoContent:
+	default:
+		err = fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return resp, err
+}
+
+// PetsServer handles pets operations of Pets API.
+type PetsServer interface {
+	// FindPets performs GET /pets.
+	//
+	// Find pets.
+	FindPets(ctx context.Context, req FindPetsRequest) (FindPetsResponse, error)
+
+	// AddPet performs POST /pets.
+	AddPet(ctx context.Context, req AddPetRequest) (AddPetResponse, error)
+
+	// RenamePet performs PATCH /pets/{petId}.
+	RenamePet(ctx context.Context, req RenamePetRequest) (RenamePetResponse, error)
+
+	// UploadPhoto performs POST /pets/{petId}/photo.
+	UploadPhoto(ctx context.Context, req UploadPhotoRequest) (UploadPhotoResponse, error)
+}
+
+// UnimplementedPetsServer can be embedded to have forward compatible implementation of PetsServer.
+type UnimplementedPetsServer struct{}
+
+// FindPets implements PetsServer.
+func (UnimplementedPetsServer) FindPets(ctx context.Context, req FindPetsRequest) (FindPetsResponse, error) {
+	var r0 FindPetsResponse
+	return r0, errors.New("PetsServer.FindPets is not implemented")
+}
+
+// AddPet implements PetsServer.
+func (UnimplementedPetsServer) AddPet(ctx context.Context, req AddPetRequest) (AddPetResponse, error) {
+	var r0 AddPetResponse
+	return r0, errors.New("PetsServer.AddPet is not implemented")
+}
+
+// RenamePet implements PetsServer.
+func (UnimplementedPetsServer) RenamePet(ctx context.Context, req RenamePetRequest) (RenamePetResponse, error) {
+	var r0 RenamePetResponse
+	return r0, errors.New("PetsServer.RenamePet is not implemented")
+}
+
+// UploadPhoto implements PetsServer.
+func (UnimplementedPetsServer) UploadPhoto(ctx context.Context, req UploadPhotoRequest) (UploadPhotoResponse, error) {
+	var r0 UploadPhotoResponse
+	return r0, errors.New("PetsServer.UploadPhoto is not implemented")
+}
+
+// Router dispatches HTTP requests to server operations by method and path.
+type Router struct {
+	// OnError writes error response, http.Error is used if nil.
+	OnError func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+	// Validate checks decoded request structure, error is written with http.StatusBadRequest.
+	// Schema constraints of parameters and body (e.g. enum, minimum, pattern) are not checked otherwise.
+	Validate func(r *http.Request, req interface{}) error
+
+	routes []route
+}
+
+type routeHandler func(w http.ResponseWriter, r *http.Request, path map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handle   routeHandler
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		handle           routeHandler
+		params           map[string]string
+		methodNotAllowed bool
+	)
+
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+
+	for _, rr := range rt.routes {
+		path, ok := matchPath(rr.segments, segments)
+		if !ok {
+			continue
+		}
+
+		if rr.method != r.Method {
+			methodNotAllowed = true
+
+			continue
+		}
+
+		// Route with less parameters is more specific.
+		if handle == nil || len(path) < len(params) {
+			handle = rr.handle
+			params = path
+		}
+	}
+
+	switch {
+	case handle != nil:
+		handle(w, r, params)
+	case methodNotAllowed:
+		rt.fail(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	default:
+		rt.fail(w, r, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (rt *Router) add(method, pattern string, handle routeHandler) {
+	rt.routes = append(rt.routes, route{method: method, segments: strings.Split(pattern, "/"), handle: handle})
+}
+
+func (rt *Router) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if rt.OnError != nil {
+		rt.OnError(w, r, status, err)
+
+		return
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+func matchPath(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	path := make(map[string]string)
+
+	for i, p := range pattern {
+		if len(p) > 2 && p[0] == '{' && p[len(p)-1] == '}' {
+			v, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+
+			path[p[1:len(p)-1]] = v
+
+			continue
+		}
+
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+
+	return path, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// RegisterPetsServer adds routes of PetsServer operations.
+func (rt *Router) RegisterPetsServer(srv PetsServer) {
+	rt.add(http.MethodGet, "/pets", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveFindPets(srv, w, r, path)
+	})
+	rt.add(http.MethodPost, "/pets", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveAddPet(srv, w, r, path)
+	})
+	rt.add(http.MethodPatch, "/pets/{petId}", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveRenamePet(srv, w, r, path)
+	})
+	rt.add(http.MethodPost, "/pets/{petId}/photo", func(w http.ResponseWriter, r *http.Request, path map[string]string) {
+		rt.serveUploadPhoto(srv, w, r, path)
+	})
+}
+
+func (rt *Router) serveFindPets(srv PetsServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req FindPetsRequest
+
+	query := r.URL.Query()
+
+	for _, v := range query["tags"] {
+		req.Tags = append(req.Tags, v)
+	}
+
+	if v := query.Get("codes"); v != "" {
+		for _, s := range strings.Split(v, "|") {
+			x, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid query parameter codes: %w", err))
+				return
+			}
+
+			req.Codes = append(req.Codes, x)
+		}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		x, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid query parameter limit: %w", err))
+			return
+		}
+
+		req.Limit = &x
+	}
+
+	if v := r.Header.Get("X-Fields"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			req.XFields = append(req.XFields, s)
+		}
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.FindPets(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		writeJSON(w, resp.StatusCode, "application/json", resp.OK)
+	default:
+		writeJSON(w, resp.StatusCode, "application/json", resp.Default)
+	}
+}
+
+func (rt *Router) serveAddPet(srv PetsServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req AddPetRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.AddPet(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusCreated
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+		writeJSON(w, resp.StatusCode, "application/json", resp.Created)
+	default:
+		w.WriteHeader(resp.StatusCode)
+	}
+}
+
+func (rt *Router) serveRenamePet(srv PetsServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req RenamePetRequest
+
+	if v := path["petId"]; v != "" {
+		x, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid path parameter petId: %w", err))
+			return
+		}
+
+		req.PetID = x
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing path parameter petId"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid form: %w", err))
+		return
+	}
+
+	form := r.PostForm
+
+	if v := form.Get("name"); v != "" {
+		req.Name = v
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing formData parameter name"))
+		return
+	}
+
+	for _, v := range form["aliases"] {
+		req.Aliases = append(req.Aliases, v)
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.RenamePet(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusNoContent
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		w.WriteHeader(resp.StatusCode)
+	default:
+		writeJSON(w, resp.StatusCode, "application/json", resp.Default)
+	}
+}
+
+func (rt *Router) serveUploadPhoto(srv PetsServer, w http.ResponseWriter, r *http.Request, path map[string]string) {
+	var req UploadPhotoRequest
+
+	if v := path["petId"]; v != "" {
+		x, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid path parameter petId: %w", err))
+			return
+		}
+
+		req.PetID = x
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing path parameter petId"))
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid form: %w", err))
+		return
+	}
+
+	form := r.PostForm
+
+	if v := form.Get("caption"); v != "" {
+		req.Caption = &v
+	}
+
+	if f, _, err := r.FormFile("photo"); err == nil {
+		defer f.Close()
+		req.Photo = f
+	} else if err != http.ErrMissingFile {
+		rt.fail(w, r, http.StatusBadRequest, fmt.Errorf("invalid formData parameter photo: %w", err))
+		return
+	} else {
+		rt.fail(w, r, http.StatusBadRequest, errors.New("missing formData parameter photo"))
+		return
+	}
+
+	if rt.Validate != nil {
+		if err := rt.Validate(r, req); err != nil {
+			rt.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp, err := srv.UploadPhoto(r.Context(), req)
+	if err != nil {
+		rt.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusNoContent
+	}
+
+	w.WriteHeader(resp.StatusCode)
+}
+
+// Registry maps identifiers to factories of generated types.
+var Registry = map[string]func() interface{}{
+	"Pet": func() interface{} { return new(Pet) },
+	"Cat": func() interface{} { return new(Cat) },
+}
+
+// Decode decodes JSON data into a new instance of type registered with id.
+//
+// Result is a pointer to registered type.
+func Decode(id string, data []byte) (interface{}, error) {
+	factory, ok := Registry[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown type id %q", id)
+	}
+
+	v := factory()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,144 @@
+package swagger2
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type petsServer struct {
+	UnimplementedPetsServer
+
+	findReq   FindPetsRequest
+	renameReq RenamePetRequest
+	caption   *string
+	photo     string
+}
+
+func (s *petsServer) FindPets(_ context.Context, req FindPetsRequest) (FindPetsResponse, error) {
+	s.findReq = req
+
+	return FindPetsResponse{OK: []Pet{{Name: "Tom", PetType: "Cat"}}}, nil
+}
+
+func (s *petsServer) AddPet(_ context.Context, req AddPetRequest) (AddPetResponse, error) {
+	return AddPetResponse{Created: &req.Body}, nil
+}
+
+func (s *petsServer) RenamePet(_ context.Context, req RenamePetRequest) (RenamePetResponse, error) {
+	s.renameReq = req
+
+	if req.PetID != 1 {
+		return RenamePetResponse{StatusCode: http.StatusNotFound, Default: &Error{Message: "not found"}}, nil
+	}
+
+	return RenamePetResponse{}, nil
+}
+
+func (s *petsServer) UploadPhoto(_ context.Context, req UploadPhotoRequest) (UploadPhotoResponse, error) {
+	photo, err := io.ReadAll(req.Photo)
+	if err != nil {
+		return UploadPhotoResponse{}, err
+	}
+
+	s.caption = req.Caption
+	s.photo = string(photo)
+
+	return UploadPhotoResponse{}, nil
+}
+
+func TestHTTPClient(t *testing.T) {
+	srv := &petsServer{}
+	rt := &Router{}
+	rt.RegisterPetsServer(srv)
+
+	ts := httptest.NewServer(rt)
+	defer ts.Close()
+
+	ctx := context.Background()
+	c := NewHTTPClient(ts.URL)
+
+	limit := int64(10)
+	found, err := c.FindPets(ctx, FindPetsRequest{
+		Tags:    []string{"a", "b"},
+		Codes:   []int64{1, 2},
+		Limit:   &limit,
+		XFields: []string{"name", "petType"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, found.StatusCode)
+	assert.Equal(t, []Pet{{Name: "Tom", PetType: "Cat"}}, found.OK)
+	assert.Equal(t, []string{"a", "b"}, srv.findReq.Tags)
+	assert.Equal(t, []int64{1, 2}, srv.findReq.Codes)
+	assert.Equal(t, limit, *srv.findReq.Limit)
+	assert.Equal(t, []string{"name", "petType"}, srv.findReq.XFields)
+
+	added, err := c.AddPet(ctx, AddPetRequest{Body: Pet{Name: "Rex", PetType: "Dog"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, added.StatusCode)
+	assert.Equal(t, &Pet{Name: "Rex", PetType: "Dog"}, added.Created)
+
+	renamed, err := c.RenamePet(ctx, RenamePetRequest{PetID: 1, Name: "Max", Aliases: []string{"M", "Maxi"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, renamed.StatusCode)
+	assert.Equal(t, RenamePetRequest{PetID: 1, Name: "Max", Aliases: []string{"M", "Maxi"}}, srv.renameReq)
+
+	renamed, err = c.RenamePet(ctx, RenamePetRequest{PetID: 2, Name: "Max"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, renamed.StatusCode)
+	assert.Equal(t, &Error{Message: "not found"}, renamed.Default)
+
+	caption := "Sleeping"
+	uploaded, err := c.UploadPhoto(ctx, UploadPhotoRequest{PetID: 1, Caption: &caption, Photo: strings.NewReader("jpeg")})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, uploaded.StatusCode)
+	assert.Equal(t, caption, *srv.caption)
+	assert.Equal(t, "jpeg", srv.photo)
+}
+
+func TestRouter_form(t *testing.T) {
+	rt := &Router{}
+	rt.RegisterPetsServer(&petsServer{})
+
+	r := httptest.NewRequest(http.MethodPatch, "/pets/1", strings.NewReader("aliases=M"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw := httptest.NewRecorder()
+	rt.ServeHTTP(rw, r)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "missing formData parameter name\n", rw.Body.String())
+
+	r = httptest.NewRequest(http.MethodPost, "/pets/1/photo", strings.NewReader("--b\r\n\r\n--b--\r\n"))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+	rw = httptest.NewRecorder()
+	rt.ServeHTTP(rw, r)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "missing formData parameter photo\n", rw.Body.String())
+}
+
+func TestDecode(t *testing.T) {
+	v, err := Decode("Pet", []byte(`{"name":"Tom","petType":"Cat"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Pet{Name: "Tom", PetType: "Cat"}, v)
+}
+
+func TestDecode_discriminator(t *testing.T) {
+	data := []byte(`{"name":"Tom","petType":"Cat","huntingSkill":"lazy"}`)
+
+	var pet Pet
+
+	require.NoError(t, json.Unmarshal(data, &pet))
+
+	v, err := Decode(pet.PetType, data)
+	require.NoError(t, err)
+	assert.Equal(t, &Cat{
+		Pet:       Pet{Name: "Tom", PetType: "Cat"},
+		CatAllOf1: CatAllOf1{HuntingSkill: "lazy"},
+	}, v)
+}
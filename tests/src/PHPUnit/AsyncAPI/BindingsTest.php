@@ -0,0 +1,45 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\AsyncAPI;
+
+
+use Swaggest\GoCodeBuilder\AsyncAPI\Bindings;
+use Swaggest\GoCodeBuilder\AsyncAPI\Builder;
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class BindingsTest extends \PHPUnit_Framework_TestCase
+{
+    public function testBindings()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/asyncapi-bindings.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $channels = $builder->getChannels();
+        $this->assertSame(array('amqp', 'kafka'), array_keys($channels[0]->bindings));
+        $this->assertSame('queue', $channels[1]->bindings['amqp']->is);
+        $this->assertSame(array('http.query'), array_keys($channels[1]->operations[0]->bindingTypes));
+
+        $goFile = new GoFile('bindings');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+        $goFile->getCode()->addSnippet(new Bindings($builder));
+
+        $filePath = __DIR__ . '/../../../resources/go/asyncapi-bindings/bindings.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+}
\ No newline at end of file
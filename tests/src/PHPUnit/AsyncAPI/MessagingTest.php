@@ -0,0 +1,94 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\AsyncAPI;
+
+
+use Swaggest\GoCodeBuilder\AsyncAPI\Builder;
+use Swaggest\GoCodeBuilder\AsyncAPI\Messaging;
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\Registry;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class MessagingTest extends \PHPUnit_Framework_TestCase
+{
+    public function testMessaging()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/asyncapi-users.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $registry = new Registry($goBuilder);
+        $builder->register($registry);
+
+        $goFile = new GoFile('users');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+        $goFile->getCode()->addSnippet($builder->getCode());
+        $goFile->getCode()->addSnippet((new Messaging($builder))->setRegistry($registry));
+
+        $filePath = __DIR__ . '/../../../resources/go/asyncapi-users/users.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testRegistry()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/asyncapi-users.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $registry = new Registry($goBuilder);
+        $builder->register($registry);
+
+        $goFile = new GoFile('users');
+        $goFile->fileComment = '';
+        $goFile->getCode()->addSnippet($registry);
+
+        $filePath = __DIR__ . '/../../../resources/go/asyncapi-users/registry.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testMissingParameter()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "asyncapi": "2.4.0",
+  "info": {"title": "Broken", "version": "1.0.0"},
+  "channels": {
+    "user/{userId}/signup": {
+      "subscribe": {"message": {"payload": {"type": "string"}}}
+    }
+  }
+}
+JSON
+        );
+
+        $this->setExpectedException(
+            'Swaggest\GoCodeBuilder\AsyncAPI\Exception',
+            'Missing parameter "userId" of channel user/{userId}/signup'
+        );
+        (new Builder($document))->build();
+    }
+}
\ No newline at end of file
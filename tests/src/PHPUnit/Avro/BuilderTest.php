@@ -0,0 +1,101 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\Avro;
+
+
+use Swaggest\GoCodeBuilder\Avro\Builder;
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class BuilderTest extends \PHPUnit_Framework_TestCase
+{
+    public function testBuilder()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/avro-users.avsc'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $goFile = new GoFile('avro');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+
+        $filePath = __DIR__ . '/../../../resources/go/avro/users.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testSchemaData()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "type": "record",
+  "name": "Event",
+  "namespace": "com.example",
+  "fields": [
+    {"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 16}},
+    {"name": "parent", "type": "Hash", "doc": "Parent hash."},
+    {"name": "retries", "type": ["null", "int"], "default": null},
+    {"name": "payload", "type": ["string", "long", "null"]}
+  ]
+}
+JSON
+        );
+
+        $builder = new Builder($document);
+        $schemaData = json_decode(json_encode($builder->getSchemaData()), true);
+
+        $this->assertSame(array('com.example.Event', 'com.example.Hash'), array_keys($schemaData['definitions']));
+        $this->assertSame(array('type' => 'string'), $schemaData['definitions']['com.example.Hash']);
+        $this->assertSame(
+            array(
+                'hash' => array('$ref' => '#/definitions/com.example.Hash'),
+                'parent' => array('anyOf' => array(array('$ref' => '#/definitions/com.example.Hash')), 'description' => 'Parent hash.'),
+                'retries' => array(
+                    'type' => 'integer',
+                    'minimum' => -2147483648,
+                    'maximum' => 2147483647,
+                    'x-go-type' => '*int32',
+                    'x-nullable' => true,
+                    'x-omitempty' => false,
+                ),
+                'payload' => array(
+                    'oneOf' => array(array('type' => 'string'), array('type' => 'integer')),
+                    'x-nullable' => true,
+                    'x-omitempty' => false,
+                ),
+            ),
+            $schemaData['definitions']['com.example.Event']['properties']
+        );
+        $this->assertSame(array('hash', 'parent', 'payload'), $schemaData['definitions']['com.example.Event']['required']);
+    }
+
+    public function testAliases()
+    {
+        $document = json_decode('{"type": "record", "name": "User", "aliases": ["Account"], "fields": [{"name": "id", "type": "long"}]}');
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->renames = array('Account' => 'Customer');
+
+        $builder = new Builder($document, $goBuilder);
+        $this->assertSame('Customer', $builder->getType('Account')->getTypeString());
+        $this->assertSame(array('Account' => 'Customer', 'User' => 'Customer'), $goBuilder->options->renames);
+    }
+
+    public function testUnknownType()
+    {
+        $this->setExpectedException('Swaggest\GoCodeBuilder\Avro\Exception', 'Unknown type Address at #/fields/0/type');
+        new Builder(json_decode('{"type": "record", "name": "User", "fields": [{"name": "home", "type": "Address"}]}'));
+    }
+}
\ No newline at end of file
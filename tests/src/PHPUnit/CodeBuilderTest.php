@@ -4,6 +4,8 @@ namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
 
 
 use Swaggest\GoCodeBuilder\GoCodeBuilder;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
 
 class CodeBuilderTest extends \PHPUnit_Framework_TestCase
 {
@@ -14,4 +16,39 @@ class CodeBuilderTest extends \PHPUnit_Framework_TestCase
         $this->assertSame('Schema', $codeBuilder->exportableName('$schema'));
     }
 
+    public function testGoFileHeader()
+    {
+        $codeBuilder = new GoCodeBuilder();
+        $codeBuilder->buildConstraint = 'linux && amd64';
+        $codeBuilder->generateDirectives[] = 'json-cli gen-go schema.json --output ./entities.go';
+        $codeBuilder->nolint = array('lll', 'funlen');
+        $codeBuilder->licenseHeaderFile = __DIR__ . '/../../resources/license-header.txt';
+
+        $goFile = new GoFile('sample');
+        $goFile->setComment('Package sample contains generated entities.');
+        $goFile->setCode(new Code("type Sample int\n"));
+        $codeBuilder->setUpGoFile($goFile);
+
+        $this->assertSame(<<<GO
+// Copyright 2026 Acme Inc.
+//
+// Licensed under the MIT License.
+
+// Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.
+
+//go:build linux && amd64
+
+//go:generate json-cli gen-go schema.json --output ./entities.go
+
+// Package sample contains generated entities.
+//nolint:lll,funlen
+package sample
+
+
+
+type Sample int
+
+GO
+            , $goFile->render());
+    }
 }
\ No newline at end of file
@@ -0,0 +1,24 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
+
+
+use Swaggest\GoCodeBuilder\GoCodeBuilder;
+
+/**
+ * FilesCodeBuilder writes predefined contents instead of registered templates.
+ */
+class FilesCodeBuilder extends GoCodeBuilder
+{
+    /**
+     * @var string[] contents by relative path
+     */
+    public $contents = array();
+
+    protected function writeFiles($srcPath)
+    {
+        foreach ($this->contents as $relativePath => $code) {
+            file_put_contents($srcPath . DIRECTORY_SEPARATOR . $relativePath, $code);
+        }
+    }
+}
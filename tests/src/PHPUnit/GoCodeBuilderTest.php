@@ -0,0 +1,164 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
+
+
+use Swaggest\GoCodeBuilder\Exception;
+use Swaggest\GoCodeBuilder\Import;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\GoCodeBuilder\Templates\ProtectedRegion;
+
+class GoCodeBuilderTest extends \PHPUnit_Framework_TestCase
+{
+    private $srcPath;
+
+    protected function setUp()
+    {
+        $this->srcPath = tempnam(sys_get_temp_dir(), 'go-code-builder-test');
+        unlink($this->srcPath);
+        mkdir($this->srcPath);
+    }
+
+    protected function tearDown()
+    {
+        foreach (glob($this->srcPath . '/*') as $file) {
+            unlink($file);
+        }
+        rmdir($this->srcPath);
+    }
+
+    public function testStoreToDiskPreservesRegions()
+    {
+        file_put_contents($this->srcPath . '/sample.go', <<<GO
+// Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.
+
+package sample
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func Sample() {
+	fmt.Println("outdated")
+	// protected region custom begin
+	log.Println(strings.ToUpper("kept"))
+	// protected region custom end
+}
+
+GO
+        );
+
+        $builder = new FilesCodeBuilder();
+        $builder->preserveRegions = true;
+        $builder->contents['sample.go'] = $this->sampleFile()->render();
+        $builder->storeToDisk($this->srcPath);
+
+        $this->assertSame(<<<GO
+// Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.
+
+package sample
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+func Sample() {
+	fmt.Println("generated")
+	// protected region custom begin
+	log.Println(strings.ToUpper("kept"))
+	// protected region custom end
+}
+
+GO
+            , file_get_contents($this->srcPath . '/sample.go'));
+    }
+
+    public function testDryRun()
+    {
+        $builder = new FilesCodeBuilder();
+        $builder->contents['doc.go'] = 'package sample';
+
+        $changeset = $builder->dryRun($this->srcPath);
+        $this->assertSame(array(), glob($this->srcPath . '/*'));
+        $this->assertSame('A doc.go', $changeset->summary());
+        $this->assertSame(<<<'DIFF'
+--- /dev/null
++++ b/doc.go
+@@ -0,0 +1 @@
++package sample
+\ No newline at end of file
+
+DIFF
+            , $changeset->diff());
+
+        try {
+            $builder->check($this->srcPath);
+            $this->fail('Exception expected');
+        } catch (Exception $exception) {
+            $this->assertStringStartsWith("Generated code is out of date:\nA doc.go\n", $exception->getMessage());
+        }
+
+        $builder->storeToDisk($this->srcPath);
+        $this->assertTrue($builder->check($this->srcPath)->isEmpty());
+
+        file_put_contents($this->srcPath . '/old.go', "package sample\n");
+        $builder->contents['doc.go'] = "package sample\n";
+        $changeset = $builder->dryRun($this->srcPath);
+        $this->assertFalse($changeset->isEmpty());
+        $this->assertSame("M doc.go\nD old.go", $changeset->summary());
+        $this->assertSame("package sample", file_get_contents($this->srcPath . '/doc.go'));
+    }
+
+    public function testStoreToDiskChecksum()
+    {
+        $goFile = $this->sampleFile();
+        $goFile->checksum = true;
+        $signed = $goFile->render();
+
+        $builder = new FilesCodeBuilder();
+        $builder->contents['sample.go'] = $signed;
+        $builder->storeToDisk($this->srcPath);
+        $builder->storeToDisk($this->srcPath);
+        $this->assertSame(array(), $builder->tamperedFiles);
+        $this->assertSame($signed, file_get_contents($this->srcPath . '/sample.go'));
+
+        file_put_contents($this->srcPath . '/sample.go', str_replace('"generated"', '"edited"', $signed));
+        try {
+            $builder->storeToDisk($this->srcPath);
+            $this->fail('Exception expected');
+        } catch (Exception $exception) {
+            $this->assertSame("Generated files were edited by hand, use force to overwrite:\nsample.go",
+                $exception->getMessage());
+        }
+        $this->assertContains('"edited"', file_get_contents($this->srcPath . '/sample.go'));
+
+        $builder->force = true;
+        $builder->storeToDisk($this->srcPath);
+        $this->assertSame(array('sample.go'), $builder->tamperedFiles);
+        $this->assertSame($signed, file_get_contents($this->srcPath . '/sample.go'));
+    }
+
+    /**
+     * @return GoFile
+     */
+    private function sampleFile()
+    {
+        $func = new FuncDef('Sample');
+        $code = new Code("fmt.Println(\"generated\")\n");
+        $code->imports()->add(new Import('fmt'));
+        $code->addSnippet(new ProtectedRegion('custom'));
+        $func->setBody($code);
+
+        $goFile = new GoFile('sample');
+        $goFile->setCode($func);
+
+        return $goFile;
+    }
+}
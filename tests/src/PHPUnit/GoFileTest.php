@@ -4,9 +4,15 @@ namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
 
 
 use Swaggest\GoCodeBuilder\Import;
+use Swaggest\GoCodeBuilder\Templates\Checksum;
 use Swaggest\GoCodeBuilder\Templates\Code;
 use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
 use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\GoCodeBuilder\Templates\ProtectedRegion;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\GoCodeBuilder\Templates\Struct\Tags;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
 
 class GoFileTest extends \PHPUnit_Framework_TestCase
 {
@@ -43,4 +49,192 @@ GO
 
     }
 
+    public function testImportAliases()
+    {
+        $struct = new StructDef('Sample', 'Sample is a sample structure');
+        $struct
+            ->addProperty(new StructProperty('A', TypeUtil::fromString('a.com/orders.Order'), (new Tags())->setTag('json', 'a')))
+            ->addProperty(new StructProperty('B', TypeUtil::fromString('b.com/orders.Order'), (new Tags())->setTag('json', 'b')))
+            ->addProperty(new StructProperty('C', TypeUtil::fromString('*example.com/sample.Item'), (new Tags())->setTag('json', 'c')));
+
+        $goFile = new GoFile('sample', 'github.com/acme/sample');
+        $goFile->setCode($struct);
+
+        $this->assertSame(<<<GO
+// Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.
+
+package sample // import "github.com/acme/sample"
+
+import (
+	"a.com/orders"
+	bcomorders "b.com/orders"
+	examplecomsample "example.com/sample"
+)
+
+// Sample is a sample structure
+type Sample struct {
+	A orders.Order           `json:"a"`
+	B bcomorders.Order       `json:"b"`
+	C *examplecomsample.Item `json:"c"`
+}
+
+GO
+            , $goFile->render());
+    }
+
+    public function testRenderTwice()
+    {
+        $struct = new StructDef('Sample', 'Sample is a sample structure');
+        $struct
+            ->addProperty(new StructProperty('A', TypeUtil::fromString('a.com/orders.Order'), (new Tags())->setTag('json', 'a')))
+            ->addProperty(new StructProperty('B', TypeUtil::fromString('b.com/orders.Order'), (new Tags())->setTag('json', 'b')));
+
+        $func = new FuncDef('Custom');
+        $func->setBody(new Code(new ProtectedRegion('custom')));
+
+        $goFile = new GoFile('sample');
+        $goFile->fileComment = '';
+        $goFile->setCode((new Code($struct))->addSnippet($func));
+        // Dependent code is kept when code is rendered again with aliases.
+        $goFile->setDependentCode('helper', "func helper() {}\n");
+        $goFile->preserveRegions(<<<GO
+package sample
+
+import (
+	"log"
+)
+
+func Custom() {
+	// protected region custom begin
+	log.Println("kept")
+	// protected region custom end
+}
+
+GO
+        );
+
+        $expected = <<<GO
+package sample
+
+import (
+	"log"
+
+	"a.com/orders"
+	bcomorders "b.com/orders"
+)
+
+// Sample is a sample structure
+type Sample struct {
+	A orders.Order     `json:"a"`
+	B bcomorders.Order `json:"b"`
+}
+
+func Custom() {
+	// protected region custom begin
+	log.Println("kept")
+	// protected region custom end
+}
+
+func helper() {}
+
+GO;
+
+        $this->assertSame($expected, $goFile->render());
+        $this->assertSame($expected, $goFile->render());
+        $this->assertSame(array(), $goFile->getImports()->imports);
+    }
+
+    public function testProtectedRegions()
+    {
+        $previous = <<<GO
+// Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.
+
+package sample
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func Sample() {
+	fmt.Println("outdated")
+	// protected region custom begin
+	log.Println(strings.ToUpper("kept"))
+	// protected region custom end
+}
+
+GO;
+
+        $func = new FuncDef('Sample');
+        $code = new Code("fmt.Println(\"generated\")\n");
+        $code->imports()->add(new Import('fmt'));
+        $code->addSnippet(new ProtectedRegion('custom'));
+        $func->setBody($code);
+
+        $goFile = new GoFile('sample');
+        $goFile->setCode($func);
+        $goFile->preserveRegions($previous);
+
+        $expected = <<<GO
+// Code generated by github.com/swaggest/go-code-builder, DO NOT EDIT.
+
+package sample
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+func Sample() {
+	fmt.Println("generated")
+	// protected region custom begin
+	log.Println(strings.ToUpper("kept"))
+	// protected region custom end
+}
+
+GO;
+
+        $this->assertSame($expected, $goFile->render());
+
+        $fresh = new GoFile('sample');
+        $fresh->setCode($func);
+        $this->assertSame($expected, ProtectedRegion::restore($fresh->render(), $previous));
+    }
+
+    public function testChecksum()
+    {
+        $func = new FuncDef('Sample');
+        $code = new Code("fmt.Println(\"generated\")\n");
+        $code->imports()->add(new Import('fmt'));
+        $code->addSnippet(new ProtectedRegion('custom'));
+        $func->setBody($code);
+
+        $goFile = new GoFile('sample');
+        $goFile->setCode($func);
+        $goFile->checksum = true;
+        $goFile->generatorVersion = 'v1.2.3';
+
+        $signed = $goFile->render();
+        $this->assertRegExp('/\A\/\/ Code generated by github.com\/swaggest\/go-code-builder, DO NOT EDIT.\n'
+            . '\/\/ Generator: v1\.2\.3\n\/\/ Checksum: sha256:[0-9a-f]{64}\n\npackage sample\n/', $signed);
+        $this->assertTrue(Checksum::verify($signed));
+
+        $edited = str_replace('"generated"', '"edited"', $signed);
+        $this->assertFalse(Checksum::verify($edited));
+
+        $regionEdited = str_replace("\t// protected region custom end",
+            "\tlog.Println(\"kept\")\n\t// protected region custom end", $signed);
+        $regionEdited = str_replace("\t\"fmt\"\n", "\t\"fmt\"\n\t\"log\"\n", $regionEdited);
+        $this->assertNotSame($signed, $regionEdited);
+        $this->assertTrue(Checksum::verify($regionEdited));
+
+        $importEdited = str_replace("\t\"fmt\"\n", "\tfmt \"example.com/fmt\"\n", $signed);
+        $this->assertFalse(Checksum::verify($importEdited));
+
+        $goFile->checksum = false;
+        $this->assertNull(Checksum::verify($goFile->render()));
+    }
 }
\ No newline at end of file
@@ -0,0 +1,71 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
+
+
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\GoCodeBuilder\Templates\GoFmt;
+
+class GoFmtTest extends \PHPUnit_Framework_TestCase
+{
+    /**
+     * Golden files are made with `gofmt < name.input.txt > name.golden.txt`.
+     *
+     * @dataProvider goldenProvider
+     * @param string $name
+     */
+    public function testGolden($name)
+    {
+        $dir = __DIR__ . '/../../resources/gofmt/';
+        $this->assertSame(
+            file_get_contents($dir . $name . '.golden.txt'),
+            GoFmt::format(file_get_contents($dir . $name . '.input.txt'))
+        );
+    }
+
+    public function goldenProvider()
+    {
+        $result = array();
+        foreach (glob(__DIR__ . '/../../resources/gofmt/*.input.txt') as $file) {
+            $name = basename($file, '.input.txt');
+            $result[$name] = array($name);
+        }
+        return $result;
+    }
+
+    public function testIdempotent()
+    {
+        $golden = file_get_contents(__DIR__ . '/../../resources/gofmt/declarations.golden.txt');
+        $this->assertSame($golden, GoFmt::format($golden));
+    }
+
+    public function testGoFile()
+    {
+        $func = new FuncDef('Sample');
+        $func->setBody(new Code(<<<GO
+if true {
+    fmt.Println("Hello world") // Greeting.
+}
+GO
+        ));
+
+        $goFile = new GoFile('my_package');
+        $goFile->fileComment = '';
+        $goFile->gofmt = true;
+        $goFile->setCode($func);
+
+        $this->assertSame(<<<GO
+package my_package
+
+func Sample() {
+	if true {
+		fmt.Println("Hello world") // Greeting.
+	}
+}
+
+GO
+            , $goFile->render());
+    }
+}
\ No newline at end of file
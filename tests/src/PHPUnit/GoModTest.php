@@ -0,0 +1,95 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
+
+
+use Swaggest\GoCodeBuilder\GoCodeBuilder;
+use Swaggest\GoCodeBuilder\GoMod;
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\GoVersion;
+use Swaggest\GoCodeBuilder\JsonSchema\WarningHookCallback;
+use Swaggest\JsonSchema\Schema;
+
+class GoModTest extends \PHPUnit_Framework_TestCase
+{
+    private $dir;
+
+    protected function setUp()
+    {
+        $this->dir = sys_get_temp_dir() . DIRECTORY_SEPARATOR . uniqid('go-mod-test');
+        mkdir($this->dir . '/internal/orders', 0777, true);
+        file_put_contents($this->dir . '/go.mod', <<<'MOD'
+// Service module.
+module github.com/acme/service/v2
+
+go 1.21
+
+require github.com/google/uuid v1.6.0
+
+MOD
+        );
+    }
+
+    protected function tearDown()
+    {
+        rmdir($this->dir . '/internal/orders');
+        rmdir($this->dir . '/internal');
+        unlink($this->dir . '/go.mod');
+        rmdir($this->dir);
+    }
+
+    public function testFind()
+    {
+        $goMod = GoMod::find($this->dir . '/internal/entities/generated');
+        $this->assertSame('github.com/acme/service/v2', $goMod->modulePath);
+        $this->assertSame('1.21', $goMod->goVersion);
+        $this->assertSame(realpath($this->dir), $goMod->dir);
+
+        $this->assertSame('github.com/acme/service/v2', $goMod->importPath($this->dir));
+        $this->assertSame('github.com/acme/service/v2/internal/entities',
+            $goMod->importPath($this->dir . '/internal/../internal/entities'));
+
+        $this->assertNull($goMod->mismatchingImportPath('github.com/acme/service/v2/internal/orders'));
+        $this->assertNull($goMod->mismatchingImportPath('github.com/google/uuid'));
+        // Packages of other owners are not checked even if module has a directory with the same name.
+        $this->assertNull($goMod->mismatchingImportPath('github.com/google/orders/internal/orders'));
+        $this->assertNull($goMod->mismatchingImportPath('gitlab.com/acme/service/internal/orders'));
+        $this->assertSame('github.com/acme/service/v2/internal/orders',
+            $goMod->mismatchingImportPath('github.com/acme/service/internal/orders'));
+    }
+
+    public function testCreateGoFile()
+    {
+        $codeBuilder = new GoCodeBuilder();
+        $goFile = $codeBuilder->createGoFile($this->dir . '/internal/entities');
+        $this->assertSame('entities', $goFile->getPackage());
+        $this->assertSame('github.com/acme/service/v2/internal/entities', $goFile->getImportPath());
+
+        $goFile = $codeBuilder->createGoFile($this->dir);
+        $this->assertSame('service', $goFile->getPackage());
+    }
+
+    public function testGoBuilder()
+    {
+        $builder = new GoBuilder();
+        $this->assertFalse($builder->supports(GoVersion::ERRORS_JOIN));
+
+        $builder->codeBuilder->readGoMod($this->dir . '/internal/entities');
+        $this->assertTrue($builder->supports(GoVersion::ERRORS_JOIN));
+        $this->assertFalse($builder->supports(GoVersion::OMIT_ZERO));
+
+        $warnings = array();
+        $builder->warningHook = new WarningHookCallback(function ($message, $path) use (&$warnings) {
+            $warnings[] = $path . ': ' . $message;
+        });
+
+        $order = Schema::object();
+        $order->{'x-go-type'} = 'github.com/acme/service/internal/orders.Order';
+        $builder->getType(Schema::object()->setProperty('order', $order));
+
+        $this->assertSame(array(
+            '#->order: x-go-type imports "github.com/acme/service/internal/orders", but package of module '
+            . 'github.com/acme/service/v2 is "github.com/acme/service/v2/internal/orders"'
+        ), $warnings);
+    }
+}
\ No newline at end of file
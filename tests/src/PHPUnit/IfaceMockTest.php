@@ -0,0 +1,237 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
+
+
+use Swaggest\GoCodeBuilder\Exception;
+use Swaggest\GoCodeBuilder\Templates\Func\Arguments;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceDef;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceMock;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceUnimplemented;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
+use Swaggest\GoCodeBuilder\Templates\Type\Slice;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
+
+class IfaceMockTest extends \PHPUnit_Framework_TestCase
+{
+    private function store()
+    {
+        $get = new FuncDef('Get');
+        $get->setArguments((new Arguments())
+            ->add('ctx', TypeUtil::fromString('context.Context'))
+            ->add('id', new Type('string')));
+        $get->setResult((new Result())
+            ->add(null, new Pointer(new Type('Order')))
+            ->add(null, new Type('error')));
+
+        $list = new FuncDef('List');
+        $list->setArguments((new Arguments())->add('opts', new Type('Option'), true));
+        $list->setResult((new Result())->add(null, new Slice(new Type('Order'))));
+
+        $iface = new IfaceDef('Store');
+        $iface->addFunc($get)->addFunc($list)->addFunc(new FuncDef('Close'));
+
+        return $iface;
+    }
+
+    public function testMock()
+    {
+        $iface = $this->store();
+        $mock = new IfaceMock($iface);
+        $mock->setPanicOnNil(false);
+
+        $goFile = new GoFile('sample');
+        $goFile->fileComment = '';
+        $goFile->getCode()
+            ->addSnippet($mock)
+            ->addSnippet(new IfaceUnimplemented($iface));
+
+        $this->assertSame(<<<'GO'
+package sample
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Ensure that StoreMock does implement Store.
+var _ Store = &StoreMock{}
+
+// StoreMock is a mock implementation of Store.
+type StoreMock struct {
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, id string) (*Order, error)
+
+	// ListFunc mocks the List method.
+	ListFunc func(opts ...Option) []Order
+
+	// CloseFunc mocks the Close method.
+	CloseFunc func()
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			Ctx context.Context
+			ID  string
+		}
+
+		// List holds details about calls to the List method.
+		List []struct {
+			Opts []Option
+		}
+
+		// Close holds details about calls to the Close method.
+		Close []struct{}
+	}
+	lockGet   sync.RWMutex
+	lockList  sync.RWMutex
+	lockClose sync.RWMutex
+}
+
+// Get calls GetFunc.
+func (mock *StoreMock) Get(ctx context.Context, id string) (*Order, error) {
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	if mock.GetFunc == nil {
+		var r0 *Order
+		var r1 error
+		return r0, r1
+	}
+	return mock.GetFunc(ctx, id)
+}
+
+// GetCalls gets all the calls that were made to Get.
+func (mock *StoreMock) GetCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// List calls ListFunc.
+func (mock *StoreMock) List(opts ...Option) []Order {
+	callInfo := struct {
+		Opts []Option
+	}{
+		Opts: opts,
+	}
+	mock.lockList.Lock()
+	mock.calls.List = append(mock.calls.List, callInfo)
+	mock.lockList.Unlock()
+	if mock.ListFunc == nil {
+		var r0 []Order
+		return r0
+	}
+	return mock.ListFunc(opts...)
+}
+
+// ListCalls gets all the calls that were made to List.
+func (mock *StoreMock) ListCalls() []struct {
+	Opts []Option
+} {
+	var calls []struct {
+		Opts []Option
+	}
+	mock.lockList.RLock()
+	calls = mock.calls.List
+	mock.lockList.RUnlock()
+	return calls
+}
+
+// Close calls CloseFunc.
+func (mock *StoreMock) Close() {
+	callInfo := struct{}{}
+	mock.lockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	mock.lockClose.Unlock()
+	if mock.CloseFunc == nil {
+		return
+	}
+	mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+func (mock *StoreMock) CloseCalls() []struct{} {
+	var calls []struct{}
+	mock.lockClose.RLock()
+	calls = mock.calls.Close
+	mock.lockClose.RUnlock()
+	return calls
+}
+
+// UnimplementedStore can be embedded to have forward compatible implementation of Store.
+type UnimplementedStore struct{}
+
+// Get implements Store.
+func (UnimplementedStore) Get(ctx context.Context, id string) (*Order, error) {
+	var r0 *Order
+	return r0, errors.New("Store.Get is not implemented")
+}
+
+// List implements Store.
+func (UnimplementedStore) List(opts ...Option) []Order {
+	var r0 []Order
+	return r0
+}
+
+// Close implements Store.
+func (UnimplementedStore) Close() {
+}
+
+GO
+            , $goFile->render());
+    }
+
+    public function testMockPanicsOnNil()
+    {
+        $goFile = new GoFile('sample');
+        $goFile->getCode()->addSnippet(new IfaceMock($this->store(), 'FakeStore'));
+
+        $this->assertContains(<<<'GO'
+func (mock *FakeStore) Close() {
+	if mock.CloseFunc == nil {
+		panic("FakeStore.CloseFunc: method is nil but Store.Close was just called")
+	}
+	callInfo := struct{}{}
+GO
+            , $goFile->render());
+    }
+
+    public function testEmbeddedInterface()
+    {
+        $iface = $this->store();
+        $iface->addType(TypeUtil::fromString('io.Closer'));
+
+        try {
+            new IfaceMock($iface);
+            $this->fail('Exception expected');
+        } catch (Exception $exception) {
+            $this->assertSame('Interface Store embeds io.Closer, add methods of embedded interfaces with addFunc instead',
+                $exception->getMessage());
+        }
+
+        $this->setExpectedException('Swaggest\GoCodeBuilder\Exception', 'Interface Store embeds io.Closer');
+        new IfaceUnimplemented($iface);
+    }
+}
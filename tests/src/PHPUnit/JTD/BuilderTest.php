@@ -0,0 +1,98 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\JTD;
+
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JTD\Builder;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class BuilderTest extends \PHPUnit_Framework_TestCase
+{
+    public function testBuilder()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/jtd-user.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, 'user', $goBuilder);
+        $type = $builder->build();
+        $this->assertSame('User', $type->getTypeString());
+
+        $goFile = new GoFile('jtd');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+
+        $filePath = __DIR__ . '/../../../resources/go/jtd/user.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testSchemaData()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "discriminator": "kind",
+  "mapping": {
+    "circle": {"properties": {"radius": {"type": "float64"}}},
+    "square": {"optionalProperties": {"side": {"type": "uint16", "nullable": true}}}
+  },
+  "definitions": {
+    "label": {"type": "string"},
+    "owner": {"properties": {"label": {"ref": "label", "nullable": true}}}
+  }
+}
+JSON
+        );
+
+        $builder = new Builder($document, 'shape');
+        $schemaData = json_decode(json_encode($builder->getSchemaData()), true);
+
+        $this->assertSame(
+            array('oneOf' => array(array('$ref' => '#/variants/ShapeCircle'), array('$ref' => '#/variants/ShapeSquare'))),
+            $schemaData['definitions']['shape']
+        );
+        $this->assertSame(
+            array('anyOf' => array(array('$ref' => '#/definitions/label')), 'x-nullable' => true),
+            $schemaData['definitions']['owner']['properties']['label']
+        );
+        $this->assertSame(
+            array(
+                'type' => 'object',
+                'properties' => array(
+                    'kind' => array('type' => 'string', 'enum' => array('circle')),
+                    'radius' => array('type' => 'number'),
+                ),
+                'required' => array('kind', 'radius'),
+                'additionalProperties' => false,
+            ),
+            $schemaData['variants']['ShapeCircle']
+        );
+        $this->assertSame(
+            array('type' => 'integer', 'minimum' => 0, 'maximum' => 65535, 'x-go-type' => '*uint16', 'x-nullable' => true),
+            $schemaData['variants']['ShapeSquare']['properties']['side']
+        );
+        $this->assertSame(array('kind'), $schemaData['variants']['ShapeSquare']['required']);
+    }
+
+    public function testMissingDefinition()
+    {
+        $this->setExpectedException('Swaggest\GoCodeBuilder\JTD\Exception', 'Missing definition address at #/properties/home');
+        new Builder(json_decode('{"properties": {"home": {"ref": "address"}}}'), 'user');
+    }
+
+    public function testUnknownType()
+    {
+        $this->setExpectedException('Swaggest\GoCodeBuilder\JTD\Exception', 'Unknown type int64 at #/elements');
+        new Builder(json_decode('{"elements": {"type": "int64"}}'), 'ids');
+    }
+}
\ No newline at end of file
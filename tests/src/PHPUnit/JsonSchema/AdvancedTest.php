@@ -4,6 +4,7 @@ namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\JsonSchema;
 
 
 use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\SchemaTagsTestFunc;
 use Swaggest\GoCodeBuilder\JsonSchema\StructHookCallback;
 use Swaggest\GoCodeBuilder\Templates\GoFile;
 use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
@@ -197,4 +198,97 @@ JSON;
         $out = implode("\n", $out);
         $this->assertSame('', $out, "Generated files changed");
     }
+
+    public function testJsonSchemaTags()
+    {
+        $schemaData = <<<'JSON'
+{
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+        "name": {"type": "string", "minLength": 3, "maxLength": 20},
+        "age": {"type": "integer", "minimum": 18, "maximum": 130},
+        "score": {"type": "number", "multipleOf": 0.5, "default": 1}
+    }
+}
+JSON;
+        $schema = Schema::import(json_decode($schemaData));
+        $builder = new GoBuilder();
+        $builder->options->defaultAdditionalProperties = false;
+        $builder->options->validateRequired = false;
+        $builder->options->jsonSchemaTags = true;
+        $builder->structCreatedHook = new StructHookCallback(function (StructDef $structDef, $path) {
+            if ('#' === $path) {
+                $structDef->setName('Account');
+            }
+        });
+        $builder->getType($schema);
+
+        $goFile = new GoFile('entities');
+        $goFile->fileComment = '';
+        $goFile->setComment('Package entities contains generated structures.');
+
+        // Schema reflected from generated structures with github.com/swaggest/jsonschema-go is checked by tests.
+        $goTestFile = new GoFile('entities_test');
+        $goTestFile->setPackage('entities');
+        $goTestFile->fileComment = '';
+
+        foreach ($builder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+
+            $testFunc = SchemaTagsTestFunc::make($generatedStruct);
+            if ($testFunc !== null) {
+                $goTestFile->getCode()->addSnippet($testFunc);
+            }
+        }
+        $goFile->getCode()->addSnippet($builder->getCode());
+
+        $path = __DIR__ . '/../../../resources/go/advanced/json-schema-tags';
+        file_put_contents($path . '/entities.go', $goFile->render());
+        file_put_contents($path . '/entities_test.go', $goTestFile->render());
+
+        exec('git diff ' . $path, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testYamlMarshaling()
+    {
+        $schemaData = <<<'JSON'
+{
+    "type": "object",
+    "properties": {
+        "name": {"type": "string"},
+        "kind": {"type": "string", "enum": ["alpha", "beta"]},
+        "count": {"type": "integer"}
+    },
+    "additionalProperties": {"type": "string"}
+}
+JSON;
+        $schema = Schema::import(json_decode($schemaData));
+        $builder = new GoBuilder();
+        $builder->options->yamlMarshaling = true;
+        $builder->structCreatedHook = new StructHookCallback(function (StructDef $structDef, $path) {
+            if ('#' === $path) {
+                $structDef->setName('Config');
+            }
+        });
+        $builder->getType($schema);
+
+        $goFile = new GoFile('entities');
+        $goFile->fileComment = '';
+        $goFile->setComment('Package entities contains generated structures.');
+        foreach ($builder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($builder->getCode());
+
+        // YAML round trips are checked by entities_yaml_test.go.
+        $filePath = __DIR__ . '/../../../resources/go/advanced/yaml/entities.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
 }
\ No newline at end of file
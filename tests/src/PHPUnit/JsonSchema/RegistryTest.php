@@ -0,0 +1,87 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\JsonSchema;
+
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\Registry;
+use Swaggest\GoCodeBuilder\JsonSchema\StructHookCallback;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\JsonSchema\Schema;
+
+class RegistryTest extends \PHPUnit_Framework_TestCase
+{
+    public function testRegistry()
+    {
+        $schema = Schema::import(json_decode(<<<'JSON'
+{
+  "title": "Pets",
+  "type": "object",
+  "properties": {
+    "cat": {"$ref": "#/definitions/cat"},
+    "dog": {"$ref": "#/definitions/dog"}
+  },
+  "definitions": {
+    "cat": {
+      "$id": "https://example.com/schemas/animals/domestic/cat.json",
+      "title": "Cat",
+      "type": "object",
+      "properties": {"kind": {"const": "cat"}, "name": {"type": "string"}}
+    },
+    "dog": {
+      "title": "Dog",
+      "type": "object",
+      "properties": {"kind": {"type": "string", "enum": ["dog"]}}
+    }
+  }
+}
+JSON
+        ));
+
+        $builder = new GoBuilder();
+        $builder->structCreatedHook = new StructHookCallback(function (StructDef $structDef, $path, $schema) {
+            $structDef->setName($schema->title);
+        });
+        $builder->getType($schema);
+
+        $registry = new Registry($builder);
+        $registry->setDiscriminator('kind');
+        $registry->add('dog', new Type('Animal'));
+
+        $this->assertSame(<<<'GO'
+// Registry maps identifiers to factories of generated types.
+var Registry = map[string]func() interface{}{
+	"dog": func() interface{} { return new(Animal) },
+	"https://example.com/schemas/animals/domestic/cat.json": func() interface{} { return new(Cat) },
+	"cat": func() interface{} { return new(Cat) },
+}
+
+// Decode decodes JSON data into a new instance of type registered with id.
+//
+// Result is a pointer to registered type.
+func Decode(id string, data []byte) (interface{}, error) {
+	factory, ok := Registry[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown type id %q", id)
+	}
+
+	v := factory()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+
+GO
+            , $registry->render());
+    }
+
+    public function testEmpty()
+    {
+        $registry = new Registry(new GoBuilder());
+        $this->assertSame('', $registry->render());
+    }
+}
\ No newline at end of file
@@ -3,6 +3,8 @@
 namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\JsonSchema;
 
 use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\KeywordHookCallback;
+use Swaggest\GoCodeBuilder\JsonSchema\SchemaTagsTestFunc;
 use Swaggest\JsonSchema\Schema;
 
 class SchemaTest extends \PHPUnit_Framework_TestCase
@@ -253,4 +255,325 @@ GO;
         $this->assertSame($expectedStructs, $actualStructs);
 
     }
+
+    public function testStrictUnmarshal()
+    {
+        $builder = new GoBuilder();
+        $builder->options->strictUnmarshal = true;
+
+        $schema = Schema::object()->setProperty('name', Schema::string());
+
+        $expectedStructs = <<<'GO'
+// Untitled1 structure is generated from "#".
+type Untitled1 struct {
+	Name string `json:"name,omitempty"`
+}
+
+type marshalUntitled1 Untitled1
+
+var knownKeysUntitled1 = []string{
+	"name",
+}
+
+// UnmarshalJSON decodes JSON.
+func (u *Untitled1) UnmarshalJSON(data []byte) error {
+	var err error
+
+	mu := marshalUntitled1(*u)
+
+	err = json.Unmarshal(data, &mu)
+	if err != nil {
+		return err
+	}
+
+	var rawMap map[string]json.RawMessage
+
+	err = json.Unmarshal(data, &rawMap)
+	if err != nil {
+		rawMap = nil
+	}
+
+	for _, key := range knownKeysUntitled1 {
+		delete(rawMap, key)
+	}
+
+	if len(rawMap) != 0 {
+		offendingKeys := make([]string, 0, len(rawMap))
+
+		for key := range rawMap {
+			offendingKeys = append(offendingKeys, key)
+		}
+
+		return fmt.Errorf("additional properties not allowed in Untitled1: %v", offendingKeys)
+	}
+
+	*u = Untitled1(mu)
+
+	return nil
+}
+
+
+
+GO;
+
+        $builder->getType($schema);
+        $actualStructs = '';
+        foreach ($builder->getGeneratedStructs() as $class) {
+            $actualStructs .= $class->structDef;
+        }
+
+        $this->assertSame($expectedStructs, $actualStructs);
+    }
+
+    public function testStrictUnmarshalPaths()
+    {
+        $builder = new GoBuilder();
+        $builder->options->defaultAdditionalProperties = false;
+        $builder->options->strictUnmarshalPaths = ['#->another'];
+
+        $schema = Schema::object()
+            ->setProperty('sampleInt', Schema::integer())
+            ->setProperty('another', Schema::object()->setProperty('hello', Schema::boolean()));
+
+        $builder->getType($schema);
+        $structs = $builder->getGeneratedStructs();
+
+        $this->assertSame('', (string)$structs['#']->marshalJson);
+        $this->assertContains('additional properties not allowed in Another', (string)$structs['#->another']->marshalJson);
+    }
+
+    public function testStrictUnmarshalAllOf()
+    {
+        $builder = new GoBuilder();
+        $builder->options->defaultAdditionalProperties = false;
+        $builder->options->strictUnmarshalPaths = ['#/definitions/order'];
+
+        $schema = Schema::import(json_decode(<<<'JSON'
+{
+    "type": "object",
+    "properties": {
+        "order": {"$ref": "#/definitions/order"},
+        "base": {"$ref": "#/definitions/base"}
+    },
+    "definitions": {
+        "base": {"type": "object", "properties": {"id": {"type": "string"}}},
+        "order": {
+            "allOf": [
+                {"$ref": "#/definitions/base"},
+                {"type": "object", "properties": {"total": {"type": "number"}}}
+            ]
+        }
+    }
+}
+JSON
+        ));
+
+        $builder->getType($schema);
+        $structs = $builder->getGeneratedStructs();
+
+        // Shared part is not affected by strictness of embedding structure.
+        $this->assertSame('', (string)$structs['#/definitions/base']->marshalJson);
+
+        $order = (string)$structs['#/definitions/order']->marshalJson;
+        $this->assertContains(<<<'GO'
+var knownKeysOrder = []string{
+	"id",
+	"total",
+}
+GO
+            , $order);
+        $this->assertContains('err = unmarshalPart(data, &o.Base, "id")', $order);
+        $this->assertContains('err = unmarshalPart(data, &o.OrderAllOf1, "total")', $order);
+        $this->assertContains('additional properties not allowed in Order', $order);
+        $this->assertContains('func unmarshalPart(data []byte, v interface{}, keys ...string) error {',
+            (string)$builder->getCode());
+    }
+
+    public function testJsonSchemaTags()
+    {
+        $builder = new GoBuilder();
+        $builder->options->jsonSchemaTags = true;
+        $builder->options->defaultAdditionalProperties = false;
+        $builder->options->validateRequired = false;
+
+        $schema = Schema::import(json_decode(<<<'JSON'
+{
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+        "name": {"type": "string", "minLength": 3, "pattern": "^[a-z]+\\d$"},
+        "count": {"type": "integer", "minimum": 1, "exclusiveMaximum": 10}
+    }
+}
+JSON
+        ));
+
+        $builder->getType($schema);
+        $struct = $builder->getGeneratedStructs()['#'];
+        $properties = $struct->structDef->getProperties();
+
+        $this->assertSame(<<<'TAGS'
+`json:"name" required:"true" pattern:"^[a-z]+\\d$" minLength:"3"`
+TAGS
+            , $properties['Name']->getTags()->render());
+        $this->assertSame('`json:"count,omitempty" minimum:"1" exclusiveMaximum:"10"`',
+            $properties['Count']->getTags()->render());
+
+        $testFunc = SchemaTagsTestFunc::make($struct)->render();
+        $this->assertContains('func TestUntitled1_JSONSchema(t *testing.T) {', $testFunc);
+        $this->assertContains('"required":["name"]', $testFunc);
+        $this->assertContains('"count":{"minimum":1,"exclusiveMaximum":10}', $testFunc);
+    }
+
+    public function testValidateTags()
+    {
+        $builder = new GoBuilder();
+        $builder->options->validateTags = true;
+        $builder->options->defaultAdditionalProperties = false;
+        $builder->options->validateRequired = false;
+
+        $unsupported = [];
+        $builder->unsupportedKeywordHook = new KeywordHookCallback(function ($keyword, $path) use (&$unsupported) {
+            $unsupported[] = $path . ':' . $keyword;
+        });
+
+        $schema = Schema::import(json_decode(<<<'JSON'
+{
+    "type": "object",
+    "required": ["email"],
+    "properties": {
+        "email": {"type": "string", "format": "email", "maxLength": 100},
+        "age": {"type": "integer", "minimum": 18, "exclusiveMaximum": 130},
+        "tags": {"type": "array", "items": {"type": "string", "minLength": 1}, "uniqueItems": true},
+        "code": {"type": "string", "pattern": "^[A-Z]+$"},
+        "kind": {"type": "string", "enum": ["a", "b"]}
+    }
+}
+JSON
+        ));
+
+        $builder->getType($schema);
+        $properties = $builder->getGeneratedStructs()['#']->structDef->getProperties();
+
+        $this->assertSame('`json:"email" validate:"required,max=100,email"`', $properties['Email']->getTags()->render());
+        $this->assertSame('`json:"age,omitempty" validate:"omitempty,gte=18,lt=130"`', $properties['Age']->getTags()->render());
+        $this->assertSame('`json:"tags,omitempty" validate:"omitempty,unique,dive,min=1"`', $properties['Tags']->getTags()->render());
+        $this->assertSame('`json:"code,omitempty"`', $properties['Code']->getTags()->render());
+        $this->assertSame('`json:"kind,omitempty" validate:"omitempty,oneof=a b"`', $properties['Kind']->getTags()->render());
+        $this->assertSame(['#->code:pattern'], $unsupported);
+    }
+
+    public function testMirrorTags()
+    {
+        $builder = new GoBuilder();
+        $builder->options->mirrorTags = ['yaml', 'bson', 'mapstructure', 'db'];
+        $builder->options->defaultAdditionalProperties = false;
+        $builder->options->validateRequired = false;
+
+        $schema = Schema::import(json_decode(<<<'JSON'
+{
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+        "name": {"type": "string"},
+        "note": {"type": "string", "x-go-tags": {"yaml": "note,flow", "db": null}},
+        "embedded": {
+            "allOf": [
+                {"type": "object", "properties": {"a": {"type": "string"}}},
+                {"type": "object", "properties": {"b": {"type": "string"}}}
+            ]
+        }
+    }
+}
+JSON
+        ));
+
+        $builder->getType($schema);
+        $structs = $builder->getGeneratedStructs();
+        $properties = $structs['#']->structDef->getProperties();
+
+        $this->assertSame('`json:"name" yaml:"name" bson:"name" mapstructure:"name" db:"name"`',
+            $properties['Name']->getTags()->render());
+        $this->assertSame('`json:"note,omitempty" yaml:"note,flow" bson:"note,omitempty" mapstructure:"note,omitempty"`',
+            $properties['Note']->getTags()->render());
+
+        foreach ($structs['#->embedded']->structDef->getProperties() as $property) {
+            $this->assertTrue($property->isEmbedded());
+            $this->assertSame('`yaml:",inline" bson:",inline" mapstructure:",squash"`', $property->getTags()->render());
+        }
+    }
+
+    public function testYamlMarshaling()
+    {
+        $builder = new GoBuilder();
+        $builder->options->yamlMarshaling = true;
+        $builder->options->defaultAdditionalProperties = false;
+
+        $kind = Schema::string();
+        $kind->enum = ['a', 'b'];
+        $schema = Schema::object()->setProperty('kind', $kind);
+
+        $builder->getType($schema);
+        $structCode = (string)$builder->getGeneratedStructs()['#']->structDef;
+        $builderCode = (string)$builder->getCode();
+
+        $this->assertContains(<<<'GO'
+// UnmarshalYAML decodes YAML node with JSON semantics.
+func (u *Untitled1) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAML(node, u)
+}
+
+// MarshalYAML encodes YAML node with JSON semantics.
+func (u Untitled1) MarshalYAML() (interface{}, error) {
+	return marshalYAML(u)
+}
+GO
+            , $structCode);
+
+        // Enum type also delegates to JSON marshaling that validates value.
+        $this->assertSame(1, substr_count($builderCode, ') UnmarshalYAML(node *yaml.Node) error {'));
+        $this->assertSame(1, substr_count($builderCode, ') MarshalYAML() (interface{}, error) {'));
+        $this->assertContains('func unmarshalYAML(node *yaml.Node, v interface{}) error {', $builderCode);
+        $this->assertContains('func marshalYAML(v interface{}) (interface{}, error) {', $builderCode);
+    }
+
+    public function testGoVersion()
+    {
+        $render = function ($goVersion) {
+            $builder = new GoBuilder();
+            $builder->options->goVersion = $goVersion;
+
+            $meta = Schema::object()->setProperty('id', Schema::string());
+            $choice = new Schema();
+            $choice->oneOf = [Schema::string(), Schema::integer()];
+
+            $schema = Schema::object()
+                ->setProperty('meta', $meta)
+                ->setProperty('extra', new Schema())
+                ->setProperty('choice', $choice);
+            $schema->additionalProperties = false;
+
+            $builder->getType($schema);
+            $code = '';
+            foreach ($builder->getGeneratedStructs() as $generatedStruct) {
+                $code .= $generatedStruct->structDef;
+            }
+            return $code;
+        };
+
+        $legacy = $render(null);
+        $this->assertRegExp('/Meta +\*\w+ +`json:"meta,omitempty"`/', $legacy);
+        $this->assertRegExp('/Extra +interface\{\} +`json:"extra,omitempty"`/', $legacy);
+        $this->assertContains('oneOfErrors := make(map[string]error, 2)', $legacy);
+        $this->assertContains('offendingKeys := make([]string, 0, len(rawMap))', $legacy);
+        $this->assertSame($legacy, $render('1.17'));
+
+        $modern = $render('go1.24');
+        $this->assertRegExp('/Meta +\w+ +`json:"meta,omitzero"`/', $modern);
+        $this->assertRegExp('/Extra +any +`json:"extra,omitempty"`/', $modern);
+        $this->assertContains('oneOfErrors = append(oneOfErrors, err)', $modern);
+        $this->assertContains('errors.Join(oneOfErrors...)', $modern);
+        $this->assertContains('slices.Sorted(maps.Keys(rawMap))', $modern);
+        $this->assertNotContains('interface{}', $modern);
+    }
 }
\ No newline at end of file
@@ -0,0 +1,132 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\OpenAPI3;
+
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\WarningHookCallback;
+use Swaggest\GoCodeBuilder\OpenAPI3\Builder;
+use Swaggest\GoCodeBuilder\OpenAPI3\Client;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class ClientTest extends \PHPUnit_Framework_TestCase
+{
+    public function testClient()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/openapi3-orders.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $goFile = new GoFile('client');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+        $goFile->getCode()->addSnippet($builder->getCode());
+        $goFile->getCode()->addSnippet(new Client($builder));
+
+        $filePath = __DIR__ . '/../../../resources/go/openapi3-client/client.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testFormRequestBody()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "openapi": "3.0.3",
+  "info": {"title": "Uploads", "version": "1.0.0"},
+  "paths": {
+    "/uploads": {
+      "post": {
+        "operationId": "createUpload",
+        "requestBody": {
+          "content": {
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["title"],
+                "properties": {
+                  "title": {"type": "string", "description": "Title of upload."},
+                  "file": {"type": "string", "format": "binary"},
+                  "tags": {"type": "array", "items": {"type": "string"}}
+                }
+              }
+            }
+          }
+        },
+        "responses": {"204": {"description": "Created"}}
+      }
+    },
+    "/notes": {
+      "put": {
+        "operationId": "putNote",
+        "requestBody": {"content": {"text/plain": {"schema": {"type": "string"}}}},
+        "responses": {"204": {"description": "Updated"}}
+      }
+    }
+  }
+}
+JSON
+        );
+
+        $warnings = array();
+        $goBuilder = new GoBuilder();
+        $goBuilder->warningHook = new WarningHookCallback(function ($message, $path) use (&$warnings) {
+            $warnings[] = $path . ': ' . $message;
+        });
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $operations = $builder->getOperations();
+        $this->assertCount(2, $operations);
+
+        $upload = $operations[0];
+        $this->assertTrue($upload->isMultipart());
+        $parameters = $upload->parametersIn('formData');
+        $this->assertCount(3, $parameters);
+        $this->assertSame('string', $parameters[0]->type->render());
+        $this->assertTrue($parameters[0]->required);
+        $this->assertTrue($parameters[1]->file);
+        $this->assertSame('io.Reader', $parameters[1]->type->render());
+        $this->assertSame('[]string', $parameters[2]->type->render());
+
+        $this->assertNull($operations[1]->bodyContentType);
+        $this->assertSame(array(
+            '#/paths/~1notes/put/requestBody/content: Request body of PutNote is skipped, media types text/plain are not supported',
+        ), $warnings);
+    }
+
+    public function testMissingPathParameter()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "openapi": "3.0.3",
+  "info": {"title": "Broken", "version": "1.0.0"},
+  "paths": {
+    "/items/{itemId}": {
+      "get": {"responses": {"204": {"description": "OK"}}}
+    }
+  }
+}
+JSON
+        );
+
+        $this->setExpectedException(
+            'Swaggest\GoCodeBuilder\OpenAPI3\Exception',
+            'Missing path parameter "itemId" in GET /items/{itemId}'
+        );
+        (new Builder($document))->build();
+    }
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\OpenAPI3;
+
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\OpenAPI3\Builder;
+use Swaggest\GoCodeBuilder\OpenAPI3\Server;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class ServerTest extends \PHPUnit_Framework_TestCase
+{
+    public function testServer()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/openapi3-orders.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $goFile = new GoFile('server');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+        $goFile->getCode()->addSnippet($builder->getCode());
+        $goFile->getCode()->addSnippet(new Server($builder));
+
+        $filePath = __DIR__ . '/../../../resources/go/openapi3-server/server.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testIfaces()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "openapi": "3.0.3",
+  "info": {"title": "Shop", "version": "1.0.0"},
+  "paths": {
+    "/orders": {"get": {"operationId": "listOrders", "tags": ["orders"], "responses": {"204": {"description": "OK"}}}},
+    "/users": {"get": {"operationId": "listUsers", "tags": ["user accounts"], "responses": {"204": {"description": "OK"}}}},
+    "/health": {"get": {"operationId": "health", "responses": {"204": {"description": "OK"}}}}
+  }
+}
+JSON
+        );
+
+        $builder = new Builder($document);
+        $builder->build();
+
+        $server = new Server($builder);
+        $this->assertSame(array('OrdersServer', 'UserAccountsServer', 'Server'), array_keys($server->getIfaces()));
+
+        $server->setGroupByTag(false);
+        $ifaces = $server->getIfaces();
+        $this->assertSame(array('Server'), array_keys($ifaces));
+        $this->assertCount(3, $ifaces['Server']->getFuncs());
+    }
+}
\ No newline at end of file
@@ -0,0 +1,180 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\Proto;
+
+
+use Swaggest\GoCodeBuilder\Avro\Builder as AvroBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Proto\Builder;
+use Swaggest\GoCodeBuilder\Proto\Converters;
+use Swaggest\GoCodeBuilder\Proto\LockFile;
+use Swaggest\GoCodeBuilder\Proto\ProtoFile;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\JsonSchema\Schema;
+
+class BuilderTest extends \PHPUnit_Framework_TestCase
+{
+    public function testBuilder()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/avro-users.avsc'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $avroBuilder = new AvroBuilder($document, $goBuilder);
+        $avroBuilder->build();
+
+        $dir = __DIR__ . '/../../../resources/go/avro';
+        $lockPath = $dir . '/pb/users.lock.json';
+        $builder = new Builder($goBuilder, 'com.example.users', 'test/avro/pb', LockFile::load($lockPath));
+        $builder->build();
+        $builder->lockFile->save($lockPath);
+
+        $protoPath = $dir . '/pb/users.proto';
+        file_put_contents($protoPath, (new ProtoFile($builder))->render());
+
+        $goFile = new GoFile('avro');
+        $goFile->fileComment = '';
+        $goFile->getCode()->addSnippet(new Converters($builder));
+
+        $filePath = $dir . '/users_proto.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $lockPath . ' ' . $protoPath . ' ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testOneof()
+    {
+        $document = json_decode(<<<'JSON'
+[
+  {"type": "record", "name": "Cat", "fields": [{"name": "lives", "type": "int"}]},
+  {"type": "record", "name": "Dog", "fields": [{"name": "name", "type": "string"}]},
+  {"type": "record", "name": "Pet", "fields": [{"name": "kind", "type": ["Cat", "Dog"]}]}
+]
+JSON
+        );
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $avroBuilder = new AvroBuilder($document, $goBuilder);
+        $avroBuilder->build();
+
+        $builder = new Builder($goBuilder, 'pets', 'example.com/pets/pb');
+        $builder->build();
+
+        $union = null;
+        foreach ($builder->getMessages() as $message) {
+            if (isset($message->fields['cat']) && $message->fields['cat']->oneof !== null) {
+                $union = $message;
+            }
+        }
+        $this->assertNotNull($union);
+
+        $proto = (new ProtoFile($builder))->render();
+        $this->assertContains(<<<PROTO
+message {$union->name} {
+  oneof one_of {
+    Cat cat = 1;
+    Dog dog = 2;
+  }
+}
+PROTO
+            , $proto);
+
+        $goFile = new GoFile('pets');
+        $goFile->getCode()->addSnippet(new Converters($builder));
+        $code = $goFile->render();
+
+        $this->assertContains(<<<GO
+	switch {
+	case base.Cat != nil:
+		item := &pb.{$union->name}_Cat{}
+		item.Cat = base.Cat.MapTo()
+		result.OneOf = item
+GO
+            , $code);
+        $this->assertContains(<<<GO
+	switch v := derived.OneOf.(type) {
+	case *pb.{$union->name}_Cat:
+		base.Cat = new(Cat)
+		base.Cat.LoadFrom(v.Cat)
+GO
+            , $code);
+    }
+
+    public function testUnsupportedType()
+    {
+        $schema = Schema::import(json_decode('{"type": "object", "properties": {"id": {"type": "string"}, "payload": {}}}'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->getType($schema);
+
+        $builder = new Builder($goBuilder, 'items', 'example.com/items/pb');
+
+        $this->setExpectedException('Swaggest\GoCodeBuilder\Proto\Exception',
+            'Failed to map Untitled1.Payload: unsupported type interface{}');
+        $builder->build();
+    }
+
+    public function testTimestamp()
+    {
+        $schema = Schema::import(json_decode('{"type": "object", "properties": {"createdAt": {"type": "string", "format": "date-time"}}}'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->getType($schema);
+
+        $builder = new Builder($goBuilder, 'items', 'example.com/items/pb');
+        $builder->build();
+
+        $proto = (new ProtoFile($builder))->render();
+        $this->assertContains('import "google/protobuf/timestamp.proto";' . "\n", $proto);
+        $this->assertContains('  google.protobuf.Timestamp created_at = 1;' . "\n", $proto);
+
+        $goFile = new GoFile('items');
+        $goFile->getCode()->addSnippet(new Converters($builder));
+        $code = $goFile->render();
+
+        $this->assertContains('"google.golang.org/protobuf/types/known/timestamppb"', $code);
+        $this->assertContains('timestamppb.New(', $code);
+        $this->assertContains('.AsTime()', $code);
+    }
+
+    public function testLockFile()
+    {
+        $lockFile = LockFile::fromData(array(
+            'messages' => array('User' => array('id' => 1, 'email' => 2), 'Big' => array('a' => 18999)),
+        ));
+
+        $this->assertSame(1, $lockFile->fieldNumber('User', 'id'));
+        $this->assertSame(3, $lockFile->fieldNumber('User', 'name'));
+        $this->assertSame(array('email' => 2), $lockFile->reservedFields('User', array('id', 'name')));
+        $this->assertSame(20000, $lockFile->fieldNumber('Big', 'b'));
+        $this->assertSame(1, $lockFile->valueNumber('Status', 'STATUS_ACTIVE'));
+        $this->assertSame(1, $lockFile->fieldNumber('Order', 'id'));
+
+        $this->assertSame(
+            '{"messages":{"User":{"id":1,"email":2,"name":3},"Big":{"a":18999,"b":20000},"Order":{"id":1}},'
+            . '"enums":{"Status":{"STATUS_ACTIVE":1}}}',
+            json_encode($lockFile->toData())
+        );
+    }
+
+    public function testNames()
+    {
+        $this->assertSame('external_id', Builder::snakeCase('externalId'));
+        $this->assertSame('http_status', Builder::snakeCase('HTTPStatus'));
+        $this->assertSame('one_of0', Builder::snakeCase('OneOf0'));
+        $this->assertSame('user_name', Builder::snakeCase('user-name'));
+
+        $this->assertSame('ExternalId', Builder::goCamelCase('external_id'));
+        $this->assertSame('OneOf0', Builder::goCamelCase('one_of0'));
+        $this->assertSame('A_1', Builder::goCamelCase('a_1'));
+        $this->assertSame('XFoo', Builder::goCamelCase('_foo'));
+    }
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\Sample;
+
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\Sample\Builder;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class BuilderTest extends \PHPUnit_Framework_TestCase
+{
+    public function testBuilder()
+    {
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($goBuilder);
+        $builder->addJsonLines(file_get_contents(__DIR__ . '/../../../resources/sample-orders.jsonl'));
+        $type = $builder->build('order');
+        $this->assertSame('Order', $type->getTypeString());
+
+        $goFile = new GoFile('sample');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+
+        $filePath = __DIR__ . '/../../../resources/go/sample/orders.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testSchemaData()
+    {
+        $builder = new Builder();
+        $builder->enumMaxValues = 2;
+        $builder->enumMinSamples = 3;
+        $builder->enumMinRepeatRatio = 0.25;
+        $builder->addJson('{"a": 1, "b": "x", "c": [1, 2.5], "d": "p", "e": null}');
+        $builder->addJson('{"a": 2, "b": "y", "c": [], "d": "q", "e": null}');
+        $builder->addJson('{"a": "3", "b": "x", "d": "r"}');
+
+        $this->assertSame(
+            array(
+                'type' => 'object',
+                'properties' => array(
+                    'a' => array('type' => array('integer', 'string')),
+                    'b' => array('type' => 'string', 'enum' => array('x', 'y')),
+                    'c' => array('type' => 'array', 'items' => array('type' => 'number')),
+                    'd' => array('type' => 'string'),
+                    'e' => array(),
+                ),
+                'required' => array('a', 'b', 'd'),
+            ),
+            json_decode(json_encode($builder->getSchemaData()), true)
+        );
+    }
+
+    public function testEnumSamples()
+    {
+        $builder = new Builder();
+        $builder->addJsonLines(file_get_contents(__DIR__ . '/../../../resources/sample-orders.jsonl'));
+        $schema = $builder->getSchemaData();
+
+        // Status has 2 values in 10 samples, sku has 9 values in 10 samples with a single repeated value.
+        $this->assertSame(array('paid', 'sent'), $schema->properties->status->enum);
+        $this->assertSame('string', $schema->properties->items->items->properties->sku->type);
+        $this->assertFalse(isset($schema->properties->items->items->properties->sku->enum));
+
+        $builder->enumMinSamples = 11;
+        $schema = $builder->getSchemaData();
+        $this->assertFalse(isset($schema->properties->status->enum));
+    }
+
+    public function testInvalidJsonLines()
+    {
+        $this->setExpectedException('Swaggest\GoCodeBuilder\Sample\Exception', 'Invalid JSON at line 3: Syntax error');
+        $builder = new Builder();
+        $builder->addJsonLines("{\"a\": 1}\n\n{\"a\":");
+    }
+}
\ No newline at end of file
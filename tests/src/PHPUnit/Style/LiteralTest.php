@@ -0,0 +1,21 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\Style;
+
+
+use Swaggest\GoCodeBuilder\Style\Literal;
+
+class LiteralTest extends \PHPUnit_Framework_TestCase
+{
+    public function testQuote()
+    {
+        $this->assertSame('"user/{userId}/\"signup\"\\n"', Literal::quote("user/{userId}/\"signup\"\n"));
+        $this->assertSame('"café"', Literal::quote('café'));
+    }
+
+    public function testInvalidUtf8()
+    {
+        $this->setExpectedException('Swaggest\GoCodeBuilder\Exception', 'Failed to quote Go string: Malformed UTF-8 characters, possibly incorrectly encoded');
+        Literal::quote("\xB1\x31");
+    }
+}
@@ -0,0 +1,171 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit\Swagger2;
+
+
+use Swaggest\GoCodeBuilder\JsonSchema\GoBuilder;
+use Swaggest\GoCodeBuilder\JsonSchema\Registry;
+use Swaggest\GoCodeBuilder\OpenAPI3\Client;
+use Swaggest\GoCodeBuilder\OpenAPI3\Server;
+use Swaggest\GoCodeBuilder\Swagger2\Builder;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+
+class BuilderTest extends \PHPUnit_Framework_TestCase
+{
+    public function testBuilder()
+    {
+        $document = json_decode(file_get_contents(__DIR__ . '/../../../resources/swagger2-pets.json'));
+
+        $goBuilder = new GoBuilder();
+        $goBuilder->options->enableXNullable = true;
+        $goBuilder->options->defaultAdditionalProperties = false;
+        $goBuilder->options->validateRequired = false;
+
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $registry = new Registry($goBuilder);
+        $builder->register($registry);
+
+        $goFile = new GoFile('swagger2');
+        $goFile->fileComment = '';
+        foreach ($goBuilder->getGeneratedStructs() as $generatedStruct) {
+            $goFile->getCode()->addSnippet($generatedStruct->structDef);
+        }
+        $goFile->getCode()->addSnippet($goBuilder->getCode());
+        $goFile->getCode()->addSnippet($builder->getCode());
+        $goFile->getCode()->addSnippet(new Client($builder));
+        $goFile->getCode()->addSnippet(new Server($builder));
+        $goFile->getCode()->addSnippet($registry);
+
+        $filePath = __DIR__ . '/../../../resources/go/swagger2/pets.go';
+        file_put_contents($filePath, $goFile->render());
+
+        exec('git diff ' . $filePath, $out);
+        $out = implode("\n", $out);
+        $this->assertSame('', $out, "Generated files changed");
+    }
+
+    public function testParameters()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "swagger": "2.0",
+  "info": {"title": "Files", "version": "1.0.0"},
+  "paths": {
+    "/files": {
+      "post": {
+        "operationId": "createFile",
+        "consumes": ["multipart/form-data", "application/x-www-form-urlencoded"],
+        "parameters": [
+          {"name": "name", "in": "formData", "type": "string"},
+          {"name": "lines", "in": "query", "type": "array", "items": {"type": "integer"}, "collectionFormat": "tsv"},
+          {"name": "words", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "ssv"}
+        ],
+        "responses": {"204": {"description": "Created"}}
+      }
+    }
+  }
+}
+JSON
+        );
+
+        $builder = new Builder($document);
+        $builder->build();
+
+        $operations = $builder->getOperations();
+        $this->assertCount(1, $operations);
+        $this->assertFalse($operations[0]->isMultipart());
+        $this->assertSame('application/x-www-form-urlencoded', $operations[0]->bodyContentType);
+
+        $parameters = $operations[0]->parametersIn('query');
+        $this->assertSame("\t", $parameters[0]->delimiter);
+        $this->assertSame(' ', $parameters[1]->delimiter);
+        $this->assertFalse($parameters[1]->explode);
+        $this->assertSame('[]int64', $parameters[0]->type->render());
+    }
+
+    public function testRegister()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "discriminator": "petType",
+      "required": ["petType"],
+      "properties": {"petType": {"type": "string"}}
+    },
+    "Cat": {
+      "allOf": [
+        {"$ref": "#/definitions/Pet"},
+        {"type": "object", "properties": {"huntingSkill": {"type": "string"}}}
+      ]
+    },
+    "Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+  }
+}
+JSON
+        );
+
+        $goBuilder = new GoBuilder();
+        $builder = new Builder($document, $goBuilder);
+        $builder->build();
+
+        $registry = new Registry($goBuilder);
+        $builder->register($registry);
+
+        $this->assertSame(array('Pet', 'Cat'), array_keys($registry->getTypes()));
+    }
+
+    public function testMissingPathParameter()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {
+    "/pets/{petId}": {"get": {"operationId": "getPet", "responses": {"204": {"description": "OK"}}}}
+  }
+}
+JSON
+        );
+
+        $this->setExpectedException(
+            'Swaggest\GoCodeBuilder\OpenAPI3\Exception',
+            'Missing path parameter "petId" in GET /pets/{petId}'
+        );
+        (new Builder($document))->build();
+    }
+
+    public function testUnknownCollectionFormat()
+    {
+        $document = json_decode(<<<'JSON'
+{
+  "swagger": "2.0",
+  "info": {"title": "Files", "version": "1.0.0"},
+  "paths": {
+    "/files": {
+      "get": {
+        "operationId": "findFiles",
+        "parameters": [
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "semicolon"}
+        ],
+        "responses": {"204": {"description": "OK"}}
+      }
+    }
+  }
+}
+JSON
+        );
+
+        $this->setExpectedException(
+            'Swaggest\GoCodeBuilder\Swagger2\Exception',
+            'Unsupported collectionFormat "semicolon" of parameter "tags" in GET /files'
+        );
+        (new Builder($document))->build();
+    }
+}
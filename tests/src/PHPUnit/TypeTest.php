@@ -4,8 +4,20 @@ namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
 
 
 use Swaggest\GoCodeBuilder\Import;
+use Swaggest\GoCodeBuilder\Templates\Code;
+use Swaggest\GoCodeBuilder\Templates\Func\Argument;
+use Swaggest\GoCodeBuilder\Templates\Func\FuncDef;
+use Swaggest\GoCodeBuilder\Templates\Func\Result;
+use Swaggest\GoCodeBuilder\Templates\GoFile;
+use Swaggest\GoCodeBuilder\Templates\Iface\IfaceDef;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructDef;
+use Swaggest\GoCodeBuilder\Templates\Struct\StructProperty;
+use Swaggest\GoCodeBuilder\Templates\Type\Pointer;
 use Swaggest\GoCodeBuilder\Templates\Type\Slice;
 use Swaggest\GoCodeBuilder\Templates\Type\Type;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeParam;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeParams;
+use Swaggest\GoCodeBuilder\Templates\Type\TypeUtil;
 
 class TypeTest extends \PHPUnit_Framework_TestCase
 {
@@ -23,4 +35,81 @@ class TypeTest extends \PHPUnit_Framework_TestCase
         );
     }
 
+    public function testFromString()
+    {
+        $cases = array(
+            'example.com/paging.Page[example.com/orders.Order]' => 'Generic',
+            'Result[*example.com/orders.Order, error]' => 'Generic',
+            'map[string]Page[[]int]' => 'Map',
+            '[16]byte' => 'ArrayType',
+            '[Size][]string' => 'ArrayType',
+            'chan int' => 'Chan',
+            '<-chan example.com/orders.Order' => 'Chan',
+            'chan<- []byte' => 'Chan',
+            'chan (<-chan int)' => 'Chan',
+            '~int | ~int64 | float64' => 'Union',
+        );
+
+        foreach ($cases as $typeString => $class) {
+            $type = TypeUtil::fromString($typeString);
+            $this->assertInstanceOf('Swaggest\\GoCodeBuilder\\Templates\\Type\\' . $class, $type, $typeString);
+            $this->assertSame($typeString, $type->getTypeString());
+            $this->assertTrue(TypeUtil::equals($type, TypeUtil::fromString($type->getTypeString())), $typeString);
+        }
+
+        $this->assertFalse(TypeUtil::equals(TypeUtil::fromString('Page[int]'), TypeUtil::fromString('Page[string]')));
+        $this->assertFalse(TypeUtil::equals(TypeUtil::fromString('[4]int'), TypeUtil::fromString('[8]int')));
+        $this->assertFalse(TypeUtil::equals(TypeUtil::fromString('<-chan int'), TypeUtil::fromString('chan int')));
+        $this->assertFalse(TypeUtil::equals(TypeUtil::fromString('~int'), TypeUtil::fromString('int | string')));
+    }
+
+    public function testGenerics()
+    {
+        $typeParams = (new TypeParams())
+            ->add(new TypeParam('T'))
+            ->add(new TypeParam('N', TypeUtil::fromString('~int | ~int64')));
+
+        $struct = new StructDef('Page');
+        $struct->setTypeParams($typeParams);
+        $struct->addProperty(new StructProperty('Items', new Slice(new Type('T'))));
+        $struct->addProperty(new StructProperty('Total', new Type('N')));
+        $struct->addProperty(new StructProperty('Next', TypeUtil::fromString('<-chan example.com/paging.Cursor[T]')));
+
+        $func = new FuncDef('Len');
+        $func->setSelf(new Argument('p', new Pointer($struct->getType())));
+        $func->setResult((new Result())->add(null, new Type('N')));
+        $func->setBody(new Code('return N(len(p.Items))'));
+        $struct->addFunc($func);
+
+        $iface = new IfaceDef('Number');
+        $iface->addType(TypeUtil::fromString('~int | ~float64'));
+
+        $goFile = new GoFile('sample');
+        $goFile->fileComment = '';
+        $goFile->getCode()->addSnippet($iface)->addSnippet($struct);
+
+        $this->assertSame(<<<GO
+package sample
+
+import (
+	"example.com/paging"
+)
+
+type Number interface {
+	~int | ~float64
+}
+
+type Page[T any, N ~int | ~int64] struct {
+	Items []T
+	Total N
+	Next  <-chan paging.Cursor[T]
+}
+
+func (p *Page[T, N]) Len() N {
+	return N(len(p.Items))
+}
+
+GO
+            , $goFile->render());
+    }
 }
\ No newline at end of file
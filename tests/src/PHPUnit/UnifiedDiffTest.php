@@ -0,0 +1,101 @@
+<?php
+
+namespace Swaggest\GoCodeBuilder\Tests\PHPUnit;
+
+
+use Swaggest\GoCodeBuilder\Changeset;
+use Swaggest\GoCodeBuilder\UnifiedDiff;
+
+class UnifiedDiffTest extends \PHPUnit_Framework_TestCase
+{
+    public function testDiff()
+    {
+        $diff = new UnifiedDiff();
+
+        $this->assertSame('', $diff->diff("a\nb\n", "a\nb\n"));
+
+        $this->assertSame(<<<'DIFF'
+--- a/entities.go
++++ b/entities.go
+@@ -1,4 +1,5 @@
+ a
+-b
++B
+ c
+ d
++e
+
+DIFF
+            , $diff->diff("a\nb\nc\nd\n", "a\nB\nc\nd\ne\n", 'a/entities.go', 'b/entities.go'));
+
+        $this->assertSame(<<<'DIFF'
+--- a
++++ b
+@@ -1,3 +1,4 @@
++x
+ 1
+ 2
+ 3
+@@ -6,5 +7,5 @@
+ 6
+ 7
+ 8
+-9
++nine
+ 10
+
+DIFF
+            , $diff->diff("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "x\n1\n2\n3\n4\n5\n6\n7\n8\nnine\n10\n"));
+
+        $this->assertSame(<<<'DIFF'
+--- /dev/null
++++ b/doc.go
+@@ -0,0 +1,2 @@
++// Package sample.
++package sample
+
+DIFF
+            , $diff->diff('', "// Package sample.\npackage sample\n", '/dev/null', 'b/doc.go'));
+
+        $this->assertSame(<<<'DIFF'
+--- a
++++ b
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+
+DIFF
+            , $diff->diff("a\nb", "a\nb\n"));
+    }
+
+    public function testLargeDiff()
+    {
+        $old = '';
+        $new = '';
+        for ($i = 0; $i < 3000; ++$i) {
+            $old .= "old {$i}\n";
+            $new .= "new {$i}\n";
+        }
+
+        $diff = new UnifiedDiff();
+        $result = $diff->diff("same\n" . $old, "same\n" . $new);
+        $this->assertSame(3000, substr_count($result, "\n-old "));
+        $this->assertSame(3000, substr_count($result, "\n+new "));
+    }
+
+    public function testChangeset()
+    {
+        $changeset = new Changeset();
+        $this->assertTrue($changeset->isEmpty());
+
+        $changeset->modified['entities.go'] = "--- a/entities.go\n";
+        $changeset->added['doc.go'] = "--- /dev/null\n";
+        $changeset->removed['old.go'] = "--- a/old.go\n";
+
+        $this->assertFalse($changeset->isEmpty());
+        $this->assertSame("A doc.go\nM entities.go\nD old.go", $changeset->summary());
+        $this->assertSame("--- /dev/null\n--- a/entities.go\n--- a/old.go\n", $changeset->diff());
+    }
+}
\ No newline at end of file